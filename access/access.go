@@ -0,0 +1,96 @@
+// Package access restricts routes by client IP address, and resolves the client IP
+// address of requests forwarded by trusted proxies
+package access
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Networks is a set of IP networks
+type Networks []*net.IPNet
+
+// ParseNetworks parses CIDR notations (e.g. 10.0.0.0/8) or single IP addresses
+func ParseNetworks(cidrs []string) (Networks, error) {
+	networks := make(Networks, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, errors.Errorf("invalid IP address %s", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CIDR %s", cidr)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// Contains returns whether ip is in any of the networks
+func (n Networks) Contains(ip net.IP) bool {
+	for _, network := range n {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// List restricts access by client IP address. Denied networks take precedence over
+// allowed networks, and an empty allow list allows every address not denied
+type List struct {
+	Allow Networks
+	Deny  Networks
+}
+
+// Empty returns whether the list allows every address
+func (l List) Empty() bool {
+	return len(l.Allow) == 0 && len(l.Deny) == 0
+}
+
+// Allowed returns whether ip may access
+func (l List) Allowed(ip net.IP) bool {
+	if ip == nil {
+		return l.Empty()
+	}
+	if l.Deny.Contains(ip) {
+		return false
+	}
+	return len(l.Allow) == 0 || l.Allow.Contains(ip)
+}
+
+// Filter responds with 403 to requests from clients not allowed by list.
+// RealIP should be installed before this middleware when running behind proxies
+func Filter(list List, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if list.Empty() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := service.ClientIP(r)
+			if !list.Allowed(net.ParseIP(ip)) {
+				service.Logger(r, logger).Info("client IP is not allowed", zap.String("client-ip", ip))
+				response.WriteError(w, r, response.ErrForbidden().AddMessages("Your IP address is not allowed to access this resource"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package access
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func mustParse(t *testing.T, cidrs ...string) Networks {
+	n, err := ParseNetworks(cidrs)
+	require.NoError(t, err)
+	return n
+}
+
+func TestParseNetworks(t *testing.T) {
+	n := mustParse(t, "10.0.0.0/8", "192.0.2.1", "2001:db8::/32", "::1")
+	require.Len(t, n, 4)
+
+	require.True(t, n.Contains(net.ParseIP("10.1.2.3")))
+	require.True(t, n.Contains(net.ParseIP("192.0.2.1")))
+	require.False(t, n.Contains(net.ParseIP("192.0.2.2")))
+	require.True(t, n.Contains(net.ParseIP("2001:db8::1")))
+	require.True(t, n.Contains(net.ParseIP("::1")))
+	require.False(t, n.Contains(net.ParseIP("::2")))
+
+	for _, invalid := range []string{"10.0.0.0/33", "example.com", ""} {
+		_, err := ParseNetworks([]string{invalid})
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestListAllowed(t *testing.T) {
+	var empty List
+	require.True(t, empty.Allowed(net.ParseIP("192.0.2.1")))
+
+	allow := List{Allow: mustParse(t, "10.0.0.0/8")}
+	require.True(t, allow.Allowed(net.ParseIP("10.0.0.1")))
+	require.False(t, allow.Allowed(net.ParseIP("192.0.2.1")))
+	require.False(t, allow.Allowed(nil))
+
+	deny := List{Deny: mustParse(t, "192.0.2.0/24")}
+	require.True(t, deny.Allowed(net.ParseIP("10.0.0.1")))
+	require.False(t, deny.Allowed(net.ParseIP("192.0.2.1")))
+
+	both := List{
+		Allow: mustParse(t, "10.0.0.0/8"),
+		Deny:  mustParse(t, "10.0.0.0/24"),
+	}
+	require.True(t, both.Allowed(net.ParseIP("10.1.0.1")))
+	require.False(t, both.Allowed(net.ParseIP("10.0.0.1")))
+}
+
+func serve(h http.Handler, remoteAddr string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "http://example.com/", nil)
+	req.RemoteAddr = remoteAddr
+	for k, values := range header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestFilter(t *testing.T) {
+	list := List{Allow: mustParse(t, "10.0.0.0/8")}
+	h := Filter(list, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	require.Equal(t, http.StatusNoContent, serve(h, "10.0.0.1:1234", nil).Code)
+	require.Equal(t, http.StatusForbidden, serve(h, "192.0.2.1:1234", nil).Code)
+	// headers are ignored without RealIP
+	require.Equal(t, http.StatusForbidden, serve(h, "192.0.2.1:1234", http.Header{
+		HeaderForwardedFor: {"10.0.0.1"},
+	}).Code)
+}
+
+func TestRealIP(t *testing.T) {
+	var got string
+	h := RealIP(mustParse(t, "127.0.0.1", "172.16.0.0/12"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.RemoteAddr
+	}))
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		header     http.Header
+		expected   string
+	}{
+		{
+			name:       "untrusted remote ignores headers",
+			remoteAddr: "192.0.2.1:1234",
+			header:     http.Header{HeaderForwardedFor: {"10.0.0.1"}},
+			expected:   "192.0.2.1:1234",
+		},
+		{
+			name:       "trusted remote without headers",
+			remoteAddr: "127.0.0.1:1234",
+			expected:   "127.0.0.1:1234",
+		},
+		{
+			name:       "single forwarded address",
+			remoteAddr: "127.0.0.1:1234",
+			header:     http.Header{HeaderForwardedFor: {"192.0.2.1"}},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "spoofed addresses before the client are skipped",
+			remoteAddr: "127.0.0.1:1234",
+			header:     http.Header{HeaderForwardedFor: {"10.0.0.1, 192.0.2.1, 172.16.0.1"}},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "multiple headers",
+			remoteAddr: "127.0.0.1:1234",
+			header:     http.Header{HeaderForwardedFor: {"10.0.0.1", "192.0.2.1"}},
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "invalid address stops at the last valid hop",
+			remoteAddr: "127.0.0.1:1234",
+			header:     http.Header{HeaderForwardedFor: {"garbage, 172.16.0.1"}},
+			expected:   "172.16.0.1",
+		},
+		{
+			name:       "real ip header",
+			remoteAddr: "127.0.0.1:1234",
+			header:     http.Header{HeaderRealIP: {"2001:db8::1"}},
+			expected:   "2001:db8::1",
+		},
+		{
+			name:       "forwarded for takes precedence",
+			remoteAddr: "127.0.0.1:1234",
+			header: http.Header{
+				HeaderRealIP:       {"192.0.2.2"},
+				HeaderForwardedFor: {"192.0.2.1"},
+			},
+			expected: "192.0.2.1",
+		},
+	}
+
+	for _, tc := range cases {
+		serve(h, tc.remoteAddr, tc.header)
+		require.Equal(t, tc.expected, got, tc.name)
+	}
+}
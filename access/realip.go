@@ -0,0 +1,58 @@
+package access
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/zllovesuki/b/service"
+)
+
+const (
+	HeaderForwardedFor = "X-Forwarded-For"
+	HeaderRealIP       = "X-Real-IP"
+)
+
+// RealIP replaces the remote address of requests from trusted proxies with the client IP
+// address in X-Forwarded-For, or X-Real-IP if absent. Addresses in X-Forwarded-For are
+// read from right to left, skipping trusted proxies, such that clients cannot spoof their
+// address by sending the header themselves. Requests from other addresses are untouched.
+// This middleware should be installed before any middleware using the client IP address
+func RealIP(trusted Networks) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := net.ParseIP(service.ClientIP(r)); ip != nil && trusted.Contains(ip) {
+				if client := forwardedIP(r, trusted); client != nil {
+					r.RemoteAddr = client.String()
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func forwardedIP(r *http.Request, trusted Networks) net.IP {
+	var hops []string
+	for _, v := range r.Header.Values(HeaderForwardedFor) {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	if len(hops) == 0 {
+		return net.ParseIP(strings.TrimSpace(r.Header.Get(HeaderRealIP)))
+	}
+
+	var client net.IP
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			break
+		}
+		client = ip
+		if !trusted.Contains(ip) {
+			break
+		}
+	}
+	return client
+}
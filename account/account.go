@@ -0,0 +1,181 @@
+// Package account stores users, their API keys, and the ownership of saved items in an app.Backend
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	userPrefix = "user-"
+	// MinPasswordLength is the shortest password accepted
+	MinPasswordLength = 8
+	// maxPasswordLength is the longest password bcrypt can hash
+	maxPasswordLength = 72
+)
+
+var (
+	ErrInvalidUsername    = errors.New("username must be 1 to 64 alphanumeric characters")
+	ErrInvalidPassword    = errors.Errorf("password must be %d to %d bytes long", MinPasswordLength, maxPasswordLength)
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidSubject     = errors.New("issuer and subject are required")
+)
+
+var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9]{1,64}$`)
+
+// User is an account which can own saved items. The username is the ID of the user
+type User struct {
+	ID       string    `json:"id"`
+	Created  time.Time `json:"created"`
+	Password []byte    `json:"password,omitempty"`
+	// Issuer is the identity provider authenticating the user, or empty for local users
+	Issuer string `json:"issuer,omitempty"`
+	// Subject identifies the user at the issuer
+	Subject string `json:"subject,omitempty"`
+	// Name is the display name given by the issuer when the user was provisioned
+	Name string `json:"name,omitempty"`
+}
+
+// Store manages users, API keys and ownership records in a backend
+type Store struct {
+	backend app.RemovableBackend
+	// cost of hashing passwords
+	cost int
+}
+
+func NewStore(backend app.RemovableBackend) (*Store, error) {
+	if backend == nil {
+		return nil, errors.New("missing backend")
+	}
+	return &Store{
+		backend: backend,
+		cost:    bcrypt.DefaultCost,
+	}, nil
+}
+
+// CreateUser creates a user with the password, returning app.ErrConflict if the username is taken
+func (s *Store) CreateUser(c context.Context, username, password string) (*User, error) {
+	if !usernameRegex.MatchString(username) {
+		return nil, ErrInvalidUsername
+	}
+	if len(password) < MinPasswordLength || len(password) > maxPasswordLength {
+		return nil, ErrInvalidPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing password")
+	}
+	u := &User{
+		ID:       username,
+		Created:  time.Now().UTC().Truncate(time.Second),
+		Password: hash,
+	}
+	buf, err := json.Marshal(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding user")
+	}
+	if err := s.backend.SaveTTL(c, userPrefix+u.ID, buf, 0); err != nil {
+		return nil, err
+	}
+	u.Password = nil
+	return u, nil
+}
+
+// User returns the user, or app.ErrNotFound if it does not exist
+func (s *Store) User(c context.Context, id string) (*User, error) {
+	if !usernameRegex.MatchString(id) {
+		return nil, app.ErrNotFound
+	}
+	buf, err := s.backend.Retrieve(c, userPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	var u User
+	if err := json.Unmarshal(buf, &u); err != nil {
+		return nil, errors.Wrap(err, "decoding user")
+	}
+	return &u, nil
+}
+
+// Authenticate returns the user if the password matches, or ErrInvalidCredentials otherwise
+func (s *Store) Authenticate(c context.Context, username, password string) (*User, error) {
+	u, err := s.User(c, username)
+	if errors.Is(err, app.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword(u.Password, []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	u.Password = nil
+	return u, nil
+}
+
+// ListUsers returns a page of users with username starting with prefix
+func (s *Store) ListUsers(c context.Context, prefix, cursor string, limit int) ([]User, string, error) {
+	items, next, err := s.backend.List(c, userPrefix+prefix, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	users := make([]User, 0, len(items))
+	for _, item := range items {
+		u, err := s.User(c, strings.TrimPrefix(item.ID, userPrefix))
+		if errors.Is(err, app.ErrNotFound) {
+			// removed since listing
+			continue
+		} else if err != nil {
+			return nil, "", err
+		}
+		u.Password = nil
+		users = append(users, *u)
+	}
+	return users, next, nil
+}
+
+// DeleteUser removes the user, its API keys and sessions. Items owned by the user are left untouched
+func (s *Store) DeleteUser(c context.Context, id string) error {
+	keys, err := s.listAll(c, keyPrefix+id+"-")
+	if err != nil {
+		return err
+	}
+	sessions, err := s.listAll(c, sessionPrefix+id+"-")
+	if err != nil {
+		return err
+	}
+	keys = append(keys, sessions...)
+	for _, key := range append(keys, userPrefix+id) {
+		if err := s.backend.Delete(c, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listAll returns keys of every record under prefix
+func (s *Store) listAll(c context.Context, prefix string) ([]string, error) {
+	var keys []string
+	cursor := ""
+	for {
+		items, next, err := s.backend.List(c, prefix, cursor, 100)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			keys = append(keys, item.ID)
+		}
+		if next == "" {
+			return keys, nil
+		}
+		cursor = next
+	}
+}
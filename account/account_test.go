@@ -0,0 +1,232 @@
+package account
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/backend"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func getFixtures(t *testing.T) (*Store, func()) {
+	b, err := backend.NewSQLiteBackend(filepath.Join(t.TempDir(), "account.db"))
+	require.NoError(t, err)
+
+	s, err := NewStore(b)
+	require.NoError(t, err)
+	s.cost = bcrypt.MinCost
+
+	return s, func() {
+		require.NoError(t, b.Close())
+	}
+}
+
+func TestUser(t *testing.T) {
+	s, finish := getFixtures(t)
+	defer finish()
+
+	ctx := context.Background()
+
+	u, err := s.CreateUser(ctx, "alice", "correct horse")
+	require.NoError(t, err)
+	require.Equal(t, "alice", u.ID)
+	require.Nil(t, u.Password)
+
+	_, err = s.CreateUser(ctx, "alice", "battery staple")
+	require.ErrorIs(t, err, app.ErrConflict)
+
+	_, err = s.CreateUser(ctx, "bob-1", "correct horse")
+	require.ErrorIs(t, err, ErrInvalidUsername)
+
+	_, err = s.CreateUser(ctx, "bob", "short")
+	require.ErrorIs(t, err, ErrInvalidPassword)
+
+	u, err = s.Authenticate(ctx, "alice", "correct horse")
+	require.NoError(t, err)
+	require.Equal(t, "alice", u.ID)
+	require.Nil(t, u.Password)
+
+	_, err = s.Authenticate(ctx, "alice", "battery staple")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = s.Authenticate(ctx, "bob", "correct horse")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = s.CreateUser(ctx, "bob", "correct horse")
+	require.NoError(t, err)
+
+	users, _, err := s.ListUsers(ctx, "", "", 10)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	for _, u := range users {
+		require.Nil(t, u.Password)
+	}
+
+	require.NoError(t, s.DeleteUser(ctx, "bob"))
+	_, err = s.User(ctx, "bob")
+	require.ErrorIs(t, err, app.ErrNotFound)
+}
+
+func TestKey(t *testing.T) {
+	s, finish := getFixtures(t)
+	defer finish()
+
+	ctx := context.Background()
+
+	_, err := s.CreateUser(ctx, "alice", "correct horse")
+	require.NoError(t, err)
+
+	token, key, err := s.CreateKey(ctx, "alice", "laptop")
+	require.NoError(t, err)
+	require.Equal(t, "laptop", key.Name)
+	require.Nil(t, key.Digest)
+
+	u, err := s.VerifyKey(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, "alice", u.ID)
+
+	for _, invalid := range []string{"", "alice", "alice." + key.ID + ".wrong", "bob." + key.ID + "." + token[len("alice."+key.ID+"."):]} {
+		_, err = s.VerifyKey(ctx, invalid)
+		require.ErrorIs(t, err, ErrInvalidCredentials, invalid)
+	}
+
+	keys, err := s.ListKeys(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, key.ID, keys[0].ID)
+	require.Nil(t, keys[0].Digest)
+
+	require.NoError(t, s.DeleteKey(ctx, "alice", key.ID))
+	_, err = s.VerifyKey(ctx, token)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// keys are revoked along with the user
+	token, _, err = s.CreateKey(ctx, "alice", "phone")
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteUser(ctx, "alice"))
+	_, err = s.VerifyKey(ctx, token)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	keys, err = s.ListKeys(ctx, "alice")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func TestOwnership(t *testing.T) {
+	s, finish := getFixtures(t)
+	defer finish()
+
+	ctx := context.Background()
+
+	require.NoError(t, s.Own(ctx, "link", "abc", "alice", 0))
+	require.NoError(t, s.Own(ctx, "text", "abc", "alice", time.Hour))
+	require.NoError(t, s.Own(ctx, "link", "def", "bob", 0))
+
+	require.ErrorIs(t, s.Own(ctx, "link", "abc", "bob", 0), app.ErrConflict)
+
+	owner, err := s.Owner(ctx, "link", "abc")
+	require.NoError(t, err)
+	require.Equal(t, "alice", owner)
+
+	_, err = s.Owner(ctx, "file", "abc")
+	require.ErrorIs(t, err, app.ErrNotFound)
+
+	ids, _, err := s.Owned(ctx, "alice", "link", "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"abc"}, ids)
+
+	require.NoError(t, s.TouchOwner(ctx, "link", "abc", time.Minute))
+	require.ErrorIs(t, s.TouchOwner(ctx, "file", "abc", time.Minute), app.ErrNotFound)
+
+	require.NoError(t, s.Disown(ctx, "link", "abc"))
+	require.NoError(t, s.Disown(ctx, "link", "abc"))
+	_, err = s.Owner(ctx, "link", "abc")
+	require.ErrorIs(t, err, app.ErrNotFound)
+
+	ids, _, err = s.Owned(ctx, "alice", "link", "", 10)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	ids, _, err = s.Owned(ctx, "alice", "text", "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"abc"}, ids)
+}
+
+func TestProvision(t *testing.T) {
+	s, finish := getFixtures(t)
+	defer finish()
+
+	ctx := context.Background()
+	issuer := "https://issuer.example.com"
+
+	u, err := s.Provision(ctx, issuer, "subject", "john.doe")
+	require.NoError(t, err)
+	require.Equal(t, ProvisionedID(issuer, "subject"), u.ID)
+	require.Equal(t, issuer, u.Issuer)
+	require.Equal(t, "john.doe", u.Name)
+
+	// the same subject is the same user, regardless of the name
+	u, err = s.Provision(ctx, issuer, "subject", "jane.doe")
+	require.NoError(t, err)
+	require.Equal(t, ProvisionedID(issuer, "subject"), u.ID)
+
+	// provisioned users have no password
+	_, err = s.Authenticate(ctx, u.ID, "")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// the same name is a different user
+	other, err := s.Provision(ctx, issuer, "other", "john.doe")
+	require.NoError(t, err)
+	require.NotEqual(t, u.ID, other.ID)
+
+	other, err = s.Provision(ctx, "https://other.example.com", "subject", "john.doe")
+	require.NoError(t, err)
+	require.NotEqual(t, u.ID, other.ID)
+
+	_, err = s.CreateUser(ctx, ProvisionedID(issuer, "taken"), "correct horse")
+	require.NoError(t, err)
+	_, err = s.Provision(ctx, issuer, "taken", "")
+	require.ErrorIs(t, err, app.ErrConflict)
+
+	_, err = s.Provision(ctx, issuer, "", "john.doe")
+	require.ErrorIs(t, err, ErrInvalidSubject)
+}
+
+func TestSession(t *testing.T) {
+	s, finish := getFixtures(t)
+	defer finish()
+
+	ctx := context.Background()
+
+	_, err := s.CreateUser(ctx, "bob", "correct horse")
+	require.NoError(t, err)
+
+	token, err := s.CreateSession(ctx, "bob", []string{ScopeSave}, time.Hour)
+	require.NoError(t, err)
+
+	session, err := s.Session(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, "bob", session.User)
+	require.True(t, session.Has(ScopeSave))
+	require.False(t, session.Has(ScopeMe))
+	require.Nil(t, session.Digest)
+
+	_, err = s.Session(ctx, token+"x")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = s.Session(ctx, "garbage")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	require.NoError(t, s.DeleteSession(ctx, token))
+	_, err = s.Session(ctx, token)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	token, err = s.CreateSession(ctx, "bob", Scopes, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteUser(ctx, "bob"))
+	_, err = s.Session(ctx, token)
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
@@ -0,0 +1,120 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/pkg/errors"
+)
+
+const keyPrefix = "key-"
+
+// Key is an API key of a user. The secret part of the key is only persisted as a digest
+type Key struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Digest  []byte    `json:"digest,omitempty"`
+}
+
+// token is the API key handed to the user, in the form of {user}.{key}.{secret}
+func token(user, key, secret string) string {
+	return user + "." + key + "." + secret
+}
+
+// CreateKey creates an API key for the user, returning the token to be handed to the user.
+// The token cannot be retrieved afterward
+func (s *Store) CreateKey(c context.Context, user, name string) (string, *Key, error) {
+	buf := make([]byte, 8)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", nil, errors.Wrap(err, "generating key id")
+	}
+	secret, digest, err := service.NewSecret()
+	if err != nil {
+		return "", nil, err
+	}
+
+	k := &Key{
+		ID:      hex.EncodeToString(buf),
+		Name:    name,
+		Created: time.Now().UTC().Truncate(time.Second),
+		Digest:  digest,
+	}
+	b, err := json.Marshal(k)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "encoding key")
+	}
+	if err := s.backend.SaveTTL(c, keyPrefix+user+"-"+k.ID, b, 0); err != nil {
+		return "", nil, err
+	}
+	k.Digest = nil
+	return token(user, k.ID, secret), k, nil
+}
+
+// VerifyKey returns the owner of the API key, or ErrInvalidCredentials if the key is invalid
+func (s *Store) VerifyKey(c context.Context, apiKey string) (*User, error) {
+	parts := strings.Split(apiKey, ".")
+	if len(parts) != 3 || !usernameRegex.MatchString(parts[0]) {
+		return nil, ErrInvalidCredentials
+	}
+
+	b, err := s.backend.Retrieve(c, keyPrefix+parts[0]+"-"+parts[1])
+	if errors.Is(err, app.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+	var k Key
+	if err := json.Unmarshal(b, &k); err != nil {
+		return nil, errors.Wrap(err, "decoding key")
+	}
+	if !service.VerifySecret(parts[2], k.Digest) {
+		return nil, ErrInvalidCredentials
+	}
+
+	u, err := s.User(c, parts[0])
+	if errors.Is(err, app.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+	u.Password = nil
+	return u, nil
+}
+
+// ListKeys returns every API key of the user
+func (s *Store) ListKeys(c context.Context, user string) ([]Key, error) {
+	ids, err := s.listAll(c, keyPrefix+user+"-")
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]Key, 0, len(ids))
+	for _, id := range ids {
+		b, err := s.backend.Retrieve(c, id)
+		if errors.Is(err, app.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		var k Key
+		if err := json.Unmarshal(b, &k); err != nil {
+			return nil, errors.Wrap(err, "decoding key")
+		}
+		k.Digest = nil
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// DeleteKey revokes the API key of the user
+func (s *Store) DeleteKey(c context.Context, user, id string) error {
+	return s.backend.Delete(c, keyPrefix+user+"-"+id)
+}
@@ -0,0 +1,79 @@
+package account
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/zllovesuki/b/ratelimit"
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Middleware authenticates requests carrying an API key as a bearer token, a username
+// and password via basic authentication, or a session cookie of the web UI. Authenticated
+// requests carry the user ID in the context, retrievable via service.User, and are rate limited
+// per user instead of per IP. Anonymous requests are passed through, while invalid credentials
+// are rejected with 401. Invalid or expired session cookies are ignored, treating the request as anonymous
+func Middleware(store *Store, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				u       *User
+				session *Session
+				err     error
+			)
+			auth := r.Header.Get("Authorization")
+			if username, password, ok := r.BasicAuth(); ok {
+				u, err = store.Authenticate(r.Context(), username, password)
+			} else if strings.HasPrefix(auth, "Bearer ") {
+				u, err = store.VerifyKey(r.Context(), strings.TrimPrefix(auth, "Bearer "))
+			} else if cookie, cErr := r.Cookie(SessionCookie); cErr == nil {
+				session, err = store.Session(r.Context(), cookie.Value)
+				if errors.Is(err, ErrInvalidCredentials) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				if session != nil {
+					u = &User{ID: session.User}
+				}
+			} else {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if errors.Is(err, ErrInvalidCredentials) {
+				response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Invalid credentials"))
+				return
+			} else if err != nil {
+				service.Logger(r, logger).Error("unable to authenticate request", zap.Error(err))
+				response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to authenticate"))
+				return
+			}
+
+			ctx := service.WithUser(r.Context(), u.ID)
+			ctx = ratelimit.WithClient(ctx, "user:"+u.ID)
+			if session != nil {
+				ctx = context.WithValue(ctx, sessionCtxKey{}, session)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope rejects requests authenticated by a session lacking the scope with 403.
+// Other requests are passed through, as password and API key authentication have every scope
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if session := SessionFrom(r); session != nil && !session.Has(scope) {
+				response.WriteError(w, r, response.ErrForbidden().AddMessages("Your session is not granted the "+scope+" scope"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
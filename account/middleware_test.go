@@ -0,0 +1,127 @@
+package account
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zllovesuki/b/ratelimit"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMiddleware(t *testing.T) {
+	s, finish := getFixtures(t)
+	defer finish()
+
+	_, err := s.CreateUser(context.Background(), "alice", "correct horse")
+	require.NoError(t, err)
+	token, _, err := s.CreateKey(context.Background(), "alice", "test")
+	require.NoError(t, err)
+
+	var user, client string
+	h := Middleware(s, zaptest.NewLogger(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = service.User(r)
+		client = ratelimit.ClientKey(r)
+	}))
+
+	cases := []struct {
+		name   string
+		auth   func(r *http.Request)
+		status int
+		user   string
+	}{
+		{
+			name:   "anonymous",
+			auth:   func(r *http.Request) {},
+			status: http.StatusOK,
+		},
+		{
+			name: "password",
+			auth: func(r *http.Request) {
+				r.SetBasicAuth("alice", "correct horse")
+			},
+			status: http.StatusOK,
+			user:   "alice",
+		},
+		{
+			name: "api key",
+			auth: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+token)
+			},
+			status: http.StatusOK,
+			user:   "alice",
+		},
+		{
+			name: "wrong password",
+			auth: func(r *http.Request) {
+				r.SetBasicAuth("alice", "battery staple")
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong api key",
+			auth: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer alice.0000.nope")
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		user, client = "", ""
+		r := httptest.NewRequest("GET", "http://example.com/", nil)
+		tc.auth(r)
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, r)
+
+		require.Equal(t, tc.status, recorder.Code, tc.name)
+		require.Equal(t, tc.user, user, tc.name)
+		if tc.user != "" {
+			require.Equal(t, "client:user:"+tc.user, client, tc.name)
+		}
+	}
+}
+
+func TestMiddlewareSession(t *testing.T) {
+	s, finish := getFixtures(t)
+	defer finish()
+
+	_, err := s.CreateUser(context.Background(), "bob", "correct horse")
+	require.NoError(t, err)
+	token, err := s.CreateSession(context.Background(), "bob", []string{ScopeMe}, time.Hour)
+	require.NoError(t, err)
+
+	var user string
+	h := Middleware(s, zaptest.NewLogger(t))(RequireScope(ScopeSave)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = service.User(r)
+	})))
+
+	// session lacking the scope
+	r := httptest.NewRequest("PUT", "http://example.com/", nil)
+	r.AddCookie(&http.Cookie{Name: SessionCookie, Value: token})
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, r)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	// stale cookies are treated as anonymous
+	r = httptest.NewRequest("PUT", "http://example.com/", nil)
+	r.AddCookie(&http.Cookie{Name: SessionCookie, Value: token + "x"})
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, r)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, user)
+
+	token, err = s.CreateSession(context.Background(), "bob", Scopes, time.Hour)
+	require.NoError(t, err)
+	r = httptest.NewRequest("PUT", "http://example.com/", nil)
+	r.AddCookie(&http.Cookie{Name: SessionCookie, Value: token})
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, r)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "bob", user)
+}
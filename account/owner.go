@@ -0,0 +1,95 @@
+package account
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ownerPrefix records the owner of an item, keyed by kind and id
+	ownerPrefix = "owner-"
+	// ownedPrefix indexes the items of an owner, keyed by owner, kind and id
+	ownedPrefix = "owned-"
+)
+
+var _ service.Ownership = &Store{}
+
+func ownerKey(kind, id string) string {
+	return ownerPrefix + kind + "-" + id
+}
+
+func ownedKey(owner, kind, id string) string {
+	return ownedPrefix + owner + "-" + kind + "-" + id
+}
+
+func (s *Store) Own(c context.Context, kind, id, owner string, ttl time.Duration) error {
+	if err := s.backend.SaveTTL(c, ownerKey(kind, id), []byte(owner), ttl); err != nil {
+		return err
+	}
+	// the index may be left behind by an item owned previously
+	if err := s.backend.Delete(c, ownedKey(owner, kind, id)); err != nil {
+		return err
+	}
+	if err := s.backend.SaveTTL(c, ownedKey(owner, kind, id), []byte(id), ttl); err != nil {
+		s.backend.Delete(c, ownerKey(kind, id))
+		return errors.Wrap(err, "indexing owned item")
+	}
+	return nil
+}
+
+func (s *Store) Owner(c context.Context, kind, id string) (string, error) {
+	owner, err := s.backend.Retrieve(c, ownerKey(kind, id))
+	if err != nil {
+		return "", err
+	}
+	return string(owner), nil
+}
+
+func (s *Store) TouchOwner(c context.Context, kind, id string, ttl time.Duration) error {
+	owner, err := s.Owner(c, kind, id)
+	if err != nil {
+		return err
+	}
+	if err := s.backend.Touch(c, ownerKey(kind, id), ttl); err != nil {
+		return err
+	}
+	err = s.backend.Touch(c, ownedKey(owner, kind, id), ttl)
+	if errors.Is(err, app.ErrNotFound) {
+		// index is missing, recreate it so the item is listed again
+		return s.backend.SaveTTL(c, ownedKey(owner, kind, id), []byte(id), ttl)
+	}
+	return err
+}
+
+func (s *Store) Disown(c context.Context, kind, id string) error {
+	owner, err := s.Owner(c, kind, id)
+	if errors.Is(err, app.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := s.backend.Delete(c, ownedKey(owner, kind, id)); err != nil {
+		return err
+	}
+	return s.backend.Delete(c, ownerKey(kind, id))
+}
+
+// Owned returns a page of item IDs of the given kind owned by owner
+func (s *Store) Owned(c context.Context, owner, kind, cursor string, limit int) ([]string, string, error) {
+	prefix := ownedPrefix + owner + "-" + kind + "-"
+	items, next, err := s.backend.List(c, prefix, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, strings.TrimPrefix(item.ID, prefix))
+	}
+	return ids, next, nil
+}
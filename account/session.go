@@ -0,0 +1,167 @@
+package account
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	sessionPrefix = "session-"
+	// provisionedPrefix starts the ID of users provisioned by an issuer
+	provisionedPrefix = "oidc"
+	// SessionCookie carries the session token of the web UI
+	SessionCookie = "b_session"
+)
+
+// Scopes granted to sessions. Requests authenticated with a password or an API key have every scope
+const (
+	// ScopeSave allows saving and extending items
+	ScopeSave = "save"
+	// ScopeMe allows managing owned items via /me
+	ScopeMe = "me"
+)
+
+// Scopes lists every scope
+var Scopes = []string{ScopeSave, ScopeMe}
+
+// Session is a login of a user from the web UI, limited to the scopes granted
+type Session struct {
+	User    string    `json:"user"`
+	Scopes  []string  `json:"scopes"`
+	Created time.Time `json:"created"`
+	Digest  []byte    `json:"digest,omitempty"`
+}
+
+// Has reports whether the session is granted the scope
+func (s *Session) Has(scope string) bool {
+	for _, granted := range s.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ProvisionedID returns the ID of the user identified by subject at issuer. Unlike usernames at
+// the issuer, which users may be able to change, the pair is stable and unique
+func ProvisionedID(issuer, subject string) string {
+	sum := sha256.Sum256([]byte(issuer + "\x00" + subject))
+	return provisionedPrefix + hex.EncodeToString(sum[:20])
+}
+
+// Provision returns the user identified by subject at issuer, creating it without a password and
+// with the display name if it does not exist. Returns app.ErrConflict if the ID is taken by a local user
+func (s *Store) Provision(c context.Context, issuer, subject, name string) (*User, error) {
+	if issuer == "" || subject == "" {
+		return nil, ErrInvalidSubject
+	}
+	u := &User{
+		ID:      ProvisionedID(issuer, subject),
+		Created: time.Now().UTC().Truncate(time.Second),
+		Issuer:  issuer,
+		Subject: subject,
+		Name:    name,
+	}
+	buf, err := json.Marshal(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding user")
+	}
+	err = s.backend.SaveTTL(c, userPrefix+u.ID, buf, 0)
+	if err == nil {
+		return u, nil
+	} else if !errors.Is(err, app.ErrConflict) {
+		return nil, err
+	}
+
+	id := u.ID
+	u, err = s.User(c, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.Issuer != issuer || u.Subject != subject {
+		return nil, errors.Wrapf(app.ErrConflict, "user %s is taken", id)
+	}
+	u.Password = nil
+	return u, nil
+}
+
+// CreateSession creates a session of the user expiring after ttl, returning the token to be set as cookie
+func (s *Store) CreateSession(c context.Context, user string, scopes []string, ttl time.Duration) (string, error) {
+	secret, digest, err := service.NewSecret()
+	if err != nil {
+		return "", err
+	}
+	buf, err := json.Marshal(Session{
+		User:    user,
+		Scopes:  scopes,
+		Created: time.Now().UTC().Truncate(time.Second),
+		Digest:  digest,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "encoding session")
+	}
+	id := secret[:8]
+	if err := s.backend.SaveTTL(c, sessionPrefix+user+"-"+id, buf, ttl); err != nil {
+		return "", err
+	}
+	return user + "." + id + "." + secret, nil
+}
+
+// sessionKey returns the backend key and secret of the session token
+func sessionKey(token string) (string, string, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || !usernameRegex.MatchString(parts[0]) || !strings.HasPrefix(parts[2], parts[1]) {
+		return "", "", false
+	}
+	return sessionPrefix + parts[0] + "-" + parts[1], parts[2], true
+}
+
+// Session returns the session of the token, or ErrInvalidCredentials if the session is invalid or expired
+func (s *Store) Session(c context.Context, token string) (*Session, error) {
+	key, secret, ok := sessionKey(token)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	buf, err := s.backend.Retrieve(c, key)
+	if errors.Is(err, app.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(buf, &session); err != nil {
+		return nil, errors.Wrap(err, "decoding session")
+	}
+	if !service.VerifySecret(secret, session.Digest) {
+		return nil, ErrInvalidCredentials
+	}
+	session.Digest = nil
+	return &session, nil
+}
+
+// DeleteSession logs out the session of the token
+func (s *Store) DeleteSession(c context.Context, token string) error {
+	if _, err := s.Session(c, token); err != nil {
+		return err
+	}
+	key, _, _ := sessionKey(token)
+	return s.backend.Delete(c, key)
+}
+
+type sessionCtxKey struct{}
+
+// SessionFrom returns the session of the request, or nil if the request is not authenticated by a session
+func SessionFrom(r *http.Request) *Session {
+	session, _ := r.Context().Value(sessionCtxKey{}).(*Session)
+	return session
+}
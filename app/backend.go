@@ -13,14 +13,42 @@ import (
 var (
 	ErrNotFound = fmt.Errorf("not found")
 	ErrConflict = fmt.Errorf("conflict identifier")
+	// ErrExpired is returned instead of ErrNotFound when the backend knows the data has expired.
+	// It wraps ErrNotFound, so callers checking for ErrNotFound need not care about the distinction
+	ErrExpired = fmt.Errorf("expired: %w", ErrNotFound)
+	// ErrQuotaExceeded is returned when saving the data would exceed the storage quota
+	ErrQuotaExceeded = fmt.Errorf("storage quota exceeded")
 )
 
+// Info describes a persisted document without its payload
+type Info struct {
+	ID   string
+	Size int64
+	// Created is zero if the backend does not track creation time
+	Created time.Time
+	// Expires is zero if the document never expires
+	Expires time.Time
+}
+
 // Backend is used to store and later retrieve our documents (links, files, etc)
 type Backend interface {
 	// SaveTTL will persist the data but a defined expiration time
 	SaveTTL(c context.Context, identifier string, data []byte, ttl time.Duration) error
+	// Overwrite persists the data, replacing existing data under the identifier at once, such
+	// that readers observe either the previous or the new data
+	Overwrite(c context.Context, identifier string, data []byte, ttl time.Duration) error
 	// Retrieve gets the persisted data back
 	Retrieve(c context.Context, identifier string) ([]byte, error)
+	// Stat returns information about the persisted data without reading it.
+	// ErrNotFound (or ErrExpired) is returned if the data does not exist
+	Stat(c context.Context, identifier string) (Info, error)
+	// List returns unexpired documents with identifiers starting with prefix, resuming
+	// from cursor. The returned cursor is empty when there are no more documents.
+	// limit is a hint and backends may return fewer or more documents per page
+	List(c context.Context, prefix, cursor string, limit int) ([]Info, string, error)
+	// Touch sets the data to expire ttl from now, or never if ttl is 0.
+	// ErrNotFound (or ErrExpired) is returned if the data does not exist
+	Touch(c context.Context, identifier string, ttl time.Duration) error
 	// Close releases resources before exit
 	Close() error
 }
@@ -28,10 +56,22 @@ type Backend interface {
 // FastBackend is similar to Backend, except that it utilizes io.ReadCloser to minimize buffering
 type FastBackend interface {
 	SaveTTL(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error)
+	// Overwrite persists the data, replacing existing data under the identifier at once, such
+	// that readers observe either the previous or the new data
+	Overwrite(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error)
 	Retrieve(c context.Context, identifier string) (io.ReadCloser, error)
+	Stat(c context.Context, identifier string) (Info, error)
+	List(c context.Context, prefix, cursor string, limit int) ([]Info, string, error)
+	Touch(c context.Context, identifier string, ttl time.Duration) error
 	Close() error
 }
 
+// ExpiryNotifier is implemented by backends removing expired data lazily, such as on retrieval
+type ExpiryNotifier interface {
+	// OnExpire registers fn to be called with the identifier of expired data after the backend removes it
+	OnExpire(fn func(c context.Context, identifier string))
+}
+
 // Removable is used to remove underlying resources, usually in internal tools
 type Removable interface {
 	Delete(c context.Context, identifier string) error
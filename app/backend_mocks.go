@@ -50,6 +50,36 @@ func (mr *MockBackendMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBackend)(nil).Close))
 }
 
+// List mocks base method.
+func (m *MockBackend) List(arg0 context.Context, arg1, arg2 string, arg3 int) ([]Info, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]Info)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockBackendMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBackend)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// Overwrite mocks base method.
+func (m *MockBackend) Overwrite(arg0 context.Context, arg1 string, arg2 []byte, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overwrite", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Overwrite indicates an expected call of Overwrite.
+func (mr *MockBackendMockRecorder) Overwrite(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overwrite", reflect.TypeOf((*MockBackend)(nil).Overwrite), arg0, arg1, arg2, arg3)
+}
+
 // Retrieve mocks base method.
 func (m *MockBackend) Retrieve(arg0 context.Context, arg1 string) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +109,35 @@ func (mr *MockBackendMockRecorder) SaveTTL(arg0, arg1, arg2, arg3 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTTL", reflect.TypeOf((*MockBackend)(nil).SaveTTL), arg0, arg1, arg2, arg3)
 }
 
+// Stat mocks base method.
+func (m *MockBackend) Stat(arg0 context.Context, arg1 string) (Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", arg0, arg1)
+	ret0, _ := ret[0].(Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockBackendMockRecorder) Stat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockBackend)(nil).Stat), arg0, arg1)
+}
+
+// Touch mocks base method.
+func (m *MockBackend) Touch(arg0 context.Context, arg1 string, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockBackendMockRecorder) Touch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockBackend)(nil).Touch), arg0, arg1, arg2)
+}
+
 // MockFastBackend is a mock of FastBackend interface.
 type MockFastBackend struct {
 	ctrl     *gomock.Controller
@@ -116,6 +175,37 @@ func (mr *MockFastBackendMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockFastBackend)(nil).Close))
 }
 
+// List mocks base method.
+func (m *MockFastBackend) List(arg0 context.Context, arg1, arg2 string, arg3 int) ([]Info, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]Info)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockFastBackendMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFastBackend)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// Overwrite mocks base method.
+func (m *MockFastBackend) Overwrite(arg0 context.Context, arg1 string, arg2 io.ReadCloser, arg3 time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overwrite", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Overwrite indicates an expected call of Overwrite.
+func (mr *MockFastBackendMockRecorder) Overwrite(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overwrite", reflect.TypeOf((*MockFastBackend)(nil).Overwrite), arg0, arg1, arg2, arg3)
+}
+
 // Retrieve mocks base method.
 func (m *MockFastBackend) Retrieve(arg0 context.Context, arg1 string) (io.ReadCloser, error) {
 	m.ctrl.T.Helper()
@@ -146,6 +236,35 @@ func (mr *MockFastBackendMockRecorder) SaveTTL(arg0, arg1, arg2, arg3 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTTL", reflect.TypeOf((*MockFastBackend)(nil).SaveTTL), arg0, arg1, arg2, arg3)
 }
 
+// Stat mocks base method.
+func (m *MockFastBackend) Stat(arg0 context.Context, arg1 string) (Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", arg0, arg1)
+	ret0, _ := ret[0].(Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockFastBackendMockRecorder) Stat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockFastBackend)(nil).Stat), arg0, arg1)
+}
+
+// Touch mocks base method.
+func (m *MockFastBackend) Touch(arg0 context.Context, arg1 string, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockFastBackendMockRecorder) Touch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockFastBackend)(nil).Touch), arg0, arg1, arg2)
+}
+
 // MockRemovable is a mock of Removable interface.
 type MockRemovable struct {
 	ctrl     *gomock.Controller
@@ -234,6 +353,36 @@ func (mr *MockRemovableBackendMockRecorder) Delete(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRemovableBackend)(nil).Delete), arg0, arg1)
 }
 
+// List mocks base method.
+func (m *MockRemovableBackend) List(arg0 context.Context, arg1, arg2 string, arg3 int) ([]Info, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]Info)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockRemovableBackendMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRemovableBackend)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// Overwrite mocks base method.
+func (m *MockRemovableBackend) Overwrite(arg0 context.Context, arg1 string, arg2 []byte, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overwrite", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Overwrite indicates an expected call of Overwrite.
+func (mr *MockRemovableBackendMockRecorder) Overwrite(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overwrite", reflect.TypeOf((*MockRemovableBackend)(nil).Overwrite), arg0, arg1, arg2, arg3)
+}
+
 // Retrieve mocks base method.
 func (m *MockRemovableBackend) Retrieve(arg0 context.Context, arg1 string) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -263,6 +412,35 @@ func (mr *MockRemovableBackendMockRecorder) SaveTTL(arg0, arg1, arg2, arg3 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTTL", reflect.TypeOf((*MockRemovableBackend)(nil).SaveTTL), arg0, arg1, arg2, arg3)
 }
 
+// Stat mocks base method.
+func (m *MockRemovableBackend) Stat(arg0 context.Context, arg1 string) (Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", arg0, arg1)
+	ret0, _ := ret[0].(Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockRemovableBackendMockRecorder) Stat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockRemovableBackend)(nil).Stat), arg0, arg1)
+}
+
+// Touch mocks base method.
+func (m *MockRemovableBackend) Touch(arg0 context.Context, arg1 string, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockRemovableBackendMockRecorder) Touch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockRemovableBackend)(nil).Touch), arg0, arg1, arg2)
+}
+
 // MockRemovableFastBackend is a mock of RemovableFastBackend interface.
 type MockRemovableFastBackend struct {
 	ctrl     *gomock.Controller
@@ -314,6 +492,37 @@ func (mr *MockRemovableFastBackendMockRecorder) Delete(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRemovableFastBackend)(nil).Delete), arg0, arg1)
 }
 
+// List mocks base method.
+func (m *MockRemovableFastBackend) List(arg0 context.Context, arg1, arg2 string, arg3 int) ([]Info, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]Info)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockRemovableFastBackendMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRemovableFastBackend)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// Overwrite mocks base method.
+func (m *MockRemovableFastBackend) Overwrite(arg0 context.Context, arg1 string, arg2 io.ReadCloser, arg3 time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overwrite", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Overwrite indicates an expected call of Overwrite.
+func (mr *MockRemovableFastBackendMockRecorder) Overwrite(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overwrite", reflect.TypeOf((*MockRemovableFastBackend)(nil).Overwrite), arg0, arg1, arg2, arg3)
+}
+
 // Retrieve mocks base method.
 func (m *MockRemovableFastBackend) Retrieve(arg0 context.Context, arg1 string) (io.ReadCloser, error) {
 	m.ctrl.T.Helper()
@@ -343,3 +552,32 @@ func (mr *MockRemovableFastBackendMockRecorder) SaveTTL(arg0, arg1, arg2, arg3 i
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTTL", reflect.TypeOf((*MockRemovableFastBackend)(nil).SaveTTL), arg0, arg1, arg2, arg3)
 }
+
+// Stat mocks base method.
+func (m *MockRemovableFastBackend) Stat(arg0 context.Context, arg1 string) (Info, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", arg0, arg1)
+	ret0, _ := ret[0].(Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockRemovableFastBackendMockRecorder) Stat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockRemovableFastBackend)(nil).Stat), arg0, arg1)
+}
+
+// Touch mocks base method.
+func (m *MockRemovableFastBackend) Touch(arg0 context.Context, arg1 string, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockRemovableFastBackendMockRecorder) Touch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockRemovableFastBackend)(nil).Touch), arg0, arg1, arg2)
+}
@@ -22,17 +22,11 @@ const (
 // WriteTTL will insert ttl info into current position of io.Writer.
 // Using this method for unified wire format is strongly preferred
 func WriteTTL(w io.Writer, ttl time.Duration) error {
-	head := make([]byte, headerSize)
-	head[versionByte] = 0
-
-	now, err := time.Now().UTC().MarshalBinary()
+	head, err := header(time.Now().UTC(), ttl)
 	if err != nil {
-		return errors.Wrap(err, "error marshalling time into binary")
+		return err
 	}
 
-	copy(head[createdStart:createdEnd], now)
-	binary.LittleEndian.PutUint64(head[ttlStart:ttlEnd], uint64(ttl))
-
 	if _, err := w.Write(head); err != nil {
 		return errors.Wrap(err, "cannot write expiration data")
 	}
@@ -40,29 +34,93 @@ func WriteTTL(w io.Writer, ttl time.Duration) error {
 	return nil
 }
 
-// TTLExceeded will read the ttl info from current position of io.Reader.
+// RewriteTTL will overwrite the ttl info at the beginning of io.WriterAt, keeping the creation time.
 // Using this method for unified wire format is strongly preferred
-func TTLExceeded(r io.Reader) (bool, error) {
+func RewriteTTL(w io.WriterAt, created time.Time, ttl time.Duration) error {
+	head, err := header(created, ttl)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.WriteAt(head, 0); err != nil {
+		return errors.Wrap(err, "cannot rewrite expiration data")
+	}
+
+	return nil
+}
+
+func header(created time.Time, ttl time.Duration) ([]byte, error) {
+	head := make([]byte, headerSize)
+	head[versionByte] = 0
+
+	when, err := created.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling time into binary")
+	}
+
+	copy(head[createdStart:createdEnd], when)
+	binary.LittleEndian.PutUint64(head[ttlStart:ttlEnd], uint64(ttl))
+
+	return head, nil
+}
+
+// ReadTTL will read the creation time and ttl info from current position of io.Reader.
+// Using this method for unified wire format is strongly preferred
+func ReadTTL(r io.Reader) (time.Time, time.Duration, error) {
 	head := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return time.Time{}, 0, errors.Wrap(err, "cannot read expiration data")
+	}
+
 	switch head[versionByte] {
 	case 0:
-		if _, err := r.Read(head); err != nil {
-			return false, errors.Wrap(err, "cannot read expiration data")
+		ttl := time.Duration(binary.LittleEndian.Uint64(head[ttlStart:ttlEnd]))
+
+		var created time.Time
+		if err := created.UnmarshalBinary(head[createdStart:createdEnd]); err != nil {
+			return time.Time{}, 0, errors.Wrap(err, "error unmarshalling binary into time")
 		}
 
-		ttl := int64(binary.LittleEndian.Uint64(head[ttlStart:ttlEnd]))
+		return created, ttl, nil
+	default:
+		return time.Time{}, 0, errors.Errorf("uncognized header version: %d", head[versionByte])
+	}
+}
 
-		if ttl == 0 {
-			return false, nil
-		}
+// HeaderSize returns the size of the ttl header written by WriteTTL
+func HeaderSize() int64 {
+	return headerSize
+}
 
-		var ref time.Time
-		if err := ref.UnmarshalBinary(head[createdStart:createdEnd]); err != nil {
-			return false, errors.Wrap(err, "error unmarshalling binary into time")
-		}
+// Expiry returns the expiration time given the creation time and ttl,
+// or zero time if the ttl is 0 (never expires)
+func Expiry(created time.Time, ttl time.Duration) time.Time {
+	if ttl == 0 {
+		return time.Time{}
+	}
+	return created.Add(ttl)
+}
 
-		return time.Now().After(ref.Add(time.Duration(ttl))), nil
-	default:
-		return false, errors.Errorf("uncognized header version: %d", head[versionByte])
+// RenewTTL returns the ttl relative to the creation time such that the data expires ttl from now.
+// A ttl of 0 (never expires) is returned as is
+func RenewTTL(created time.Time, ttl time.Duration) time.Duration {
+	if ttl == 0 {
+		return 0
+	}
+	return time.Since(created) + ttl
+}
+
+// TTLExceeded will read the ttl info from current position of io.Reader.
+// Using this method for unified wire format is strongly preferred
+func TTLExceeded(r io.Reader) (bool, error) {
+	created, ttl, err := ReadTTL(r)
+	if err != nil {
+		return false, err
 	}
+
+	if ttl == 0 {
+		return false, nil
+	}
+
+	return time.Now().After(created.Add(ttl)), nil
 }
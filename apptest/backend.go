@@ -104,6 +104,210 @@ func TestBackend(t *testing.T, b app.Backend) {
 	})
 }
 
+func TestListBackend(t *testing.T, b app.Backend) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	t.Run("list should return unexpired documents with prefix", func(t *testing.T) {
+		prefix := randomString(16)
+
+		expected := map[string]int64{}
+		for i := 0; i < 5; i++ {
+			key := prefix + randomString(4)
+			buf := make([]byte, i+1)
+			err := b.SaveTTL(ctx, key, buf, 0)
+			require.NoError(t, err)
+			expected[key] = int64(len(buf))
+		}
+
+		err := b.SaveTTL(ctx, prefix+"expired", []byte("h"), time.Millisecond*100)
+		require.NoError(t, err)
+		err = b.SaveTTL(ctx, randomString(16), []byte("h"), 0)
+		require.NoError(t, err)
+
+		<-time.After(time.Millisecond * 500)
+
+		listed := collect(t, func(cursor string) ([]app.Info, string, error) {
+			return b.List(ctx, prefix, cursor, 2)
+		})
+		require.Equal(t, expected, listed)
+	})
+}
+
+func TestStatBackend(t *testing.T, b app.Backend) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	t.Run("stat should return size and expiry", func(t *testing.T) {
+		key := randomString(16)
+		buf := make([]byte, 42)
+		ttl := time.Hour
+
+		err := b.SaveTTL(ctx, key, buf, ttl)
+		require.NoError(t, err)
+
+		info, err := b.Stat(ctx, key)
+		require.NoError(t, err)
+		requireInfo(t, info, key, int64(len(buf)), ttl)
+	})
+
+	t.Run("stat without ttl should not expire", func(t *testing.T) {
+		key := randomString(16)
+
+		err := b.SaveTTL(ctx, key, []byte("hello"), 0)
+		require.NoError(t, err)
+
+		info, err := b.Stat(ctx, key)
+		require.NoError(t, err)
+		requireInfo(t, info, key, 5, 0)
+	})
+
+	t.Run("stat on non-existent key should return not found", func(t *testing.T) {
+		_, err := b.Stat(ctx, randomString(16))
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+
+	t.Run("stat outside of ttl should return not found", func(t *testing.T) {
+		key := randomString(16)
+
+		err := b.SaveTTL(ctx, key, []byte("hello"), time.Millisecond*100)
+		require.NoError(t, err)
+
+		<-time.After(time.Millisecond * 500)
+
+		_, err = b.Stat(ctx, key)
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+}
+
+func TestTouchBackend(t *testing.T, b app.Backend) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	t.Run("touch should extend expiration", func(t *testing.T) {
+		key := randomString(16)
+
+		err := b.SaveTTL(ctx, key, []byte("hello"), time.Millisecond*500)
+		require.NoError(t, err)
+
+		err = b.Touch(ctx, key, time.Hour)
+		require.NoError(t, err)
+
+		<-time.After(time.Second)
+
+		info, err := b.Stat(ctx, key)
+		require.NoError(t, err)
+		requireInfo(t, info, key, 5, time.Hour)
+	})
+
+	t.Run("touch without ttl should make permanent", func(t *testing.T) {
+		key := randomString(16)
+
+		err := b.SaveTTL(ctx, key, []byte("hello"), time.Millisecond*500)
+		require.NoError(t, err)
+
+		err = b.Touch(ctx, key, 0)
+		require.NoError(t, err)
+
+		<-time.After(time.Second)
+
+		ret, err := b.Retrieve(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, []byte("hello"), ret)
+	})
+
+	t.Run("touch on non-existent key should return not found", func(t *testing.T) {
+		err := b.Touch(ctx, randomString(16), time.Hour)
+		require.ErrorIs(t, err, app.ErrNotFound)
+
+		err = b.Touch(ctx, randomString(16), 0)
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+
+	t.Run("touch outside of ttl should return not found", func(t *testing.T) {
+		key := randomString(16)
+
+		err := b.SaveTTL(ctx, key, []byte("hello"), time.Millisecond*100)
+		require.NoError(t, err)
+
+		<-time.After(time.Millisecond * 500)
+
+		err = b.Touch(ctx, key, time.Hour)
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+}
+
+func TestOverwriteBackend(t *testing.T, b app.Backend) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	t.Run("overwrite should replace existing data", func(t *testing.T) {
+		key := randomString(16)
+
+		err := b.SaveTTL(ctx, key, []byte("hello"), time.Hour)
+		require.NoError(t, err)
+
+		err = b.Overwrite(ctx, key, []byte("world!"), 0)
+		require.NoError(t, err)
+
+		ret, err := b.Retrieve(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, []byte("world!"), ret)
+
+		info, err := b.Stat(ctx, key)
+		require.NoError(t, err)
+		requireInfo(t, info, key, 6, 0)
+	})
+
+	t.Run("overwrite should create missing data", func(t *testing.T) {
+		key := randomString(16)
+
+		err := b.Overwrite(ctx, key, []byte("hello"), time.Hour)
+		require.NoError(t, err)
+
+		ret, err := b.Retrieve(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, []byte("hello"), ret)
+
+		err = b.SaveTTL(ctx, key, []byte("world"), 0)
+		require.ErrorIs(t, err, app.ErrConflict)
+	})
+}
+
+// requireInfo checks the stat result. Created is optional as not every backend records it
+func requireInfo(t *testing.T, info app.Info, key string, size int64, ttl time.Duration) {
+	now := time.Now()
+
+	require.Equal(t, key, info.ID)
+	require.Equal(t, size, info.Size)
+	if !info.Created.IsZero() {
+		require.WithinDuration(t, now, info.Created, time.Second*5)
+	}
+	if ttl == 0 {
+		require.True(t, info.Expires.IsZero())
+	} else {
+		require.WithinDuration(t, now.Add(ttl), info.Expires, time.Second*5)
+	}
+}
+
+func collect(t *testing.T, list func(cursor string) ([]app.Info, string, error)) map[string]int64 {
+	listed := map[string]int64{}
+	cursor := ""
+	for i := 0; i < 100; i++ {
+		items, next, err := list(cursor)
+		require.NoError(t, err)
+		for _, item := range items {
+			listed[item.ID] = item.Size
+		}
+		if next == "" {
+			return listed
+		}
+		cursor = next
+	}
+	require.FailNow(t, "listing did not terminate")
+	return nil
+}
+
 func TestRemovableBackend(t *testing.T, b app.RemovableBackend) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
@@ -246,6 +450,187 @@ func TestFastBackend(t *testing.T, b app.FastBackend) {
 	})
 }
 
+func TestListFastBackend(t *testing.T, b app.FastBackend) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	t.Run("list should return unexpired documents with prefix", func(t *testing.T) {
+		prefix := randomString(16)
+
+		expected := map[string]int64{}
+		for i := 0; i < 5; i++ {
+			key := prefix + randomString(4)
+			written, err := b.SaveTTL(ctx, key, io.NopCloser(bytes.NewReader(make([]byte, i+1))), 0)
+			require.NoError(t, err)
+			expected[key] = written
+		}
+
+		_, err := b.SaveTTL(ctx, prefix+"expired", GetReaderFn(t)(), time.Millisecond*100)
+		require.NoError(t, err)
+		_, err = b.SaveTTL(ctx, randomString(16), GetReaderFn(t)(), 0)
+		require.NoError(t, err)
+
+		<-time.After(time.Millisecond * 500)
+
+		listed := collect(t, func(cursor string) ([]app.Info, string, error) {
+			return b.List(ctx, prefix, cursor, 2)
+		})
+		require.Equal(t, expected, listed)
+	})
+}
+
+func TestStatFastBackend(t *testing.T, b app.FastBackend) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	reader := GetReaderFn(t)
+
+	t.Run("stat should return size and expiry", func(t *testing.T) {
+		key := randomString(16)
+		ttl := time.Hour
+
+		written, err := b.SaveTTL(ctx, key, reader(), ttl)
+		require.NoError(t, err)
+
+		info, err := b.Stat(ctx, key)
+		require.NoError(t, err)
+		requireInfo(t, info, key, written, ttl)
+	})
+
+	t.Run("stat without ttl should not expire", func(t *testing.T) {
+		key := randomString(16)
+
+		written, err := b.SaveTTL(ctx, key, reader(), 0)
+		require.NoError(t, err)
+
+		info, err := b.Stat(ctx, key)
+		require.NoError(t, err)
+		requireInfo(t, info, key, written, 0)
+	})
+
+	t.Run("stat on non-existent key should return not found", func(t *testing.T) {
+		_, err := b.Stat(ctx, randomString(16))
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+
+	t.Run("stat outside of ttl should return not found", func(t *testing.T) {
+		key := randomString(16)
+
+		_, err := b.SaveTTL(ctx, key, reader(), time.Second)
+		require.NoError(t, err)
+
+		<-time.After(time.Second * 2)
+
+		_, err = b.Stat(ctx, key)
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+}
+
+func TestTouchFastBackend(t *testing.T, b app.FastBackend) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	reader := GetReaderFn(t)
+
+	t.Run("touch should extend expiration", func(t *testing.T) {
+		key := randomString(16)
+
+		written, err := b.SaveTTL(ctx, key, reader(), time.Second)
+		require.NoError(t, err)
+
+		err = b.Touch(ctx, key, time.Hour)
+		require.NoError(t, err)
+
+		<-time.After(time.Second * 2)
+
+		info, err := b.Stat(ctx, key)
+		require.NoError(t, err)
+		requireInfo(t, info, key, written, time.Hour)
+	})
+
+	t.Run("touch without ttl should make permanent", func(t *testing.T) {
+		key := randomString(16)
+
+		_, err := b.SaveTTL(ctx, key, reader(), time.Second)
+		require.NoError(t, err)
+
+		err = b.Touch(ctx, key, 0)
+		require.NoError(t, err)
+
+		<-time.After(time.Second * 2)
+
+		r, err := b.Retrieve(ctx, key)
+		require.NoError(t, err)
+		defer r.Close()
+
+		ret, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		expected, err := io.ReadAll(reader())
+		require.NoError(t, err)
+		require.Equal(t, expected, ret)
+	})
+
+	t.Run("touch on non-existent key should return not found", func(t *testing.T) {
+		err := b.Touch(ctx, randomString(16), time.Hour)
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+
+	t.Run("touch outside of ttl should return not found", func(t *testing.T) {
+		key := randomString(16)
+
+		_, err := b.SaveTTL(ctx, key, reader(), time.Second)
+		require.NoError(t, err)
+
+		<-time.After(time.Second * 2)
+
+		err = b.Touch(ctx, key, time.Hour)
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+}
+
+func TestOverwriteFastBackend(t *testing.T, b app.FastBackend) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	retrieve := func(t *testing.T, key string) string {
+		r, err := b.Retrieve(ctx, key)
+		require.NoError(t, err)
+		defer r.Close()
+
+		ret, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(ret)
+	}
+
+	t.Run("overwrite should replace existing data", func(t *testing.T) {
+		key := randomString(16)
+
+		_, err := b.SaveTTL(ctx, key, io.NopCloser(bytes.NewBufferString("first")), time.Hour)
+		require.NoError(t, err)
+
+		written, err := b.Overwrite(ctx, key, io.NopCloser(bytes.NewBufferString("second")), 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(6), written)
+		require.Equal(t, "second", retrieve(t, key))
+
+		info, err := b.Stat(ctx, key)
+		require.NoError(t, err)
+		requireInfo(t, info, key, written, 0)
+	})
+
+	t.Run("overwrite should create missing data", func(t *testing.T) {
+		key := randomString(16)
+
+		_, err := b.Overwrite(ctx, key, io.NopCloser(bytes.NewBufferString("first")), time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, "first", retrieve(t, key))
+
+		_, err = b.SaveTTL(ctx, key, io.NopCloser(bytes.NewBufferString("second")), 0)
+		require.ErrorIs(t, err, app.ErrConflict)
+	})
+}
+
 func TestRemovableFastBackend(t *testing.T, b app.RemovableFastBackend) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
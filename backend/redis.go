@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/zllovesuki/b/app"
@@ -46,6 +47,13 @@ func (b *RedisBackend) SaveTTL(c context.Context, identifier string, data []byte
 	return nil
 }
 
+func (b *RedisBackend) Overwrite(c context.Context, identifier string, data []byte, ttl time.Duration) error {
+	if err := b.cli.Set(c, identifier, data, ttl).Err(); err != nil {
+		return errors.Wrap(err, "unexpected error from redis when overwriting")
+	}
+	return nil
+}
+
 func (b *RedisBackend) Retrieve(c context.Context, identifier string) ([]byte, error) {
 	ret, err := b.cli.Get(c, identifier).Bytes()
 	switch err {
@@ -58,6 +66,102 @@ func (b *RedisBackend) Retrieve(c context.Context, identifier string) ([]byte, e
 	}
 }
 
+func (b *RedisBackend) Stat(c context.Context, identifier string) (app.Info, error) {
+	pipe := b.cli.Pipeline()
+	size := pipe.StrLen(c, identifier)
+	ttl := pipe.PTTL(c, identifier)
+	if _, err := pipe.Exec(c); err != nil {
+		return app.Info{}, errors.Wrap(err, "unexpected error from redis when stat")
+	}
+	if ttl.Val() == -2 {
+		return app.Info{}, app.ErrNotFound
+	}
+	// redis does not keep track of creation time
+	info := app.Info{
+		ID:   identifier,
+		Size: size.Val(),
+	}
+	if ttl.Val() > 0 {
+		info.Expires = time.Now().UTC().Add(ttl.Val())
+	}
+	return info, nil
+}
+
+func (b *RedisBackend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	var start uint64
+	if cursor != "" {
+		var err error
+		start, err = strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "invalid cursor")
+		}
+	}
+
+	keys, next, err := b.cli.Scan(c, start, prefix+"*", int64(limit)).Result()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "unexpected error from redis when scanning")
+	}
+
+	pipe := b.cli.Pipeline()
+	sizes := make([]*redis.IntCmd, len(keys))
+	ttls := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		sizes[i] = pipe.StrLen(c, key)
+		ttls[i] = pipe.PTTL(c, key)
+	}
+	if len(keys) > 0 {
+		if _, err := pipe.Exec(c); err != nil {
+			return nil, "", errors.Wrap(err, "unexpected error from redis when listing")
+		}
+	}
+
+	now := time.Now().UTC()
+	items := make([]app.Info, 0, len(keys))
+	for i, key := range keys {
+		ttl := ttls[i].Val()
+		if ttl == -2 {
+			// key expired or removed since scan
+			continue
+		}
+		// redis does not keep track of creation time
+		info := app.Info{
+			ID:   key,
+			Size: sizes[i].Val(),
+		}
+		if ttl > 0 {
+			info.Expires = now.Add(ttl)
+		}
+		items = append(items, info)
+	}
+
+	if next == 0 {
+		return items, "", nil
+	}
+	return items, strconv.FormatUint(next, 10), nil
+}
+
+func (b *RedisBackend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	var ok bool
+	var err error
+	if ttl > 0 {
+		ok, err = b.cli.PExpire(c, identifier, ttl).Result()
+	} else {
+		// PERSIST also returns 0 if the key has no expiration, so check existence as well
+		pipe := b.cli.TxPipeline()
+		exists := pipe.Exists(c, identifier)
+		pipe.Persist(c, identifier)
+		_, err = pipe.Exec(c)
+		ok = exists.Val() == 1
+	}
+	if err != nil {
+		return errors.Wrap(err, "unexpected error from redis when touching")
+	}
+	if !ok {
+		return app.ErrNotFound
+	}
+	return nil
+}
+
 func (b *RedisBackend) Close() error {
 	return b.cli.Close()
 }
@@ -65,3 +169,8 @@ func (b *RedisBackend) Close() error {
 func (b *RedisBackend) Delete(c context.Context, identifier string) error {
 	return b.cli.Del(c, identifier).Err()
 }
+
+// Client returns the underlying redis client, such that other components can share the connection pool
+func (b *RedisBackend) Client() *redis.Client {
+	return b.cli
+}
@@ -45,3 +45,31 @@ func TestRedisDelete(t *testing.T) {
 
 	apptest.TestRemovableBackend(t, b)
 }
+
+func TestRedisList(t *testing.T) {
+	b, cleanup := getRedisFixtures(t)
+	defer cleanup()
+
+	apptest.TestListBackend(t, b)
+}
+
+func TestRedisStat(t *testing.T) {
+	b, cleanup := getRedisFixtures(t)
+	defer cleanup()
+
+	apptest.TestStatBackend(t, b)
+}
+
+func TestRedisTouch(t *testing.T) {
+	b, cleanup := getRedisFixtures(t)
+	defer cleanup()
+
+	apptest.TestTouchBackend(t, b)
+}
+
+func TestRedisOverwrite(t *testing.T) {
+	b, cleanup := getRedisFixtures(t)
+	defer cleanup()
+
+	apptest.TestOverwriteBackend(t, b)
+}
@@ -80,6 +80,21 @@ func (s *SQLiteBackend) SaveTTL(c context.Context, identifier string, data []byt
 	})
 }
 
+func (s *SQLiteBackend) Overwrite(c context.Context, identifier string, data []byte, ttl time.Duration) error {
+	d := SQLiteData{
+		ID:      identifier,
+		Data:    data,
+		Created: time.Now().UTC(),
+	}
+	if ttl > 0 {
+		d.Expires = time.Now().UTC().Add(ttl)
+	}
+	return s.db.WithContext(c).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "created", "expires"}),
+	}).Create(&d).Error
+}
+
 func (s *SQLiteBackend) Retrieve(c context.Context, identifier string) ([]byte, error) {
 	var data []byte
 	ret := s.db.WithContext(c).Transaction(func(tx *gorm.DB) error {
@@ -91,7 +106,7 @@ func (s *SQLiteBackend) Retrieve(c context.Context, identifier string) ([]byte,
 			return res.Error
 		}
 		if !d.Expires.IsZero() && time.Now().UTC().After(d.Expires) {
-			return app.ErrNotFound
+			return app.ErrExpired
 		}
 		data = d.Data
 		return nil
@@ -102,6 +117,98 @@ func (s *SQLiteBackend) Retrieve(c context.Context, identifier string) ([]byte,
 	return data, nil
 }
 
+func (s *SQLiteBackend) Stat(c context.Context, identifier string) (app.Info, error) {
+	var row struct {
+		ID      string
+		Size    int64
+		Created time.Time
+		Expires time.Time
+	}
+	res := s.db.WithContext(c).
+		Model(&SQLiteData{}).
+		Select("id, length(data) AS size, created, expires").
+		Where("id = ?", identifier).
+		Take(&row)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return app.Info{}, app.ErrNotFound
+	} else if res.Error != nil {
+		return app.Info{}, errors.Wrap(res.Error, "unable to stat data")
+	}
+	if !row.Expires.IsZero() && time.Now().UTC().After(row.Expires) {
+		return app.Info{}, app.ErrExpired
+	}
+	return app.Info{
+		ID:      row.ID,
+		Size:    row.Size,
+		Created: row.Created,
+		Expires: row.Expires,
+	}, nil
+}
+
+func (s *SQLiteBackend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	var rows []struct {
+		ID      string
+		Size    int64
+		Created time.Time
+		Expires time.Time
+	}
+	// substr is used instead of LIKE as LIKE is case-insensitive in SQLite
+	res := s.db.WithContext(c).
+		Model(&SQLiteData{}).
+		Select("id, length(data) AS size, created, expires").
+		Where("substr(id, 1, ?) = ? AND id > ?", len(prefix), prefix, cursor).
+		Order("id").
+		Limit(limit).
+		Find(&rows)
+	if res.Error != nil {
+		return nil, "", errors.Wrap(res.Error, "unable to list data")
+	}
+
+	now := time.Now().UTC()
+	items := make([]app.Info, 0, len(rows))
+	for _, row := range rows {
+		if !row.Expires.IsZero() && now.After(row.Expires) {
+			continue
+		}
+		items = append(items, app.Info{
+			ID:      row.ID,
+			Size:    row.Size,
+			Created: row.Created,
+			Expires: row.Expires,
+		})
+	}
+
+	next := ""
+	if len(rows) == limit {
+		next = rows[len(rows)-1].ID
+	}
+	return items, next, nil
+}
+
+func (s *SQLiteBackend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	ret := s.db.WithContext(c).Transaction(func(tx *gorm.DB) error {
+		var d SQLiteData
+		res := tx.First(&d, "id = ?", identifier)
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return app.ErrNotFound
+		} else if res.Error != nil {
+			return res.Error
+		}
+		if !d.Expires.IsZero() && time.Now().UTC().After(d.Expires) {
+			return app.ErrExpired
+		}
+		var expires time.Time
+		if ttl > 0 {
+			expires = time.Now().UTC().Add(ttl)
+		}
+		return tx.Model(&d).Update("expires", expires).Error
+	})
+	if ret != nil {
+		return errors.Wrap(ret, "unable to touch data")
+	}
+	return nil
+}
+
 func (s *SQLiteBackend) Close() error {
 	return nil
 }
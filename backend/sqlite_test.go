@@ -41,3 +41,31 @@ func TestSQLiteDelete(t *testing.T) {
 
 	apptest.TestRemovableBackend(t, b)
 }
+
+func TestSQLiteList(t *testing.T) {
+	b, cleanup := getSQLiteFixtures(t)
+	defer cleanup()
+
+	apptest.TestListBackend(t, b)
+}
+
+func TestSQLiteStat(t *testing.T) {
+	b, cleanup := getSQLiteFixtures(t)
+	defer cleanup()
+
+	apptest.TestStatBackend(t, b)
+}
+
+func TestSQLiteTouch(t *testing.T) {
+	b, cleanup := getSQLiteFixtures(t)
+	defer cleanup()
+
+	apptest.TestTouchBackend(t, b)
+}
+
+func TestSQLiteOverwrite(t *testing.T) {
+	b, cleanup := getSQLiteFixtures(t)
+	defer cleanup()
+
+	apptest.TestOverwriteBackend(t, b)
+}
@@ -3,7 +3,5 @@ package box
 // Code generated by go generate; DO NOT EDIT.
 
 func init() {
-	box.Add("/highlightFoot.html", []byte{60, 47, 99, 111, 100, 101, 62, 60, 47, 112, 114, 101, 62, 60, 115, 99, 114, 105, 112, 116, 32, 115, 114, 99, 61, 104, 116, 116, 112, 115, 58, 47, 47, 99, 100, 110, 106, 115, 46, 99, 108, 111, 117, 100, 102, 108, 97, 114, 101, 46, 99, 111, 109, 47, 97, 106, 97, 120, 47, 108, 105, 98, 115, 47, 104, 105, 103, 104, 108, 105, 103, 104, 116, 46, 106, 115, 47, 49, 49, 46, 49, 46, 48, 47, 104, 105, 103, 104, 108, 105, 103, 104, 116, 46, 109, 105, 110, 46, 106, 115, 32, 105, 110, 116, 101, 103, 114, 105, 116, 121, 61, 34, 115, 104, 97, 53, 49, 50, 45, 122, 43, 47, 87, 87, 102, 121, 68, 53, 116, 99, 99, 67, 117, 107, 77, 52, 86, 118, 79, 78, 112, 69, 116, 76, 109, 98, 65, 109, 53, 76, 68, 117, 55, 101, 75, 105, 121, 77, 81, 74, 57, 109, 55, 79, 102, 80, 69, 68, 76, 55, 103, 69, 78, 121, 68, 82, 76, 51, 89, 102, 101, 56, 88, 65, 117, 71, 115, 83, 50, 102, 83, 52, 120, 83, 77, 110, 108, 54, 100, 51, 48, 107, 113, 71, 81, 61, 61, 34, 32, 99, 114, 111, 115, 115, 111, 114, 105, 103, 105, 110, 61, 97, 110, 111, 110, 121, 109, 111, 117, 115, 32, 114, 101, 102, 101, 114, 114, 101, 114, 112, 111, 108, 105, 99, 121, 61, 110, 111, 45, 114, 101, 102, 101, 114, 114, 101, 114, 62, 60, 47, 115, 99, 114, 105, 112, 116, 62, 60, 115, 99, 114, 105, 112, 116, 62, 104, 108, 106, 115, 46, 104, 105, 103, 104, 108, 105, 103, 104, 116, 65, 108, 108, 40, 41, 60, 47, 115, 99, 114, 105, 112, 116, 62})
-	box.Add("/highlightHead.html", []byte{60, 33, 100, 111, 99, 116, 121, 112, 101, 32, 104, 116, 109, 108, 62, 60, 104, 116, 109, 108, 32, 108, 97, 110, 103, 61, 101, 110, 62, 60, 109, 101, 116, 97, 32, 99, 104, 97, 114, 115, 101, 116, 61, 117, 116, 102, 45, 56, 62, 60, 109, 101, 116, 97, 32, 110, 97, 109, 101, 61, 118, 105, 101, 119, 112, 111, 114, 116, 32, 99, 111, 110, 116, 101, 110, 116, 61, 34, 119, 105, 100, 116, 104, 61, 100, 101, 118, 105, 99, 101, 45, 119, 105, 100, 116, 104, 44, 105, 110, 105, 116, 105, 97, 108, 45, 115, 99, 97, 108, 101, 61, 49, 34, 62, 60, 116, 105, 116, 108, 101, 62, 98, 32, 119, 105, 116, 104, 32, 104, 105, 103, 104, 108, 105, 103, 104, 116, 60, 47, 116, 105, 116, 108, 101, 62, 60, 108, 105, 110, 107, 32, 114, 101, 108, 61, 115, 116, 121, 108, 101, 115, 104, 101, 101, 116, 32, 104, 114, 101, 102, 61, 104, 116, 116, 112, 115, 58, 47, 47, 99, 100, 110, 106, 115, 46, 99, 108, 111, 117, 100, 102, 108, 97, 114, 101, 46, 99, 111, 109, 47, 97, 106, 97, 120, 47, 108, 105, 98, 115, 47, 104, 105, 103, 104, 108, 105, 103, 104, 116, 46, 106, 115, 47, 49, 49, 46, 49, 46, 48, 47, 115, 116, 121, 108, 101, 115, 47, 110, 111, 114, 100, 46, 109, 105, 110, 46, 99, 115, 115, 32, 105, 110, 116, 101, 103, 114, 105, 116, 121, 61, 34, 115, 104, 97, 53, 49, 50, 45, 85, 47, 99, 90, 113, 65, 65, 79, 84, 104, 118, 98, 52, 74, 57, 85, 67, 116, 47, 68, 87, 107, 107, 106, 111, 74, 87, 72, 88, 118, 117, 116, 70, 68, 83, 47, 110, 90, 109, 90, 108, 105, 114, 99, 105, 50, 90, 77, 117, 72, 54, 113, 70, 111, 107, 79, 81, 68, 117, 117, 75, 103, 69, 55, 112, 88, 68, 43, 70, 109, 104, 68, 78, 72, 50, 106, 84, 52, 51, 120, 48, 71, 114, 101, 67, 81, 61, 61, 34, 32, 99, 114, 111, 115, 115, 111, 114, 105, 103, 105, 110, 61, 97, 110, 111, 110, 121, 109, 111, 117, 115, 32, 114, 101, 102, 101, 114, 114, 101, 114, 112, 111, 108, 105, 99, 121, 61, 110, 111, 45, 114, 101, 102, 101, 114, 114, 101, 114, 62, 60, 115, 116, 121, 108, 101, 62, 104, 116, 109, 108, 44, 98, 111, 100, 121, 123, 111, 118, 101, 114, 102, 108, 111, 119, 58, 104, 105, 100, 100, 101, 110, 125, 104, 116, 109, 108, 44, 98, 111, 100, 121, 44, 112, 114, 101, 123, 109, 97, 114, 103, 105, 110, 58, 48, 59, 112, 97, 100, 100, 105, 110, 103, 58, 48, 125, 99, 111, 100, 101, 123, 112, 111, 115, 105, 116, 105, 111, 110, 58, 97, 98, 115, 111, 108, 117, 116, 101, 59, 116, 111, 112, 58, 48, 59, 108, 101, 102, 116, 58, 48, 59, 119, 105, 100, 116, 104, 58, 49, 48, 48, 118, 119, 59, 104, 101, 105, 103, 104, 116, 58, 49, 48, 48, 118, 104, 59, 109, 97, 120, 45, 119, 105, 100, 116, 104, 58, 49, 48, 48, 37, 59, 102, 111, 110, 116, 45, 102, 97, 109, 105, 108, 121, 58, 99, 97, 115, 99, 97, 100, 105, 97, 32, 99, 111, 100, 101, 44, 83, 70, 77, 111, 110, 111, 45, 82, 101, 103, 117, 108, 97, 114, 44, 67, 111, 110, 115, 111, 108, 97, 115, 44, 76, 105, 98, 101, 114, 97, 116, 105, 111, 110, 32, 77, 111, 110, 111, 44, 77, 101, 110, 108, 111, 44, 109, 111, 110, 111, 115, 112, 97, 99, 101, 125, 60, 47, 115, 116, 121, 108, 101, 62, 60, 112, 114, 101, 62, 60, 99, 111, 100, 101, 62, 10})
-	box.Add("/index.html", []byte{60, 33, 100, 111, 99, 116, 121, 112, 101, 32, 104, 116, 109, 108, 62, 60, 104, 116, 109, 108, 32, 108, 97, 110, 103, 61, 101, 110, 62, 60, 109, 101, 116, 97, 32, 99, 104, 97, 114, 115, 101, 116, 61, 117, 116, 102, 45, 56, 62, 60, 109, 101, 116, 97, 32, 110, 97, 109, 101, 61, 118, 105, 101, 119, 112, 111, 114, 116, 32, 99, 111, 110, 116, 101, 110, 116, 61, 34, 119, 105, 100, 116, 104, 61, 100, 101, 118, 105, 99, 101, 45, 119, 105, 100, 116, 104, 44, 105, 110, 105, 116, 105, 97, 108, 45, 115, 99, 97, 108, 101, 61, 49, 34, 62, 60, 108, 105, 110, 107, 32, 114, 101, 108, 61, 115, 116, 121, 108, 101, 115, 104, 101, 101, 116, 32, 104, 114, 101, 102, 61, 104, 116, 116, 112, 115, 58, 47, 47, 99, 100, 110, 106, 115, 46, 99, 108, 111, 117, 100, 102, 108, 97, 114, 101, 46, 99, 111, 109, 47, 97, 106, 97, 120, 47, 108, 105, 98, 115, 47, 115, 112, 101, 99, 116, 114, 101, 46, 99, 115, 115, 47, 48, 46, 53, 46, 56, 47, 115, 112, 101, 99, 116, 114, 101, 46, 109, 105, 110, 46, 99, 115, 115, 32, 105, 110, 116, 101, 103, 114, 105, 116, 121, 61, 34, 115, 104, 97, 50, 53, 54, 45, 74, 50, 52, 80, 90, 105, 117, 110, 88, 57, 117, 76, 49, 83, 100, 109, 98, 101, 54, 89, 84, 57, 107, 78, 117, 86, 53, 108, 102, 86, 120, 106, 51, 65, 54, 75, 105, 106, 53, 85, 80, 54, 107, 61, 34, 32, 99, 114, 111, 115, 115, 111, 114, 105, 103, 105, 110, 61, 97, 110, 111, 110, 121, 109, 111, 117, 115, 62, 60, 108, 105, 110, 107, 32, 114, 101, 108, 61, 115, 116, 121, 108, 101, 115, 104, 101, 101, 116, 32, 104, 114, 101, 102, 61, 104, 116, 116, 112, 115, 58, 47, 47, 99, 100, 110, 106, 115, 46, 99, 108, 111, 117, 100, 102, 108, 97, 114, 101, 46, 99, 111, 109, 47, 97, 106, 97, 120, 47, 108, 105, 98, 115, 47, 115, 112, 101, 99, 116, 114, 101, 46, 99, 115, 115, 47, 48, 46, 53, 46, 56, 47, 115, 112, 101, 99, 116, 114, 101, 45, 105, 99, 111, 110, 115, 46, 109, 105, 110, 46, 99, 115, 115, 32, 105, 110, 116, 101, 103, 114, 105, 116, 121, 61, 34, 115, 104, 97, 50, 53, 54, 45, 76, 120, 100, 68, 83, 57, 71, 57, 52, 65, 114, 85, 122, 50, 85, 89, 86, 80, 111, 53, 70, 104, 83, 101, 68, 52, 111, 119, 119, 99, 66, 70, 65, 81, 118, 50, 78, 108, 49, 100, 78, 85, 85, 61, 34, 32, 99, 114, 111, 115, 115, 111, 114, 105, 103, 105, 110, 61, 97, 110, 111, 110, 121, 109, 111, 117, 115, 62, 60, 115, 116, 121, 108, 101, 62, 100, 105, 118, 91, 105, 100, 36, 61, 45, 102, 111, 114, 109, 93, 58, 110, 111, 116, 40, 46, 97, 99, 116, 105, 118, 101, 41, 123, 100, 105, 115, 112, 108, 97, 121, 58, 110, 111, 110, 101, 125, 46, 99, 111, 108, 45, 54, 123, 119, 105, 100, 116, 104, 58, 55, 48, 37, 33, 105, 109, 112, 111, 114, 116, 97, 110, 116, 125, 60, 47, 115, 116, 121, 108, 101, 62, 60, 116, 105, 116, 108, 101, 62, 98, 60, 47, 116, 105, 116, 108, 101, 62, 60, 110, 97, 118, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 110, 116, 97, 105, 110, 101, 114, 32, 109, 98, 45, 50, 32, 112, 98, 45, 50, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 99, 111, 108, 117, 109, 110, 115, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 108, 117, 109, 110, 32, 99, 111, 108, 45, 115, 109, 45, 49, 50, 32, 99, 111, 108, 45, 109, 100, 45, 49, 48, 32, 99, 111, 108, 45, 108, 103, 45, 56, 32, 99, 111, 108, 45, 54, 32, 99, 111, 108, 45, 109, 120, 45, 97, 117, 116, 111, 34, 62, 60, 117, 108, 32, 99, 108, 97, 115, 115, 61, 34, 116, 97, 98, 32, 116, 97, 98, 45, 98, 108, 111, 99, 107, 34, 62, 60, 108, 105, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 116, 97, 98, 32, 99, 108, 97, 115, 115, 61, 116, 97, 98, 45, 105, 116, 101, 109, 62, 60, 97, 32, 104, 114, 101, 102, 61, 35, 62, 70, 105, 108, 101, 115, 60, 47, 97, 62, 60, 108, 105, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 116, 97, 98, 32, 99, 108, 97, 115, 115, 61, 34, 116, 97, 98, 45, 105, 116, 101, 109, 32, 97, 99, 116, 105, 118, 101, 34, 62, 60, 97, 32, 104, 114, 101, 102, 61, 35, 62, 76, 105, 110, 107, 115, 60, 47, 97, 62, 60, 108, 105, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 116, 97, 98, 32, 99, 108, 97, 115, 115, 61, 116, 97, 98, 45, 105, 116, 101, 109, 62, 60, 97, 32, 104, 114, 101, 102, 61, 35, 62, 84, 101, 120, 116, 115, 60, 47, 97, 62, 60, 47, 117, 108, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 110, 97, 118, 62, 60, 109, 97, 105, 110, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 110, 116, 97, 105, 110, 101, 114, 32, 109, 116, 45, 50, 32, 112, 116, 45, 50, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 99, 111, 108, 117, 109, 110, 115, 62, 60, 100, 105, 118, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 102, 111, 114, 109, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 108, 117, 109, 110, 32, 99, 111, 108, 45, 115, 109, 45, 49, 50, 32, 99, 111, 108, 45, 109, 100, 45, 49, 48, 32, 99, 111, 108, 45, 108, 103, 45, 56, 32, 99, 111, 108, 45, 54, 32, 99, 111, 108, 45, 109, 120, 45, 97, 117, 116, 111, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 102, 105, 108, 101, 115, 45, 117, 114, 108, 62, 85, 82, 76, 60, 47, 108, 97, 98, 101, 108, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 62, 60, 115, 112, 97, 110, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 97, 100, 100, 111, 110, 62, 47, 102, 45, 60, 47, 115, 112, 97, 110, 62, 10, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 117, 114, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 97, 49, 98, 50, 99, 51, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 10, 60, 98, 117, 116, 116, 111, 110, 32, 99, 108, 97, 115, 115, 61, 34, 98, 116, 110, 32, 98, 116, 110, 45, 112, 114, 105, 109, 97, 114, 121, 32, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 98, 116, 110, 34, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 115, 117, 98, 109, 105, 116, 32, 100, 105, 115, 97, 98, 108, 101, 100, 62, 10, 60, 105, 32, 99, 108, 97, 115, 115, 61, 34, 105, 99, 111, 110, 32, 105, 99, 111, 110, 45, 117, 112, 108, 111, 97, 100, 34, 62, 60, 47, 105, 62, 60, 47, 98, 117, 116, 116, 111, 110, 62, 60, 47, 100, 105, 118, 62, 60, 112, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 45, 104, 105, 110, 116, 62, 80, 114, 101, 115, 115, 32, 115, 112, 97, 99, 101, 32, 116, 111, 32, 114, 97, 110, 100, 111, 109, 105, 122, 101, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 102, 105, 108, 101, 115, 45, 102, 105, 108, 101, 62, 70, 105, 108, 101, 60, 47, 108, 97, 98, 101, 108, 62, 10, 60, 105, 110, 112, 117, 116, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 102, 105, 108, 101, 32, 116, 121, 112, 101, 61, 102, 105, 108, 101, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 102, 111, 114, 109, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 108, 117, 109, 110, 32, 99, 111, 108, 45, 115, 109, 45, 49, 50, 32, 99, 111, 108, 45, 109, 100, 45, 49, 48, 32, 99, 111, 108, 45, 108, 103, 45, 56, 32, 99, 111, 108, 45, 54, 32, 99, 111, 108, 45, 109, 120, 45, 97, 117, 116, 111, 32, 97, 99, 116, 105, 118, 101, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 108, 105, 110, 107, 115, 45, 117, 114, 108, 62, 85, 82, 76, 60, 47, 108, 97, 98, 101, 108, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 62, 60, 115, 112, 97, 110, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 97, 100, 100, 111, 110, 62, 47, 108, 45, 60, 47, 115, 112, 97, 110, 62, 10, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 117, 114, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 97, 49, 98, 50, 99, 51, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 10, 60, 98, 117, 116, 116, 111, 110, 32, 99, 108, 97, 115, 115, 61, 34, 98, 116, 110, 32, 98, 116, 110, 45, 112, 114, 105, 109, 97, 114, 121, 32, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 98, 116, 110, 34, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 115, 117, 98, 109, 105, 116, 32, 100, 105, 115, 97, 98, 108, 101, 100, 62, 10, 60, 105, 32, 99, 108, 97, 115, 115, 61, 34, 105, 99, 111, 110, 32, 105, 99, 111, 110, 45, 117, 112, 108, 111, 97, 100, 34, 62, 60, 47, 105, 62, 60, 47, 98, 117, 116, 116, 111, 110, 62, 60, 47, 100, 105, 118, 62, 60, 112, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 45, 104, 105, 110, 116, 62, 80, 114, 101, 115, 115, 32, 115, 112, 97, 99, 101, 32, 116, 111, 32, 114, 97, 110, 100, 111, 109, 105, 122, 101, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 108, 105, 110, 107, 115, 45, 102, 111, 114, 119, 97, 114, 100, 62, 70, 111, 114, 119, 97, 114, 100, 60, 47, 108, 97, 98, 101, 108, 62, 10, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 102, 111, 114, 119, 97, 114, 100, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 116, 121, 112, 101, 61, 117, 114, 108, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 104, 116, 116, 112, 58, 47, 47, 101, 120, 97, 109, 112, 108, 101, 46, 99, 111, 109, 47, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 102, 111, 114, 109, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 108, 117, 109, 110, 32, 99, 111, 108, 45, 115, 109, 45, 49, 50, 32, 99, 111, 108, 45, 109, 100, 45, 49, 48, 32, 99, 111, 108, 45, 108, 103, 45, 56, 32, 99, 111, 108, 45, 54, 32, 99, 111, 108, 45, 109, 120, 45, 97, 117, 116, 111, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 116, 101, 120, 116, 115, 45, 117, 114, 108, 62, 85, 82, 76, 60, 47, 108, 97, 98, 101, 108, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 62, 60, 115, 112, 97, 110, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 97, 100, 100, 111, 110, 62, 47, 116, 45, 60, 47, 115, 112, 97, 110, 62, 10, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 117, 114, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 97, 49, 98, 50, 99, 51, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 10, 60, 98, 117, 116, 116, 111, 110, 32, 99, 108, 97, 115, 115, 61, 34, 98, 116, 110, 32, 98, 116, 110, 45, 112, 114, 105, 109, 97, 114, 121, 32, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 98, 116, 110, 34, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 115, 117, 98, 109, 105, 116, 32, 100, 105, 115, 97, 98, 108, 101, 100, 62, 10, 60, 105, 32, 99, 108, 97, 115, 115, 61, 34, 105, 99, 111, 110, 32, 105, 99, 111, 110, 45, 117, 112, 108, 111, 97, 100, 34, 62, 60, 47, 105, 62, 60, 47, 98, 117, 116, 116, 111, 110, 62, 60, 47, 100, 105, 118, 62, 60, 112, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 45, 104, 105, 110, 116, 62, 80, 114, 101, 115, 115, 32, 115, 112, 97, 99, 101, 32, 116, 111, 32, 114, 97, 110, 100, 111, 109, 105, 122, 101, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 116, 101, 120, 116, 115, 45, 99, 111, 110, 116, 101, 110, 116, 115, 62, 67, 111, 110, 116, 101, 110, 116, 115, 60, 47, 108, 97, 98, 101, 108, 62, 10, 60, 116, 101, 120, 116, 97, 114, 101, 97, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 99, 111, 110, 116, 101, 110, 116, 115, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 114, 111, 119, 115, 61, 51, 48, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 34, 72, 101, 108, 108, 111, 44, 32, 87, 111, 114, 108, 100, 33, 34, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 60, 47, 116, 101, 120, 116, 97, 114, 101, 97, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 109, 97, 105, 110, 62, 60, 100, 105, 118, 32, 105, 100, 61, 109, 111, 100, 97, 108, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 62, 60, 97, 32, 105, 100, 61, 109, 111, 100, 97, 108, 45, 98, 103, 32, 104, 114, 101, 102, 61, 35, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 45, 111, 118, 101, 114, 108, 97, 121, 62, 60, 47, 97, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 45, 99, 111, 110, 116, 97, 105, 110, 101, 114, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 45, 104, 101, 97, 100, 101, 114, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 34, 109, 111, 100, 97, 108, 45, 116, 105, 116, 108, 101, 32, 104, 54, 34, 62, 83, 117, 99, 99, 101, 115, 115, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 45, 98, 111, 100, 121, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 99, 111, 110, 116, 101, 110, 116, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 104, 97, 115, 45, 105, 99, 111, 110, 45, 114, 105, 103, 104, 116, 62, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 109, 111, 100, 97, 108, 45, 105, 110, 112, 117, 116, 32, 116, 121, 112, 101, 61, 117, 114, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 62, 10, 60, 105, 32, 99, 108, 97, 115, 115, 61, 34, 102, 111, 114, 109, 45, 105, 99, 111, 110, 32, 105, 99, 111, 110, 32, 105, 99, 111, 110, 45, 99, 111, 112, 121, 34, 62, 60, 47, 105, 62, 60, 47, 100, 105, 118, 62, 60, 112, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 45, 104, 105, 110, 116, 32, 105, 100, 61, 109, 111, 100, 97, 108, 45, 104, 105, 110, 116, 62, 67, 108, 105, 99, 107, 32, 116, 111, 32, 99, 111, 112, 121, 32, 116, 111, 32, 99, 108, 105, 112, 98, 111, 97, 114, 100, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 115, 99, 114, 105, 112, 116, 62, 99, 111, 110, 115, 116, 32, 116, 97, 98, 115, 61, 123, 102, 105, 108, 101, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 116, 97, 98, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 102, 111, 114, 109, 34, 41, 93, 44, 108, 105, 110, 107, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 116, 97, 98, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 102, 111, 114, 109, 34, 41, 93, 44, 116, 101, 120, 116, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 116, 97, 98, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 102, 111, 114, 109, 34, 41, 93, 125, 44, 105, 110, 112, 117, 116, 115, 61, 123, 102, 105, 108, 101, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 117, 114, 108, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 102, 105, 108, 101, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 115, 117, 98, 109, 105, 116, 34, 41, 93, 44, 108, 105, 110, 107, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 117, 114, 108, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 102, 111, 114, 119, 97, 114, 100, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 115, 117, 98, 109, 105, 116, 34, 41, 93, 44, 116, 101, 120, 116, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 117, 114, 108, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 99, 111, 110, 116, 101, 110, 116, 115, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 115, 117, 98, 109, 105, 116, 34, 41, 93, 125, 59, 108, 101, 116, 32, 98, 97, 115, 101, 85, 114, 108, 61, 96, 36, 123, 108, 111, 99, 97, 116, 105, 111, 110, 46, 112, 114, 111, 116, 111, 99, 111, 108, 125, 47, 47, 36, 123, 108, 111, 99, 97, 116, 105, 111, 110, 46, 104, 111, 115, 116, 125, 36, 123, 108, 111, 99, 97, 116, 105, 111, 110, 46, 112, 97, 116, 104, 110, 97, 109, 101, 125, 96, 59, 98, 97, 115, 101, 85, 114, 108, 46, 101, 110, 100, 115, 87, 105, 116, 104, 40, 34, 47, 34, 41, 124, 124, 40, 98, 97, 115, 101, 85, 114, 108, 43, 61, 34, 47, 34, 41, 59, 99, 111, 110, 115, 116, 32, 109, 111, 100, 97, 108, 61, 123, 115, 101, 108, 102, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 109, 111, 100, 97, 108, 34, 41, 44, 105, 110, 112, 117, 116, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 109, 111, 100, 97, 108, 45, 105, 110, 112, 117, 116, 34, 41, 44, 98, 103, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 109, 111, 100, 97, 108, 45, 98, 103, 34, 41, 44, 104, 105, 110, 116, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 109, 111, 100, 97, 108, 45, 104, 105, 110, 116, 34, 41, 125, 44, 111, 112, 101, 110, 77, 111, 100, 97, 108, 61, 97, 61, 62, 123, 109, 111, 100, 97, 108, 46, 105, 110, 112, 117, 116, 46, 118, 97, 108, 117, 101, 61, 97, 44, 109, 111, 100, 97, 108, 46, 104, 105, 110, 116, 46, 105, 110, 110, 101, 114, 84, 101, 120, 116, 61, 34, 67, 108, 105, 99, 107, 32, 116, 111, 32, 99, 111, 112, 121, 32, 116, 111, 32, 99, 108, 105, 112, 98, 111, 97, 114, 100, 34, 44, 109, 111, 100, 97, 108, 46, 115, 101, 108, 102, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 97, 100, 100, 40, 34, 97, 99, 116, 105, 118, 101, 34, 41, 125, 44, 99, 108, 111, 115, 101, 77, 111, 100, 97, 108, 61, 40, 41, 61, 62, 123, 109, 111, 100, 97, 108, 46, 104, 105, 110, 116, 46, 105, 110, 110, 101, 114, 84, 101, 120, 116, 61, 34, 67, 111, 112, 105, 101, 100, 32, 116, 111, 32, 99, 108, 105, 112, 98, 111, 97, 114, 100, 34, 44, 115, 101, 116, 84, 105, 109, 101, 111, 117, 116, 40, 40, 41, 61, 62, 123, 109, 111, 100, 97, 108, 46, 115, 101, 108, 102, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 114, 101, 109, 111, 118, 101, 40, 34, 97, 99, 116, 105, 118, 101, 34, 41, 44, 109, 111, 100, 97, 108, 46, 105, 110, 112, 117, 116, 46, 118, 97, 108, 117, 101, 61, 34, 34, 125, 44, 50, 53, 48, 48, 41, 125, 59, 109, 111, 100, 97, 108, 46, 105, 110, 112, 117, 116, 46, 111, 110, 99, 108, 105, 99, 107, 61, 97, 61, 62, 123, 97, 46, 112, 114, 101, 118, 101, 110, 116, 68, 101, 102, 97, 117, 108, 116, 40, 41, 44, 109, 111, 100, 97, 108, 46, 105, 110, 112, 117, 116, 46, 115, 101, 108, 101, 99, 116, 40, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 101, 120, 101, 99, 67, 111, 109, 109, 97, 110, 100, 40, 34, 99, 111, 112, 121, 34, 41, 44, 99, 108, 111, 115, 101, 77, 111, 100, 97, 108, 40, 41, 125, 44, 109, 111, 100, 97, 108, 46, 98, 103, 46, 111, 110, 99, 108, 105, 99, 107, 61, 99, 108, 111, 115, 101, 77, 111, 100, 97, 108, 59, 99, 111, 110, 115, 116, 32, 114, 97, 110, 100, 111, 109, 85, 114, 108, 61, 40, 41, 61, 62, 77, 97, 116, 104, 46, 102, 108, 111, 111, 114, 40, 77, 97, 116, 104, 46, 114, 97, 110, 100, 111, 109, 40, 41, 42, 50, 49, 52, 55, 52, 56, 51, 54, 52, 55, 41, 46, 116, 111, 83, 116, 114, 105, 110, 103, 40, 51, 54, 41, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 97, 32, 105, 110, 32, 116, 97, 98, 115, 41, 116, 97, 98, 115, 91, 97, 93, 91, 48, 93, 46, 111, 110, 99, 108, 105, 99, 107, 61, 40, 41, 61, 62, 123, 99, 111, 110, 115, 116, 32, 98, 61, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 65, 108, 108, 40, 34, 46, 97, 99, 116, 105, 118, 101, 34, 41, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 97, 32, 111, 102, 32, 98, 41, 97, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 114, 101, 109, 111, 118, 101, 40, 34, 97, 99, 116, 105, 118, 101, 34, 41, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 98, 32, 111, 102, 32, 116, 97, 98, 115, 91, 97, 93, 41, 98, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 97, 100, 100, 40, 34, 97, 99, 116, 105, 118, 101, 34, 41, 125, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 98, 32, 105, 110, 32, 105, 110, 112, 117, 116, 115, 41, 123, 99, 111, 110, 115, 116, 32, 99, 61, 105, 110, 112, 117, 116, 115, 91, 98, 93, 91, 105, 110, 112, 117, 116, 115, 91, 98, 93, 46, 108, 101, 110, 103, 116, 104, 45, 49, 93, 44, 97, 61, 105, 110, 112, 117, 116, 115, 91, 98, 93, 91, 48, 93, 59, 97, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 105, 110, 112, 117, 116, 34, 44, 98, 61, 62, 123, 105, 102, 40, 97, 46, 118, 97, 108, 117, 101, 91, 97, 46, 118, 97, 108, 117, 101, 46, 108, 101, 110, 103, 116, 104, 45, 49, 93, 61, 61, 61, 34, 32, 34, 41, 123, 97, 46, 118, 97, 108, 117, 101, 61, 114, 97, 110, 100, 111, 109, 85, 114, 108, 40, 41, 44, 100, 40, 41, 44, 98, 46, 112, 114, 101, 118, 101, 110, 116, 68, 101, 102, 97, 117, 108, 116, 40, 41, 59, 114, 101, 116, 117, 114, 110, 125, 97, 46, 118, 97, 108, 117, 101, 61, 97, 46, 118, 97, 108, 117, 101, 46, 114, 101, 112, 108, 97, 99, 101, 40, 47, 91, 94, 48, 45, 57, 65, 45, 90, 97, 45, 122, 93, 47, 103, 44, 34, 34, 41, 46, 116, 111, 76, 111, 119, 101, 114, 67, 97, 115, 101, 40, 41, 44, 112, 97, 114, 115, 101, 73, 110, 116, 40, 97, 46, 118, 97, 108, 117, 101, 44, 51, 54, 41, 62, 50, 49, 52, 55, 52, 56, 51, 54, 52, 55, 63, 97, 46, 115, 101, 116, 67, 117, 115, 116, 111, 109, 86, 97, 108, 105, 100, 105, 116, 121, 40, 34, 66, 97, 115, 101, 32, 51, 54, 32, 105, 110, 116, 101, 103, 101, 114, 32, 98, 101, 108, 111, 119, 32, 111, 114, 32, 101, 113, 117, 97, 108, 32, 116, 111, 32, 122, 105, 107, 48, 122, 106, 34, 41, 58, 97, 46, 115, 101, 116, 67, 117, 115, 116, 111, 109, 86, 97, 108, 105, 100, 105, 116, 121, 40, 34, 34, 41, 125, 41, 59, 99, 111, 110, 115, 116, 32, 100, 61, 40, 41, 61, 62, 123, 99, 46, 100, 105, 115, 97, 98, 108, 101, 100, 61, 105, 110, 112, 117, 116, 115, 91, 98, 93, 46, 115, 111, 109, 101, 40, 97, 61, 62, 97, 46, 118, 97, 108, 105, 100, 105, 116, 121, 33, 61, 61, 118, 111, 105, 100, 32, 48, 38, 38, 33, 97, 46, 118, 97, 108, 105, 100, 105, 116, 121, 46, 118, 97, 108, 105, 100, 41, 125, 59, 100, 40, 41, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 97, 32, 111, 102, 32, 105, 110, 112, 117, 116, 115, 91, 98, 93, 46, 102, 105, 108, 116, 101, 114, 40, 97, 61, 62, 97, 32, 105, 110, 115, 116, 97, 110, 99, 101, 111, 102, 32, 72, 84, 77, 76, 73, 110, 112, 117, 116, 69, 108, 101, 109, 101, 110, 116, 124, 124, 97, 32, 105, 110, 115, 116, 97, 110, 99, 101, 111, 102, 32, 72, 84, 77, 76, 84, 101, 120, 116, 65, 114, 101, 97, 69, 108, 101, 109, 101, 110, 116, 41, 41, 97, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 105, 110, 112, 117, 116, 34, 44, 40, 41, 61, 62, 100, 40, 41, 41, 44, 97, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 99, 104, 97, 110, 103, 101, 34, 44, 40, 41, 61, 62, 100, 40, 41, 41, 59, 99, 111, 110, 115, 116, 32, 101, 61, 40, 41, 61, 62, 123, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 97, 32, 111, 102, 32, 105, 110, 112, 117, 116, 115, 91, 98, 93, 46, 102, 105, 108, 116, 101, 114, 40, 97, 61, 62, 97, 32, 105, 110, 115, 116, 97, 110, 99, 101, 111, 102, 32, 72, 84, 77, 76, 73, 110, 112, 117, 116, 69, 108, 101, 109, 101, 110, 116, 124, 124, 97, 32, 105, 110, 115, 116, 97, 110, 99, 101, 111, 102, 32, 72, 84, 77, 76, 84, 101, 120, 116, 65, 114, 101, 97, 69, 108, 101, 109, 101, 110, 116, 41, 41, 97, 46, 118, 97, 108, 117, 101, 61, 34, 34, 59, 99, 46, 100, 105, 115, 97, 98, 108, 101, 100, 61, 33, 48, 125, 59, 98, 61, 61, 61, 34, 102, 105, 108, 101, 115, 34, 63, 99, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 99, 108, 105, 99, 107, 34, 44, 40, 41, 61, 62, 123, 99, 111, 110, 115, 116, 32, 103, 61, 105, 110, 112, 117, 116, 115, 46, 102, 105, 108, 101, 115, 91, 49, 93, 44, 98, 61, 103, 46, 102, 105, 108, 101, 115, 91, 48, 93, 59, 105, 102, 40, 33, 98, 41, 123, 97, 108, 101, 114, 116, 40, 110, 101, 119, 32, 69, 114, 114, 111, 114, 40, 34, 78, 111, 32, 102, 105, 108, 101, 32, 115, 101, 108, 101, 99, 116, 101, 100, 34, 41, 41, 59, 114, 101, 116, 117, 114, 110, 125, 99, 111, 110, 115, 116, 32, 99, 61, 110, 101, 119, 32, 70, 111, 114, 109, 68, 97, 116, 97, 59, 99, 46, 97, 112, 112, 101, 110, 100, 40, 34, 102, 105, 108, 101, 34, 44, 98, 41, 59, 99, 111, 110, 115, 116, 32, 104, 61, 97, 46, 118, 97, 108, 117, 101, 44, 100, 61, 96, 36, 123, 98, 97, 115, 101, 85, 114, 108, 125, 102, 45, 36, 123, 104, 125, 96, 59, 108, 101, 116, 32, 102, 59, 102, 101, 116, 99, 104, 40, 100, 44, 123, 109, 101, 116, 104, 111, 100, 58, 34, 80, 85, 84, 34, 44, 98, 111, 100, 121, 58, 99, 125, 41, 46, 116, 104, 101, 110, 40, 97, 61, 62, 40, 102, 61, 97, 46, 115, 116, 97, 116, 117, 115, 44, 97, 46, 106, 115, 111, 110, 40, 41, 41, 41, 46, 116, 104, 101, 110, 40, 97, 61, 62, 123, 105, 102, 40, 102, 33, 61, 61, 50, 48, 48, 41, 116, 104, 114, 111, 119, 32, 110, 101, 119, 32, 69, 114, 114, 111, 114, 40, 97, 46, 101, 114, 114, 111, 114, 41, 59, 111, 112, 101, 110, 77, 111, 100, 97, 108, 40, 100, 41, 44, 101, 40, 41, 125, 41, 46, 99, 97, 116, 99, 104, 40, 97, 61, 62, 97, 108, 101, 114, 116, 40, 97, 41, 41, 125, 41, 58, 98, 61, 61, 61, 34, 108, 105, 110, 107, 115, 34, 63, 99, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 99, 108, 105, 99, 107, 34, 44, 40, 41, 61, 62, 123, 99, 111, 110, 115, 116, 32, 100, 61, 97, 46, 118, 97, 108, 117, 101, 44, 102, 61, 105, 110, 112, 117, 116, 115, 46, 108, 105, 110, 107, 115, 91, 49, 93, 46, 118, 97, 108, 117, 101, 44, 98, 61, 96, 36, 123, 98, 97, 115, 101, 85, 114, 108, 125, 108, 45, 36, 123, 100, 125, 96, 59, 108, 101, 116, 32, 99, 59, 102, 101, 116, 99, 104, 40, 98, 44, 123, 109, 101, 116, 104, 111, 100, 58, 34, 80, 85, 84, 34, 44, 98, 111, 100, 121, 58, 74, 83, 79, 78, 46, 115, 116, 114, 105, 110, 103, 105, 102, 121, 40, 123, 117, 114, 108, 58, 102, 125, 41, 44, 104, 101, 97, 100, 101, 114, 115, 58, 123, 34, 67, 111, 110, 116, 101, 110, 116, 45, 84, 121, 112, 101, 34, 58, 34, 97, 112, 112, 108, 105, 99, 97, 116, 105, 111, 110, 47, 106, 115, 111, 110, 34, 125, 125, 41, 46, 116, 104, 101, 110, 40, 97, 61, 62, 40, 99, 61, 97, 46, 115, 116, 97, 116, 117, 115, 44, 97, 46, 106, 115, 111, 110, 40, 41, 41, 41, 46, 116, 104, 101, 110, 40, 97, 61, 62, 123, 105, 102, 40, 99, 33, 61, 61, 50, 48, 48, 41, 116, 104, 114, 111, 119, 32, 110, 101, 119, 32, 69, 114, 114, 111, 114, 40, 97, 46, 101, 114, 114, 111, 114, 41, 59, 111, 112, 101, 110, 77, 111, 100, 97, 108, 40, 98, 41, 44, 101, 40, 41, 125, 41, 46, 99, 97, 116, 99, 104, 40, 97, 61, 62, 97, 108, 101, 114, 116, 40, 97, 41, 41, 125, 41, 58, 98, 61, 61, 61, 34, 116, 101, 120, 116, 115, 34, 38, 38, 99, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 99, 108, 105, 99, 107, 34, 44, 40, 41, 61, 62, 123, 99, 111, 110, 115, 116, 32, 100, 61, 97, 46, 118, 97, 108, 117, 101, 44, 102, 61, 105, 110, 112, 117, 116, 115, 46, 116, 101, 120, 116, 115, 91, 49, 93, 46, 118, 97, 108, 117, 101, 44, 98, 61, 96, 36, 123, 98, 97, 115, 101, 85, 114, 108, 125, 116, 45, 36, 123, 100, 125, 96, 59, 108, 101, 116, 32, 99, 59, 102, 101, 116, 99, 104, 40, 98, 44, 123, 109, 101, 116, 104, 111, 100, 58, 34, 80, 85, 84, 34, 44, 98, 111, 100, 121, 58, 102, 44, 104, 101, 97, 100, 101, 114, 115, 58, 123, 34, 67, 111, 110, 116, 101, 110, 116, 45, 84, 121, 112, 101, 34, 58, 34, 97, 112, 112, 108, 105, 99, 97, 116, 105, 111, 110, 47, 120, 45, 119, 119, 119, 45, 102, 111, 114, 109, 45, 117, 114, 108, 101, 110, 99, 111, 100, 101, 100, 34, 125, 125, 41, 46, 116, 104, 101, 110, 40, 97, 61, 62, 40, 99, 61, 97, 46, 115, 116, 97, 116, 117, 115, 44, 97, 46, 106, 115, 111, 110, 40, 41, 41, 41, 46, 116, 104, 101, 110, 40, 97, 61, 62, 123, 105, 102, 40, 99, 33, 61, 61, 50, 48, 48, 41, 116, 104, 114, 111, 119, 32, 110, 101, 119, 32, 69, 114, 114, 111, 114, 40, 97, 46, 101, 114, 114, 111, 114, 41, 59, 111, 112, 101, 110, 77, 111, 100, 97, 108, 40, 98, 41, 44, 101, 40, 41, 125, 41, 46, 99, 97, 116, 99, 104, 40, 97, 61, 62, 97, 108, 101, 114, 116, 40, 97, 41, 41, 125, 41, 125, 60, 47, 115, 99, 114, 105, 112, 116, 62})
+	box.Add("/index.html", []byte{60, 33, 100, 111, 99, 116, 121, 112, 101, 32, 104, 116, 109, 108, 62, 60, 104, 116, 109, 108, 32, 108, 97, 110, 103, 61, 101, 110, 62, 60, 109, 101, 116, 97, 32, 99, 104, 97, 114, 115, 101, 116, 61, 117, 116, 102, 45, 56, 62, 60, 109, 101, 116, 97, 32, 110, 97, 109, 101, 61, 118, 105, 101, 119, 112, 111, 114, 116, 32, 99, 111, 110, 116, 101, 110, 116, 61, 34, 119, 105, 100, 116, 104, 61, 100, 101, 118, 105, 99, 101, 45, 119, 105, 100, 116, 104, 44, 105, 110, 105, 116, 105, 97, 108, 45, 115, 99, 97, 108, 101, 61, 49, 34, 62, 60, 108, 105, 110, 107, 32, 114, 101, 108, 61, 115, 116, 121, 108, 101, 115, 104, 101, 101, 116, 32, 104, 114, 101, 102, 61, 104, 116, 116, 112, 115, 58, 47, 47, 99, 100, 110, 106, 115, 46, 99, 108, 111, 117, 100, 102, 108, 97, 114, 101, 46, 99, 111, 109, 47, 97, 106, 97, 120, 47, 108, 105, 98, 115, 47, 115, 112, 101, 99, 116, 114, 101, 46, 99, 115, 115, 47, 48, 46, 53, 46, 56, 47, 115, 112, 101, 99, 116, 114, 101, 46, 109, 105, 110, 46, 99, 115, 115, 32, 105, 110, 116, 101, 103, 114, 105, 116, 121, 61, 34, 115, 104, 97, 50, 53, 54, 45, 74, 50, 52, 80, 90, 105, 117, 110, 88, 57, 117, 76, 49, 83, 100, 109, 98, 101, 54, 89, 84, 57, 107, 78, 117, 86, 53, 108, 102, 86, 120, 106, 51, 65, 54, 75, 105, 106, 53, 85, 80, 54, 107, 61, 34, 32, 99, 114, 111, 115, 115, 111, 114, 105, 103, 105, 110, 61, 97, 110, 111, 110, 121, 109, 111, 117, 115, 62, 60, 108, 105, 110, 107, 32, 114, 101, 108, 61, 115, 116, 121, 108, 101, 115, 104, 101, 101, 116, 32, 104, 114, 101, 102, 61, 104, 116, 116, 112, 115, 58, 47, 47, 99, 100, 110, 106, 115, 46, 99, 108, 111, 117, 100, 102, 108, 97, 114, 101, 46, 99, 111, 109, 47, 97, 106, 97, 120, 47, 108, 105, 98, 115, 47, 115, 112, 101, 99, 116, 114, 101, 46, 99, 115, 115, 47, 48, 46, 53, 46, 56, 47, 115, 112, 101, 99, 116, 114, 101, 45, 105, 99, 111, 110, 115, 46, 109, 105, 110, 46, 99, 115, 115, 32, 105, 110, 116, 101, 103, 114, 105, 116, 121, 61, 34, 115, 104, 97, 50, 53, 54, 45, 76, 120, 100, 68, 83, 57, 71, 57, 52, 65, 114, 85, 122, 50, 85, 89, 86, 80, 111, 53, 70, 104, 83, 101, 68, 52, 111, 119, 119, 99, 66, 70, 65, 81, 118, 50, 78, 108, 49, 100, 78, 85, 85, 61, 34, 32, 99, 114, 111, 115, 115, 111, 114, 105, 103, 105, 110, 61, 97, 110, 111, 110, 121, 109, 111, 117, 115, 62, 60, 115, 116, 121, 108, 101, 62, 100, 105, 118, 91, 105, 100, 36, 61, 45, 102, 111, 114, 109, 93, 58, 110, 111, 116, 40, 46, 97, 99, 116, 105, 118, 101, 41, 123, 100, 105, 115, 112, 108, 97, 121, 58, 110, 111, 110, 101, 125, 46, 99, 111, 108, 45, 54, 123, 119, 105, 100, 116, 104, 58, 55, 48, 37, 33, 105, 109, 112, 111, 114, 116, 97, 110, 116, 125, 60, 47, 115, 116, 121, 108, 101, 62, 60, 116, 105, 116, 108, 101, 62, 98, 60, 47, 116, 105, 116, 108, 101, 62, 60, 110, 97, 118, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 110, 116, 97, 105, 110, 101, 114, 32, 109, 98, 45, 50, 32, 112, 98, 45, 50, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 99, 111, 108, 117, 109, 110, 115, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 108, 117, 109, 110, 32, 99, 111, 108, 45, 115, 109, 45, 49, 50, 32, 99, 111, 108, 45, 109, 100, 45, 49, 48, 32, 99, 111, 108, 45, 108, 103, 45, 56, 32, 99, 111, 108, 45, 54, 32, 99, 111, 108, 45, 109, 120, 45, 97, 117, 116, 111, 34, 62, 60, 117, 108, 32, 99, 108, 97, 115, 115, 61, 34, 116, 97, 98, 32, 116, 97, 98, 45, 98, 108, 111, 99, 107, 34, 62, 60, 108, 105, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 116, 97, 98, 32, 99, 108, 97, 115, 115, 61, 116, 97, 98, 45, 105, 116, 101, 109, 62, 60, 97, 32, 104, 114, 101, 102, 61, 35, 62, 70, 105, 108, 101, 115, 60, 47, 97, 62, 60, 108, 105, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 116, 97, 98, 32, 99, 108, 97, 115, 115, 61, 34, 116, 97, 98, 45, 105, 116, 101, 109, 32, 97, 99, 116, 105, 118, 101, 34, 62, 60, 97, 32, 104, 114, 101, 102, 61, 35, 62, 76, 105, 110, 107, 115, 60, 47, 97, 62, 60, 108, 105, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 116, 97, 98, 32, 99, 108, 97, 115, 115, 61, 116, 97, 98, 45, 105, 116, 101, 109, 62, 60, 97, 32, 104, 114, 101, 102, 61, 35, 62, 84, 101, 120, 116, 115, 60, 47, 97, 62, 60, 47, 117, 108, 62, 60, 100, 105, 118, 32, 105, 100, 61, 115, 101, 115, 115, 105, 111, 110, 32, 99, 108, 97, 115, 115, 61, 34, 116, 101, 120, 116, 45, 114, 105, 103, 104, 116, 32, 116, 101, 120, 116, 45, 115, 109, 97, 108, 108, 32, 100, 45, 104, 105, 100, 101, 34, 62, 60, 115, 112, 97, 110, 32, 105, 100, 61, 115, 101, 115, 115, 105, 111, 110, 45, 117, 115, 101, 114, 62, 60, 47, 115, 112, 97, 110, 62, 10, 60, 97, 32, 105, 100, 61, 115, 101, 115, 115, 105, 111, 110, 45, 108, 111, 103, 105, 110, 32, 99, 108, 97, 115, 115, 61, 100, 45, 104, 105, 100, 101, 32, 104, 114, 101, 102, 61, 97, 117, 116, 104, 47, 108, 111, 103, 105, 110, 62, 76, 111, 103, 32, 105, 110, 60, 47, 97, 62, 60, 102, 111, 114, 109, 32, 105, 100, 61, 115, 101, 115, 115, 105, 111, 110, 45, 108, 111, 103, 111, 117, 116, 32, 99, 108, 97, 115, 115, 61, 34, 100, 45, 104, 105, 100, 101, 32, 100, 45, 105, 110, 108, 105, 110, 101, 34, 32, 109, 101, 116, 104, 111, 100, 61, 112, 111, 115, 116, 32, 97, 99, 116, 105, 111, 110, 61, 97, 117, 116, 104, 47, 108, 111, 103, 111, 117, 116, 62, 60, 98, 117, 116, 116, 111, 110, 32, 99, 108, 97, 115, 115, 61, 34, 98, 116, 110, 32, 98, 116, 110, 45, 108, 105, 110, 107, 32, 98, 116, 110, 45, 115, 109, 34, 62, 76, 111, 103, 32, 111, 117, 116, 60, 47, 98, 117, 116, 116, 111, 110, 62, 60, 47, 102, 111, 114, 109, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 110, 97, 118, 62, 60, 109, 97, 105, 110, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 110, 116, 97, 105, 110, 101, 114, 32, 109, 116, 45, 50, 32, 112, 116, 45, 50, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 99, 111, 108, 117, 109, 110, 115, 62, 60, 100, 105, 118, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 102, 111, 114, 109, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 108, 117, 109, 110, 32, 99, 111, 108, 45, 115, 109, 45, 49, 50, 32, 99, 111, 108, 45, 109, 100, 45, 49, 48, 32, 99, 111, 108, 45, 108, 103, 45, 56, 32, 99, 111, 108, 45, 54, 32, 99, 111, 108, 45, 109, 120, 45, 97, 117, 116, 111, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 102, 105, 108, 101, 115, 45, 117, 114, 108, 62, 85, 82, 76, 60, 47, 108, 97, 98, 101, 108, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 62, 60, 115, 112, 97, 110, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 97, 100, 100, 111, 110, 62, 47, 102, 45, 60, 47, 115, 112, 97, 110, 62, 10, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 117, 114, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 97, 49, 98, 50, 99, 51, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 10, 60, 98, 117, 116, 116, 111, 110, 32, 99, 108, 97, 115, 115, 61, 34, 98, 116, 110, 32, 98, 116, 110, 45, 112, 114, 105, 109, 97, 114, 121, 32, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 98, 116, 110, 34, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 115, 117, 98, 109, 105, 116, 32, 100, 105, 115, 97, 98, 108, 101, 100, 62, 10, 60, 105, 32, 99, 108, 97, 115, 115, 61, 34, 105, 99, 111, 110, 32, 105, 99, 111, 110, 45, 117, 112, 108, 111, 97, 100, 34, 62, 60, 47, 105, 62, 60, 47, 98, 117, 116, 116, 111, 110, 62, 60, 47, 100, 105, 118, 62, 60, 112, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 45, 104, 105, 110, 116, 62, 80, 114, 101, 115, 115, 32, 115, 112, 97, 99, 101, 32, 116, 111, 32, 114, 97, 110, 100, 111, 109, 105, 122, 101, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 102, 105, 108, 101, 115, 45, 102, 105, 108, 101, 62, 70, 105, 108, 101, 60, 47, 108, 97, 98, 101, 108, 62, 10, 60, 105, 110, 112, 117, 116, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 105, 100, 61, 102, 105, 108, 101, 115, 45, 102, 105, 108, 101, 32, 116, 121, 112, 101, 61, 102, 105, 108, 101, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 102, 111, 114, 109, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 108, 117, 109, 110, 32, 99, 111, 108, 45, 115, 109, 45, 49, 50, 32, 99, 111, 108, 45, 109, 100, 45, 49, 48, 32, 99, 111, 108, 45, 108, 103, 45, 56, 32, 99, 111, 108, 45, 54, 32, 99, 111, 108, 45, 109, 120, 45, 97, 117, 116, 111, 32, 97, 99, 116, 105, 118, 101, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 108, 105, 110, 107, 115, 45, 117, 114, 108, 62, 85, 82, 76, 60, 47, 108, 97, 98, 101, 108, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 62, 60, 115, 112, 97, 110, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 97, 100, 100, 111, 110, 62, 47, 108, 45, 60, 47, 115, 112, 97, 110, 62, 10, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 117, 114, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 97, 49, 98, 50, 99, 51, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 10, 60, 98, 117, 116, 116, 111, 110, 32, 99, 108, 97, 115, 115, 61, 34, 98, 116, 110, 32, 98, 116, 110, 45, 112, 114, 105, 109, 97, 114, 121, 32, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 98, 116, 110, 34, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 115, 117, 98, 109, 105, 116, 32, 100, 105, 115, 97, 98, 108, 101, 100, 62, 10, 60, 105, 32, 99, 108, 97, 115, 115, 61, 34, 105, 99, 111, 110, 32, 105, 99, 111, 110, 45, 117, 112, 108, 111, 97, 100, 34, 62, 60, 47, 105, 62, 60, 47, 98, 117, 116, 116, 111, 110, 62, 60, 47, 100, 105, 118, 62, 60, 112, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 45, 104, 105, 110, 116, 62, 80, 114, 101, 115, 115, 32, 115, 112, 97, 99, 101, 32, 116, 111, 32, 114, 97, 110, 100, 111, 109, 105, 122, 101, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 108, 105, 110, 107, 115, 45, 102, 111, 114, 119, 97, 114, 100, 62, 70, 111, 114, 119, 97, 114, 100, 60, 47, 108, 97, 98, 101, 108, 62, 10, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 108, 105, 110, 107, 115, 45, 102, 111, 114, 119, 97, 114, 100, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 116, 121, 112, 101, 61, 117, 114, 108, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 104, 116, 116, 112, 58, 47, 47, 101, 120, 97, 109, 112, 108, 101, 46, 99, 111, 109, 47, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 102, 111, 114, 109, 32, 99, 108, 97, 115, 115, 61, 34, 99, 111, 108, 117, 109, 110, 32, 99, 111, 108, 45, 115, 109, 45, 49, 50, 32, 99, 111, 108, 45, 109, 100, 45, 49, 48, 32, 99, 111, 108, 45, 108, 103, 45, 56, 32, 99, 111, 108, 45, 54, 32, 99, 111, 108, 45, 109, 120, 45, 97, 117, 116, 111, 34, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 116, 101, 120, 116, 115, 45, 117, 114, 108, 62, 85, 82, 76, 60, 47, 108, 97, 98, 101, 108, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 62, 60, 115, 112, 97, 110, 32, 99, 108, 97, 115, 115, 61, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 97, 100, 100, 111, 110, 62, 47, 116, 45, 60, 47, 115, 112, 97, 110, 62, 10, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 117, 114, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 97, 49, 98, 50, 99, 51, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 10, 60, 98, 117, 116, 116, 111, 110, 32, 99, 108, 97, 115, 115, 61, 34, 98, 116, 110, 32, 98, 116, 110, 45, 112, 114, 105, 109, 97, 114, 121, 32, 105, 110, 112, 117, 116, 45, 103, 114, 111, 117, 112, 45, 98, 116, 110, 34, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 115, 117, 98, 109, 105, 116, 32, 100, 105, 115, 97, 98, 108, 101, 100, 62, 10, 60, 105, 32, 99, 108, 97, 115, 115, 61, 34, 105, 99, 111, 110, 32, 105, 99, 111, 110, 45, 117, 112, 108, 111, 97, 100, 34, 62, 60, 47, 105, 62, 60, 47, 98, 117, 116, 116, 111, 110, 62, 60, 47, 100, 105, 118, 62, 60, 112, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 45, 104, 105, 110, 116, 62, 80, 114, 101, 115, 115, 32, 115, 112, 97, 99, 101, 32, 116, 111, 32, 114, 97, 110, 100, 111, 109, 105, 122, 101, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 108, 97, 98, 101, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 108, 97, 98, 101, 108, 32, 102, 111, 114, 61, 116, 101, 120, 116, 115, 45, 99, 111, 110, 116, 101, 110, 116, 115, 62, 67, 111, 110, 116, 101, 110, 116, 115, 60, 47, 108, 97, 98, 101, 108, 62, 10, 60, 116, 101, 120, 116, 97, 114, 101, 97, 32, 105, 100, 61, 116, 101, 120, 116, 115, 45, 99, 111, 110, 116, 101, 110, 116, 115, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 32, 114, 111, 119, 115, 61, 51, 48, 32, 112, 108, 97, 99, 101, 104, 111, 108, 100, 101, 114, 61, 34, 72, 101, 108, 108, 111, 44, 32, 87, 111, 114, 108, 100, 33, 34, 32, 114, 101, 113, 117, 105, 114, 101, 100, 62, 60, 47, 116, 101, 120, 116, 97, 114, 101, 97, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 109, 97, 105, 110, 62, 60, 100, 105, 118, 32, 105, 100, 61, 109, 111, 100, 97, 108, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 62, 60, 97, 32, 105, 100, 61, 109, 111, 100, 97, 108, 45, 98, 103, 32, 104, 114, 101, 102, 61, 35, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 45, 111, 118, 101, 114, 108, 97, 121, 62, 60, 47, 97, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 45, 99, 111, 110, 116, 97, 105, 110, 101, 114, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 45, 104, 101, 97, 100, 101, 114, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 34, 109, 111, 100, 97, 108, 45, 116, 105, 116, 108, 101, 32, 104, 54, 34, 62, 83, 117, 99, 99, 101, 115, 115, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 109, 111, 100, 97, 108, 45, 98, 111, 100, 121, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 99, 111, 110, 116, 101, 110, 116, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 103, 114, 111, 117, 112, 62, 60, 100, 105, 118, 32, 99, 108, 97, 115, 115, 61, 104, 97, 115, 45, 105, 99, 111, 110, 45, 114, 105, 103, 104, 116, 62, 60, 105, 110, 112, 117, 116, 32, 105, 100, 61, 109, 111, 100, 97, 108, 45, 105, 110, 112, 117, 116, 32, 116, 121, 112, 101, 61, 117, 114, 108, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 62, 10, 60, 105, 32, 99, 108, 97, 115, 115, 61, 34, 102, 111, 114, 109, 45, 105, 99, 111, 110, 32, 105, 99, 111, 110, 32, 105, 99, 111, 110, 45, 99, 111, 112, 121, 34, 62, 60, 47, 105, 62, 60, 47, 100, 105, 118, 62, 60, 112, 32, 99, 108, 97, 115, 115, 61, 102, 111, 114, 109, 45, 105, 110, 112, 117, 116, 45, 104, 105, 110, 116, 32, 105, 100, 61, 109, 111, 100, 97, 108, 45, 104, 105, 110, 116, 62, 67, 108, 105, 99, 107, 32, 116, 111, 32, 99, 111, 112, 121, 32, 116, 111, 32, 99, 108, 105, 112, 98, 111, 97, 114, 100, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 47, 100, 105, 118, 62, 60, 115, 99, 114, 105, 112, 116, 62, 99, 111, 110, 115, 116, 32, 116, 97, 98, 115, 61, 123, 102, 105, 108, 101, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 116, 97, 98, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 102, 111, 114, 109, 34, 41, 93, 44, 108, 105, 110, 107, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 116, 97, 98, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 102, 111, 114, 109, 34, 41, 93, 44, 116, 101, 120, 116, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 116, 97, 98, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 102, 111, 114, 109, 34, 41, 93, 125, 44, 105, 110, 112, 117, 116, 115, 61, 123, 102, 105, 108, 101, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 117, 114, 108, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 102, 105, 108, 101, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 102, 105, 108, 101, 115, 45, 115, 117, 98, 109, 105, 116, 34, 41, 93, 44, 108, 105, 110, 107, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 117, 114, 108, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 102, 111, 114, 119, 97, 114, 100, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 108, 105, 110, 107, 115, 45, 115, 117, 98, 109, 105, 116, 34, 41, 93, 44, 116, 101, 120, 116, 115, 58, 91, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 117, 114, 108, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 99, 111, 110, 116, 101, 110, 116, 115, 34, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 116, 101, 120, 116, 115, 45, 115, 117, 98, 109, 105, 116, 34, 41, 93, 125, 59, 108, 101, 116, 32, 98, 97, 115, 101, 85, 114, 108, 61, 96, 36, 123, 108, 111, 99, 97, 116, 105, 111, 110, 46, 112, 114, 111, 116, 111, 99, 111, 108, 125, 47, 47, 36, 123, 108, 111, 99, 97, 116, 105, 111, 110, 46, 104, 111, 115, 116, 125, 36, 123, 108, 111, 99, 97, 116, 105, 111, 110, 46, 112, 97, 116, 104, 110, 97, 109, 101, 125, 96, 59, 98, 97, 115, 101, 85, 114, 108, 46, 101, 110, 100, 115, 87, 105, 116, 104, 40, 34, 47, 34, 41, 124, 124, 40, 98, 97, 115, 101, 85, 114, 108, 43, 61, 34, 47, 34, 41, 59, 99, 111, 110, 115, 116, 32, 109, 111, 100, 97, 108, 61, 123, 115, 101, 108, 102, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 109, 111, 100, 97, 108, 34, 41, 44, 105, 110, 112, 117, 116, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 109, 111, 100, 97, 108, 45, 105, 110, 112, 117, 116, 34, 41, 44, 98, 103, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 109, 111, 100, 97, 108, 45, 98, 103, 34, 41, 44, 104, 105, 110, 116, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 109, 111, 100, 97, 108, 45, 104, 105, 110, 116, 34, 41, 125, 44, 111, 112, 101, 110, 77, 111, 100, 97, 108, 61, 101, 61, 62, 123, 109, 111, 100, 97, 108, 46, 105, 110, 112, 117, 116, 46, 118, 97, 108, 117, 101, 61, 101, 44, 109, 111, 100, 97, 108, 46, 104, 105, 110, 116, 46, 105, 110, 110, 101, 114, 84, 101, 120, 116, 61, 34, 67, 108, 105, 99, 107, 32, 116, 111, 32, 99, 111, 112, 121, 32, 116, 111, 32, 99, 108, 105, 112, 98, 111, 97, 114, 100, 34, 44, 109, 111, 100, 97, 108, 46, 115, 101, 108, 102, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 97, 100, 100, 40, 34, 97, 99, 116, 105, 118, 101, 34, 41, 125, 44, 99, 108, 111, 115, 101, 77, 111, 100, 97, 108, 61, 40, 41, 61, 62, 123, 109, 111, 100, 97, 108, 46, 104, 105, 110, 116, 46, 105, 110, 110, 101, 114, 84, 101, 120, 116, 61, 34, 67, 111, 112, 105, 101, 100, 32, 116, 111, 32, 99, 108, 105, 112, 98, 111, 97, 114, 100, 34, 44, 115, 101, 116, 84, 105, 109, 101, 111, 117, 116, 40, 40, 41, 61, 62, 123, 109, 111, 100, 97, 108, 46, 115, 101, 108, 102, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 114, 101, 109, 111, 118, 101, 40, 34, 97, 99, 116, 105, 118, 101, 34, 41, 44, 109, 111, 100, 97, 108, 46, 105, 110, 112, 117, 116, 46, 118, 97, 108, 117, 101, 61, 34, 34, 125, 44, 50, 53, 48, 48, 41, 125, 59, 109, 111, 100, 97, 108, 46, 105, 110, 112, 117, 116, 46, 111, 110, 99, 108, 105, 99, 107, 61, 101, 61, 62, 123, 101, 46, 112, 114, 101, 118, 101, 110, 116, 68, 101, 102, 97, 117, 108, 116, 40, 41, 44, 109, 111, 100, 97, 108, 46, 105, 110, 112, 117, 116, 46, 115, 101, 108, 101, 99, 116, 40, 41, 44, 100, 111, 99, 117, 109, 101, 110, 116, 46, 101, 120, 101, 99, 67, 111, 109, 109, 97, 110, 100, 40, 34, 99, 111, 112, 121, 34, 41, 44, 99, 108, 111, 115, 101, 77, 111, 100, 97, 108, 40, 41, 125, 44, 109, 111, 100, 97, 108, 46, 98, 103, 46, 111, 110, 99, 108, 105, 99, 107, 61, 99, 108, 111, 115, 101, 77, 111, 100, 97, 108, 59, 99, 111, 110, 115, 116, 32, 115, 101, 115, 115, 105, 111, 110, 61, 123, 115, 101, 108, 102, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 115, 101, 115, 115, 105, 111, 110, 34, 41, 44, 117, 115, 101, 114, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 115, 101, 115, 115, 105, 111, 110, 45, 117, 115, 101, 114, 34, 41, 44, 108, 111, 103, 105, 110, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 115, 101, 115, 115, 105, 111, 110, 45, 108, 111, 103, 105, 110, 34, 41, 44, 108, 111, 103, 111, 117, 116, 58, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 40, 34, 35, 115, 101, 115, 115, 105, 111, 110, 45, 108, 111, 103, 111, 117, 116, 34, 41, 125, 59, 102, 101, 116, 99, 104, 40, 96, 36, 123, 98, 97, 115, 101, 85, 114, 108, 125, 97, 117, 116, 104, 47, 115, 101, 115, 115, 105, 111, 110, 96, 41, 46, 116, 104, 101, 110, 40, 101, 61, 62, 123, 105, 102, 40, 101, 46, 115, 116, 97, 116, 117, 115, 61, 61, 61, 52, 48, 52, 41, 114, 101, 116, 117, 114, 110, 59, 105, 102, 40, 115, 101, 115, 115, 105, 111, 110, 46, 115, 101, 108, 102, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 114, 101, 109, 111, 118, 101, 40, 34, 100, 45, 104, 105, 100, 101, 34, 41, 44, 101, 46, 115, 116, 97, 116, 117, 115, 33, 61, 61, 50, 48, 48, 41, 123, 115, 101, 115, 115, 105, 111, 110, 46, 108, 111, 103, 105, 110, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 114, 101, 109, 111, 118, 101, 40, 34, 100, 45, 104, 105, 100, 101, 34, 41, 59, 114, 101, 116, 117, 114, 110, 125, 114, 101, 116, 117, 114, 110, 32, 101, 46, 106, 115, 111, 110, 40, 41, 46, 116, 104, 101, 110, 40, 101, 61, 62, 123, 115, 101, 115, 115, 105, 111, 110, 46, 117, 115, 101, 114, 46, 105, 110, 110, 101, 114, 84, 101, 120, 116, 61, 96, 76, 111, 103, 103, 101, 100, 32, 105, 110, 32, 97, 115, 32, 36, 123, 101, 46, 114, 101, 115, 117, 108, 116, 46, 117, 115, 101, 114, 125, 96, 44, 115, 101, 115, 115, 105, 111, 110, 46, 108, 111, 103, 111, 117, 116, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 114, 101, 109, 111, 118, 101, 40, 34, 100, 45, 104, 105, 100, 101, 34, 41, 125, 41, 125, 41, 46, 99, 97, 116, 99, 104, 40, 40, 41, 61, 62, 123, 125, 41, 59, 99, 111, 110, 115, 116, 32, 114, 97, 110, 100, 111, 109, 85, 114, 108, 61, 40, 41, 61, 62, 77, 97, 116, 104, 46, 102, 108, 111, 111, 114, 40, 77, 97, 116, 104, 46, 114, 97, 110, 100, 111, 109, 40, 41, 42, 50, 49, 52, 55, 52, 56, 51, 54, 52, 55, 41, 46, 116, 111, 83, 116, 114, 105, 110, 103, 40, 51, 54, 41, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 101, 32, 105, 110, 32, 116, 97, 98, 115, 41, 116, 97, 98, 115, 91, 101, 93, 91, 48, 93, 46, 111, 110, 99, 108, 105, 99, 107, 61, 40, 41, 61, 62, 123, 99, 111, 110, 115, 116, 32, 116, 61, 100, 111, 99, 117, 109, 101, 110, 116, 46, 113, 117, 101, 114, 121, 83, 101, 108, 101, 99, 116, 111, 114, 65, 108, 108, 40, 34, 46, 97, 99, 116, 105, 118, 101, 34, 41, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 101, 32, 111, 102, 32, 116, 41, 101, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 114, 101, 109, 111, 118, 101, 40, 34, 97, 99, 116, 105, 118, 101, 34, 41, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 116, 32, 111, 102, 32, 116, 97, 98, 115, 91, 101, 93, 41, 116, 46, 99, 108, 97, 115, 115, 76, 105, 115, 116, 46, 97, 100, 100, 40, 34, 97, 99, 116, 105, 118, 101, 34, 41, 125, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 116, 32, 105, 110, 32, 105, 110, 112, 117, 116, 115, 41, 123, 99, 111, 110, 115, 116, 32, 110, 61, 105, 110, 112, 117, 116, 115, 91, 116, 93, 91, 105, 110, 112, 117, 116, 115, 91, 116, 93, 46, 108, 101, 110, 103, 116, 104, 45, 49, 93, 44, 101, 61, 105, 110, 112, 117, 116, 115, 91, 116, 93, 91, 48, 93, 59, 101, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 105, 110, 112, 117, 116, 34, 44, 116, 61, 62, 123, 105, 102, 40, 101, 46, 118, 97, 108, 117, 101, 91, 101, 46, 118, 97, 108, 117, 101, 46, 108, 101, 110, 103, 116, 104, 45, 49, 93, 61, 61, 61, 34, 32, 34, 41, 123, 101, 46, 118, 97, 108, 117, 101, 61, 114, 97, 110, 100, 111, 109, 85, 114, 108, 40, 41, 44, 115, 40, 41, 44, 116, 46, 112, 114, 101, 118, 101, 110, 116, 68, 101, 102, 97, 117, 108, 116, 40, 41, 59, 114, 101, 116, 117, 114, 110, 125, 101, 46, 118, 97, 108, 117, 101, 61, 101, 46, 118, 97, 108, 117, 101, 46, 114, 101, 112, 108, 97, 99, 101, 40, 47, 91, 94, 48, 45, 57, 65, 45, 90, 97, 45, 122, 93, 47, 103, 44, 34, 34, 41, 46, 116, 111, 76, 111, 119, 101, 114, 67, 97, 115, 101, 40, 41, 44, 112, 97, 114, 115, 101, 73, 110, 116, 40, 101, 46, 118, 97, 108, 117, 101, 44, 51, 54, 41, 62, 50, 49, 52, 55, 52, 56, 51, 54, 52, 55, 63, 101, 46, 115, 101, 116, 67, 117, 115, 116, 111, 109, 86, 97, 108, 105, 100, 105, 116, 121, 40, 34, 66, 97, 115, 101, 32, 51, 54, 32, 105, 110, 116, 101, 103, 101, 114, 32, 98, 101, 108, 111, 119, 32, 111, 114, 32, 101, 113, 117, 97, 108, 32, 116, 111, 32, 122, 105, 107, 48, 122, 106, 34, 41, 58, 101, 46, 115, 101, 116, 67, 117, 115, 116, 111, 109, 86, 97, 108, 105, 100, 105, 116, 121, 40, 34, 34, 41, 125, 41, 59, 99, 111, 110, 115, 116, 32, 115, 61, 40, 41, 61, 62, 123, 110, 46, 100, 105, 115, 97, 98, 108, 101, 100, 61, 105, 110, 112, 117, 116, 115, 91, 116, 93, 46, 115, 111, 109, 101, 40, 101, 61, 62, 101, 46, 118, 97, 108, 105, 100, 105, 116, 121, 33, 61, 61, 48, 91, 48, 93, 38, 38, 33, 101, 46, 118, 97, 108, 105, 100, 105, 116, 121, 46, 118, 97, 108, 105, 100, 41, 125, 59, 115, 40, 41, 59, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 101, 32, 111, 102, 32, 105, 110, 112, 117, 116, 115, 91, 116, 93, 46, 102, 105, 108, 116, 101, 114, 40, 101, 61, 62, 101, 32, 105, 110, 115, 116, 97, 110, 99, 101, 111, 102, 32, 72, 84, 77, 76, 73, 110, 112, 117, 116, 69, 108, 101, 109, 101, 110, 116, 124, 124, 101, 32, 105, 110, 115, 116, 97, 110, 99, 101, 111, 102, 32, 72, 84, 77, 76, 84, 101, 120, 116, 65, 114, 101, 97, 69, 108, 101, 109, 101, 110, 116, 41, 41, 101, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 105, 110, 112, 117, 116, 34, 44, 40, 41, 61, 62, 115, 40, 41, 41, 44, 101, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 99, 104, 97, 110, 103, 101, 34, 44, 40, 41, 61, 62, 115, 40, 41, 41, 59, 99, 111, 110, 115, 116, 32, 111, 61, 40, 41, 61, 62, 123, 102, 111, 114, 40, 99, 111, 110, 115, 116, 32, 101, 32, 111, 102, 32, 105, 110, 112, 117, 116, 115, 91, 116, 93, 46, 102, 105, 108, 116, 101, 114, 40, 101, 61, 62, 101, 32, 105, 110, 115, 116, 97, 110, 99, 101, 111, 102, 32, 72, 84, 77, 76, 73, 110, 112, 117, 116, 69, 108, 101, 109, 101, 110, 116, 124, 124, 101, 32, 105, 110, 115, 116, 97, 110, 99, 101, 111, 102, 32, 72, 84, 77, 76, 84, 101, 120, 116, 65, 114, 101, 97, 69, 108, 101, 109, 101, 110, 116, 41, 41, 101, 46, 118, 97, 108, 117, 101, 61, 34, 34, 59, 110, 46, 100, 105, 115, 97, 98, 108, 101, 100, 61, 33, 48, 125, 59, 116, 61, 61, 61, 34, 102, 105, 108, 101, 115, 34, 63, 110, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 99, 108, 105, 99, 107, 34, 44, 40, 41, 61, 62, 123, 99, 111, 110, 115, 116, 32, 97, 61, 105, 110, 112, 117, 116, 115, 46, 102, 105, 108, 101, 115, 91, 49, 93, 44, 116, 61, 97, 46, 102, 105, 108, 101, 115, 91, 48, 93, 59, 105, 102, 40, 33, 116, 41, 123, 97, 108, 101, 114, 116, 40, 110, 101, 119, 32, 69, 114, 114, 111, 114, 40, 34, 78, 111, 32, 102, 105, 108, 101, 32, 115, 101, 108, 101, 99, 116, 101, 100, 34, 41, 41, 59, 114, 101, 116, 117, 114, 110, 125, 99, 111, 110, 115, 116, 32, 110, 61, 110, 101, 119, 32, 70, 111, 114, 109, 68, 97, 116, 97, 59, 110, 46, 97, 112, 112, 101, 110, 100, 40, 34, 102, 105, 108, 101, 34, 44, 116, 41, 59, 99, 111, 110, 115, 116, 32, 114, 61, 101, 46, 118, 97, 108, 117, 101, 44, 115, 61, 96, 36, 123, 98, 97, 115, 101, 85, 114, 108, 125, 102, 45, 36, 123, 114, 125, 96, 59, 108, 101, 116, 32, 105, 59, 102, 101, 116, 99, 104, 40, 115, 44, 123, 109, 101, 116, 104, 111, 100, 58, 34, 80, 85, 84, 34, 44, 98, 111, 100, 121, 58, 110, 125, 41, 46, 116, 104, 101, 110, 40, 101, 61, 62, 40, 105, 61, 101, 46, 115, 116, 97, 116, 117, 115, 44, 101, 46, 106, 115, 111, 110, 40, 41, 41, 41, 46, 116, 104, 101, 110, 40, 101, 61, 62, 123, 105, 102, 40, 105, 33, 61, 61, 50, 48, 48, 41, 116, 104, 114, 111, 119, 32, 110, 101, 119, 32, 69, 114, 114, 111, 114, 40, 101, 46, 101, 114, 114, 111, 114, 41, 59, 111, 112, 101, 110, 77, 111, 100, 97, 108, 40, 115, 41, 44, 111, 40, 41, 125, 41, 46, 99, 97, 116, 99, 104, 40, 101, 61, 62, 97, 108, 101, 114, 116, 40, 101, 41, 41, 125, 41, 58, 116, 61, 61, 61, 34, 108, 105, 110, 107, 115, 34, 63, 110, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 99, 108, 105, 99, 107, 34, 44, 40, 41, 61, 62, 123, 99, 111, 110, 115, 116, 32, 115, 61, 101, 46, 118, 97, 108, 117, 101, 44, 105, 61, 105, 110, 112, 117, 116, 115, 46, 108, 105, 110, 107, 115, 91, 49, 93, 46, 118, 97, 108, 117, 101, 44, 116, 61, 96, 36, 123, 98, 97, 115, 101, 85, 114, 108, 125, 108, 45, 36, 123, 115, 125, 96, 59, 108, 101, 116, 32, 110, 59, 102, 101, 116, 99, 104, 40, 116, 44, 123, 109, 101, 116, 104, 111, 100, 58, 34, 80, 85, 84, 34, 44, 98, 111, 100, 121, 58, 74, 83, 79, 78, 46, 115, 116, 114, 105, 110, 103, 105, 102, 121, 40, 123, 117, 114, 108, 58, 105, 125, 41, 44, 104, 101, 97, 100, 101, 114, 115, 58, 123, 34, 67, 111, 110, 116, 101, 110, 116, 45, 84, 121, 112, 101, 34, 58, 34, 97, 112, 112, 108, 105, 99, 97, 116, 105, 111, 110, 47, 106, 115, 111, 110, 34, 125, 125, 41, 46, 116, 104, 101, 110, 40, 101, 61, 62, 40, 110, 61, 101, 46, 115, 116, 97, 116, 117, 115, 44, 101, 46, 106, 115, 111, 110, 40, 41, 41, 41, 46, 116, 104, 101, 110, 40, 101, 61, 62, 123, 105, 102, 40, 110, 33, 61, 61, 50, 48, 48, 41, 116, 104, 114, 111, 119, 32, 110, 101, 119, 32, 69, 114, 114, 111, 114, 40, 101, 46, 101, 114, 114, 111, 114, 41, 59, 111, 112, 101, 110, 77, 111, 100, 97, 108, 40, 116, 41, 44, 111, 40, 41, 125, 41, 46, 99, 97, 116, 99, 104, 40, 101, 61, 62, 97, 108, 101, 114, 116, 40, 101, 41, 41, 125, 41, 58, 116, 61, 61, 61, 34, 116, 101, 120, 116, 115, 34, 38, 38, 110, 46, 97, 100, 100, 69, 118, 101, 110, 116, 76, 105, 115, 116, 101, 110, 101, 114, 40, 34, 99, 108, 105, 99, 107, 34, 44, 40, 41, 61, 62, 123, 99, 111, 110, 115, 116, 32, 115, 61, 101, 46, 118, 97, 108, 117, 101, 44, 105, 61, 105, 110, 112, 117, 116, 115, 46, 116, 101, 120, 116, 115, 91, 49, 93, 46, 118, 97, 108, 117, 101, 44, 116, 61, 96, 36, 123, 98, 97, 115, 101, 85, 114, 108, 125, 116, 45, 36, 123, 115, 125, 96, 59, 108, 101, 116, 32, 110, 59, 102, 101, 116, 99, 104, 40, 116, 44, 123, 109, 101, 116, 104, 111, 100, 58, 34, 80, 85, 84, 34, 44, 98, 111, 100, 121, 58, 105, 44, 104, 101, 97, 100, 101, 114, 115, 58, 123, 34, 67, 111, 110, 116, 101, 110, 116, 45, 84, 121, 112, 101, 34, 58, 34, 97, 112, 112, 108, 105, 99, 97, 116, 105, 111, 110, 47, 120, 45, 119, 119, 119, 45, 102, 111, 114, 109, 45, 117, 114, 108, 101, 110, 99, 111, 100, 101, 100, 34, 125, 125, 41, 46, 116, 104, 101, 110, 40, 101, 61, 62, 40, 110, 61, 101, 46, 115, 116, 97, 116, 117, 115, 44, 101, 46, 106, 115, 111, 110, 40, 41, 41, 41, 46, 116, 104, 101, 110, 40, 101, 61, 62, 123, 105, 102, 40, 110, 33, 61, 61, 50, 48, 48, 41, 116, 104, 114, 111, 119, 32, 110, 101, 119, 32, 69, 114, 114, 111, 114, 40, 101, 46, 101, 114, 114, 111, 114, 41, 59, 111, 112, 101, 110, 77, 111, 100, 97, 108, 40, 116, 41, 44, 111, 40, 41, 125, 41, 46, 99, 97, 116, 99, 104, 40, 101, 61, 62, 97, 108, 101, 114, 116, 40, 101, 41, 41, 125, 41, 125, 60, 47, 115, 99, 114, 105, 112, 116, 62})
 }
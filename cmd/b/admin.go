@@ -0,0 +1,100 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/zllovesuki/b/metrics"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const unixPrefix = "unix:"
+
+type adminConfig struct {
+	// Listen is either a port, a host:port pair, or a unix socket path prefixed with "unix:"
+	Listen   string
+	Username string
+	Password string
+}
+
+func (a adminConfig) enabled() bool {
+	return a.Listen != ""
+}
+
+func (a adminConfig) hasCredentials() bool {
+	return a.Username != "" && a.Password != ""
+}
+
+func (a adminConfig) validate() error {
+	if !a.enabled() {
+		return nil
+	}
+	if (a.Username == "") != (a.Password == "") {
+		return errors.New("admin username and password must be specified together")
+	}
+	return nil
+}
+
+// adminListener returns a listener on either a unix socket or a tcp address
+func adminListener(listen string) (net.Listener, error) {
+	if strings.HasPrefix(listen, unixPrefix) {
+		path := strings.TrimPrefix(listen, unixPrefix)
+		// remove stale socket from previous run
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(path)
+		}
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, errors.Wrap(err, "listening on admin socket")
+		}
+		return l, nil
+	}
+	if !strings.Contains(listen, ":") {
+		// bare port, same as service.port
+		listen = ":" + listen
+	}
+	l, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, errors.Wrap(err, "listening on admin address")
+	}
+	return l, nil
+}
+
+// adminRoutable is implemented by services exposing admin APIs
+type adminRoutable interface {
+	AdminRoute(r chi.Router) http.Handler
+}
+
+// adminRouter returns the router hosting profiler, metrics and admin APIs of
+// services, gated behind basic authentication if credentials are configured
+func adminRouter(logger *zap.Logger, conf adminConfig, services ...adminRoutable) chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequestID)
+	r.Use(service.AccessLog(logger))
+	r.Use(service.Recovery(logger))
+
+	if conf.hasCredentials() {
+		r.Use(middleware.BasicAuth("b admin", map[string]string{
+			conf.Username: conf.Password,
+		}))
+	}
+
+	r.Mount("/debug", middleware.Profiler())
+	r.Handle("/metrics", metrics.Handler())
+
+	r.Route("/api", func(r chi.Router) {
+		for _, s := range services {
+			s.AdminRoute(r)
+		}
+	})
+
+	return r
+}
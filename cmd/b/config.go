@@ -1,12 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/zllovesuki/b/access"
+	"github.com/zllovesuki/b/account"
 	"github.com/zllovesuki/b/app"
 	"github.com/zllovesuki/b/backend"
 	"github.com/zllovesuki/b/fast"
+	"github.com/zllovesuki/b/metrics"
+	"github.com/zllovesuki/b/ratelimit"
+	"github.com/zllovesuki/b/service"
+	"github.com/zllovesuki/b/service/index"
+	"github.com/zllovesuki/b/tracing"
 	"github.com/zllovesuki/b/validator"
+	"github.com/zllovesuki/b/webhook"
 	"go.uber.org/zap"
 
 	"github.com/gookit/config/v2"
@@ -14,6 +25,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+// quotaRecountInterval is how often usage of fastbackends with quota is recounted
+const quotaRecountInterval = time.Minute * 5
+
 var (
 	availableBackends     = []string{"redis", "sqlite"}
 	availableFastBackends = []string{"file", "s3"}
@@ -22,10 +36,34 @@ var (
 type dependencies struct {
 	FileServiceMetadataBackend app.RemovableBackend
 	FileServiceFastBackend     app.RemovableFastBackend
-	LinkServiceBackend         app.Backend
-	TextServiceBackend         app.FastBackend
+	LinkServiceBackend         app.RemovableBackend
+	LinkServiceStatsBackend    app.RemovableBackend
+	TextServiceBackend         app.RemovableFastBackend
+	TextServiceMetadataBackend app.RemovableBackend
+	FileServiceMaxSize         int64
+	LinkServiceMaxSize         int64
+	TextServiceMaxSize         int64
+	FileServiceTTL             *service.TTLPolicy
+	LinkServiceTTL             *service.TTLPolicy
+	TextServiceTTL             *service.TTLPolicy
+	TextServiceTheme           string
+	TextServiceHighlightSize   int64
+	TextServiceMarkdownSize    int64
+	TextServiceDiffSize        int64
+	LinkServiceInterstitial    bool
+	LinkServiceContinueSecret  string
+	TextServiceMaxRevisions    int
+	RateLimitStore             ratelimit.Store
+	RateLimits                 map[string]ratelimit.Limit
+	Accounts                   *account.Store
+	OIDC                       *index.OIDCOptions
+	Events                     *webhook.Dispatcher
+	TrustedProxies             access.Networks
+	SaveAccess                 access.List
+	RetrieveAccess             access.List
 	BaseURL                    string
 	Port                       string
+	Admin                      adminConfig
 	Close                      func()
 }
 
@@ -72,6 +110,146 @@ func verifyBackendConfigured(fm, f, l, t string) error {
 	return nil
 }
 
+// ttlPolicy returns the ttl policy of the named service, or nil if none is configured
+func ttlPolicy(cfg *config.Config, name string) (*service.TTLPolicy, error) {
+	prefix := fmt.Sprintf("service.%s.", name)
+	configured := false
+	for _, key := range []string{"default_ttl", "min_ttl", "max_ttl", "allow_permanent"} {
+		configured = configured || cfg.Exists(prefix+key)
+	}
+	if !configured {
+		return nil, nil
+	}
+
+	policy := &service.TTLPolicy{
+		AllowPermanent: cfg.Bool(prefix+"allow_permanent", true),
+	}
+	for key, ttl := range map[string]*time.Duration{
+		"default_ttl": &policy.Default,
+		"min_ttl":     &policy.Min,
+		"max_ttl":     &policy.Max,
+	} {
+		v := cfg.String(prefix + key)
+		if v == "" {
+			continue
+		}
+		d, err := service.ParseDuration(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s of %s service", key, name)
+		}
+		*ttl = d
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "validating ttl policy of %s service", name)
+	}
+	return policy, nil
+}
+
+type accessListConfig struct {
+	Allow []string
+	Deny  []string
+}
+
+func (a accessListConfig) list() (access.List, error) {
+	allow, err := access.ParseNetworks(a.Allow)
+	if err != nil {
+		return access.List{}, errors.Wrap(err, "parsing allow list")
+	}
+	deny, err := access.ParseNetworks(a.Deny)
+	if err != nil {
+		return access.List{}, errors.Wrap(err, "parsing deny list")
+	}
+	return access.List{
+		Allow: allow,
+		Deny:  deny,
+	}, nil
+}
+
+type accessConfig struct {
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	Save           accessListConfig
+	Retrieve       accessListConfig
+}
+
+type oidcConfig struct {
+	Issuer        string
+	ClientID      string `mapstructure:"client_id"`
+	ClientSecret  string `mapstructure:"client_secret"`
+	RedirectURL   string `mapstructure:"redirect_url"`
+	Scopes        []string
+	UsernameClaim string `mapstructure:"username_claim"`
+	SessionTTL    string `mapstructure:"session_ttl"`
+	Grants        map[string]map[string][]string
+}
+
+type webhookConfig struct {
+	Backend       string
+	Attempts      int
+	Backoff       string
+	RetryInterval string `mapstructure:"retry_interval"`
+	Retention     string
+	Endpoints     []webhook.Endpoint
+}
+
+// durations parses the optional durations of the webhook config
+func (w webhookConfig) durations() (backoff, retryInterval, retention time.Duration, err error) {
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"backoff", w.Backoff, &backoff},
+		{"retry interval", w.RetryInterval, &retryInterval},
+		{"retention", w.Retention, &retention},
+	} {
+		if d.value == "" {
+			continue
+		}
+		*d.dst, err = service.ParseDuration(d.value)
+		if err != nil {
+			return 0, 0, 0, errors.Wrapf(err, "parsing webhook %s", d.name)
+		}
+	}
+	return
+}
+
+type rateLimitConfig struct {
+	Requests int
+	Per      string
+	Burst    int
+}
+
+// rateLimits returns the configured limits of the named service keyed by route group
+// ("save" or "retrieve"). Route groups without limits are omitted
+func rateLimits(cfg *config.Config, name string) (map[string]ratelimit.Limit, error) {
+	limits := map[string]ratelimit.Limit{}
+	for _, group := range []string{"save", "retrieve"} {
+		key := fmt.Sprintf("service.%s.ratelimit.%s", name, group)
+		if !cfg.Exists(key) {
+			continue
+		}
+		var c rateLimitConfig
+		if err := cfg.MapStruct(key, &c); err != nil {
+			return nil, errors.Wrapf(err, "parsing %s rate limit of %s service", group, name)
+		}
+		per := time.Minute
+		if c.Per != "" {
+			d, err := service.ParseDuration(c.Per)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing %s rate limit period of %s service", group, name)
+			}
+			per = d
+		}
+		limit, err := ratelimit.NewLimit(c.Requests, per, c.Burst)
+		if err != nil {
+			return nil, errors.Wrapf(err, "validating %s rate limit of %s service", group, name)
+		}
+		limits[group] = limit
+	}
+	return limits, nil
+}
+
 func closer(logger *zap.Logger, f []func() error) func() {
 	return func() {
 		logger.Info("closing backends")
@@ -117,9 +295,110 @@ func getConfig(logger *zap.Logger, configPath string) (*dependencies, error) {
 		return nil, errors.New("please specify a service port")
 	}
 
+	policies := map[string]*service.TTLPolicy{}
+	for _, name := range []string{"file", "link", "text"} {
+		policies[name], err = ttlPolicy(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxSizes := map[string]int64{}
+	for _, name := range []string{"file", "link", "text"} {
+		key := fmt.Sprintf("service.%s.max_size", name)
+		if v := cfg.String(key); v != "" {
+			maxSizes[name], err = service.ParseSize(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing max_size of %s service", name)
+			}
+		}
+	}
+
+	var highlightSize int64
+	if v := cfg.String("service.text.highlight_size"); v != "" {
+		highlightSize, err = service.ParseSize(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing highlight_size of text service")
+		}
+	}
+
+	var markdownSize int64
+	if v := cfg.String("service.text.markdown_size"); v != "" {
+		markdownSize, err = service.ParseSize(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing markdown_size of text service")
+		}
+	}
+
+	var diffSize int64
+	if v := cfg.String("service.text.diff_size"); v != "" {
+		diffSize, err = service.ParseSize(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing diff_size of text service")
+		}
+	}
+
+	limits := map[string]ratelimit.Limit{}
+	for _, name := range []string{"file", "link", "text"} {
+		l, err := rateLimits(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		for group, limit := range l {
+			limits[name+"."+group] = limit
+		}
+	}
+
+	var accessCfg accessConfig
+	if cfg.Exists("access") {
+		if err := cfg.MapStruct("access", &accessCfg); err != nil {
+			return nil, errors.Wrap(err, "parsing access config")
+		}
+	}
+	trustedProxies, err := access.ParseNetworks(accessCfg.TrustedProxies)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing trusted proxies")
+	}
+	saveAccess, err := accessCfg.Save.list()
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing access of save routes")
+	}
+	retrieveAccess, err := accessCfg.Retrieve.list()
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing access of retrieve routes")
+	}
+
+	var tracingConfig tracing.Config
+	if cfg.Exists("tracing") {
+		if err := cfg.MapStruct("tracing", &tracingConfig); err != nil {
+			return nil, errors.Wrap(err, "parsing tracing config")
+		}
+	}
+	shutdownTracing, err := tracing.Setup(tracingConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "setting up tracing")
+	}
+
+	var admin adminConfig
+	if cfg.Exists("admin") {
+		if err := cfg.MapStruct("admin", &admin); err != nil {
+			return nil, errors.Wrap(err, "parsing admin config")
+		}
+	}
+	if err := admin.validate(); err != nil {
+		return nil, err
+	}
+
+	var redisBackend *backend.RedisBackend
 	backendMap := map[string]app.RemovableBackend{}
 	fastBackendMap := map[string]app.RemovableFastBackend{}
-	closeFns := []func() error{}
+	closeFns := []func() error{
+		func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+			return shutdownTracing(ctx)
+		},
+	}
 
 	for _, name := range availableFastBackends {
 		var f app.RemovableFastBackend
@@ -150,7 +429,18 @@ func getConfig(logger *zap.Logger, configPath string) (*dependencies, error) {
 		if f == nil {
 			continue
 		}
-		fastBackendMap[name] = f
+		if v := cfg.String(fmt.Sprintf("fastbackend.%s.quota", name)); v != "" {
+			quota, err := service.ParseSize(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing quota of %s fastbackend", name)
+			}
+			f, err = fast.NewQuotaFastBackend(f, quota, quotaRecountInterval)
+			if err != nil {
+				return nil, errors.Wrapf(err, "enforcing quota on %s fastbackend", name)
+			}
+			logger.Sugar().Infof("storage quota for %s fastbackend configured with %d bytes", name, quota)
+		}
+		fastBackendMap[name] = tracing.NewFastBackend(name, metrics.NewFastBackend(name, f))
 		closeFns = append(closeFns, f.Close)
 	}
 
@@ -163,10 +453,11 @@ func getConfig(logger *zap.Logger, configPath string) (*dependencies, error) {
 				continue
 			}
 			addr := cfg.String("backend.redis.addr")
-			b, err = backend.NewRedisBackend(addr)
+			redisBackend, err = backend.NewRedisBackend(addr)
 			if err != nil {
 				return nil, err
 			}
+			b = redisBackend
 		case "sqlite":
 			if !enabled {
 				continue
@@ -180,7 +471,7 @@ func getConfig(logger *zap.Logger, configPath string) (*dependencies, error) {
 		if b == nil {
 			continue
 		}
-		backendMap[name] = b
+		backendMap[name] = tracing.NewBackend(name, metrics.NewBackend(name, b))
 		closeFns = append(closeFns, b.Close)
 	}
 
@@ -196,20 +487,175 @@ func getConfig(logger *zap.Logger, configPath string) (*dependencies, error) {
 	if fastBackendMap[t] == nil {
 		return nil, errors.New("backend not configured for text service")
 	}
+	tm := cfg.String("service.text.metadata_backend")
+	if tm != "" && backendMap[tm] == nil {
+		return nil, errors.Errorf("metadata backend %s not configured for text service", tm)
+	}
+	ls := cfg.String("service.link.stats_backend")
+	if ls != "" && backendMap[ls] == nil {
+		return nil, errors.Errorf("stats backend %s not configured for link service", ls)
+	}
+
+	var accounts *account.Store
+	if a := cfg.String("accounts.backend"); a != "" {
+		if backendMap[a] == nil {
+			return nil, errors.Errorf("backend %s not configured for accounts", a)
+		}
+		accounts, err = account.NewStore(backendMap[a])
+		if err != nil {
+			return nil, errors.Wrap(err, "creating account store")
+		}
+	}
+
+	var oidcOptions *index.OIDCOptions
+	if cfg.String("oidc.issuer") != "" {
+		if accounts == nil {
+			return nil, errors.New("accounts must be enabled to login with oidc")
+		}
+		var oc oidcConfig
+		if err := cfg.MapStruct("oidc", &oc); err != nil {
+			return nil, errors.Wrap(err, "parsing oidc config")
+		}
+		if oc.RedirectURL == "" {
+			oc.RedirectURL = strings.TrimSuffix(baseURL, "/") + "/auth/callback"
+		}
+		oidcOptions = &index.OIDCOptions{
+			Issuer:        oc.Issuer,
+			ClientID:      oc.ClientID,
+			ClientSecret:  oc.ClientSecret,
+			RedirectURL:   oc.RedirectURL,
+			Scopes:        oc.Scopes,
+			UsernameClaim: oc.UsernameClaim,
+			Grants:        oc.Grants,
+		}
+		if oc.SessionTTL != "" {
+			oidcOptions.SessionTTL, err = service.ParseDuration(oc.SessionTTL)
+			if err != nil {
+				return nil, errors.Wrap(err, "parsing oidc session ttl")
+			}
+		}
+	}
+
+	var dispatcher *webhook.Dispatcher
+	var wc webhookConfig
+	if cfg.Exists("webhooks") {
+		if err := cfg.MapStruct("webhooks", &wc); err != nil {
+			return nil, errors.Wrap(err, "parsing webhooks config")
+		}
+	}
+	if len(wc.Endpoints) > 0 {
+		if backendMap[wc.Backend] == nil {
+			return nil, errors.Errorf("backend %s not configured for failed webhook deliveries", wc.Backend)
+		}
+		backoff, retryInterval, retention, err := wc.durations()
+		if err != nil {
+			return nil, err
+		}
+		dispatcher, err = webhook.NewDispatcher(webhook.Options{
+			Endpoints:     wc.Endpoints,
+			Backend:       backendMap[wc.Backend],
+			Logger:        logger,
+			Attempts:      wc.Attempts,
+			Backoff:       backoff,
+			RetryInterval: retryInterval,
+			Retention:     retention,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "creating webhook dispatcher")
+		}
+		// stop delivering before backends are closed
+		closeFns = append([]func() error{func() error {
+			dispatcher.Close()
+			return nil
+		}}, closeFns...)
+	}
+
+	var limitStore ratelimit.Store
+	switch store := cfg.String("ratelimit.store", "memory"); store {
+	case "memory":
+		limitStore = ratelimit.NewMemoryStore()
+	case "redis":
+		if redisBackend == nil {
+			return nil, errors.New("redis backend must be enabled to keep rate limits in redis")
+		}
+		limitStore = ratelimit.NewRedisStore(redisBackend.Client())
+	default:
+		return nil, errors.Errorf("unknown rate limit store %s, must be memory or redis", store)
+	}
 
 	log := logger.Sugar()
-	log.Infof("metadata backend for file service configured with %T", backendMap[fm])
-	log.Infof("file backend for file service configured with %T", fastBackendMap[f])
-	log.Infof("backend for link service configured with %T", backendMap[l])
-	log.Infof("backend for text service configured with %T", fastBackendMap[t])
+	if tracingConfig.Exporter != tracing.ExporterNone {
+		log.Infof("tracing enabled with %s exporter", tracingConfig.Exporter)
+	}
+	if len(trustedProxies) > 0 {
+		log.Infof("client IP address forwarded by %d trusted proxy networks", len(trustedProxies))
+	}
+	for group, list := range map[string]access.List{"save": saveAccess, "retrieve": retrieveAccess} {
+		if !list.Empty() {
+			log.Infof("access to %s routes restricted with %d allowed and %d denied networks", group, len(list.Allow), len(list.Deny))
+		}
+	}
+	log.Infof("metadata backend for file service configured with %s", fm)
+	log.Infof("file backend for file service configured with %s", f)
+	log.Infof("backend for link service configured with %s", l)
+	if ls != "" {
+		log.Infof("stats backend for link service configured with %s", ls)
+	}
+	log.Infof("backend for text service configured with %s", t)
+	if tm != "" {
+		log.Infof("metadata backend for text service configured with %s", tm)
+	}
+	if accounts != nil {
+		log.Infof("user accounts configured with %s", cfg.String("accounts.backend"))
+	}
+	if dispatcher != nil {
+		log.Infof("webhooks configured with %d endpoints, failed deliveries persisted in %s", len(wc.Endpoints), wc.Backend)
+	}
+	if oidcOptions != nil {
+		log.Infof("web UI login configured with oidc issuer %s", oidcOptions.Issuer)
+	}
+	for _, name := range []string{"file", "link", "text"} {
+		if p := policies[name]; p != nil {
+			log.Infof("ttl policy for %s service configured with default %s, min %s, max %s, permanent allowed: %t", name, p.Default, p.Min, p.Max, p.AllowPermanent)
+		}
+		for _, group := range []string{"save", "retrieve"} {
+			if l, ok := limits[name+"."+group]; ok {
+				log.Infof("rate limit for %s routes of %s service configured with %.3f requests per second, burst of %d", group, name, l.Rate, l.Burst)
+			}
+		}
+	}
 
 	return &dependencies{
 		Port:                       port,
+		Admin:                      admin,
 		BaseURL:                    baseURL,
 		FileServiceMetadataBackend: backendMap[fm],
 		FileServiceFastBackend:     fastBackendMap[f],
 		LinkServiceBackend:         backendMap[l],
+		LinkServiceStatsBackend:    backendMap[ls],
 		TextServiceBackend:         fastBackendMap[t],
+		TextServiceMetadataBackend: backendMap[tm],
+		FileServiceMaxSize:         maxSizes["file"],
+		LinkServiceMaxSize:         maxSizes["link"],
+		TextServiceMaxSize:         maxSizes["text"],
+		TextServiceTheme:           cfg.String("service.text.theme"),
+		TextServiceHighlightSize:   highlightSize,
+		TextServiceMarkdownSize:    markdownSize,
+		TextServiceDiffSize:        diffSize,
+		LinkServiceInterstitial:    cfg.Bool("service.link.interstitial", false),
+		LinkServiceContinueSecret:  cfg.String("service.link.continue_secret"),
+		TextServiceMaxRevisions:    cfg.Int("service.text.max_revisions", 0),
+		FileServiceTTL:             policies["file"],
+		LinkServiceTTL:             policies["link"],
+		TextServiceTTL:             policies["text"],
+		RateLimitStore:             limitStore,
+		RateLimits:                 limits,
+		Accounts:                   accounts,
+		OIDC:                       oidcOptions,
+		Events:                     dispatcher,
+		TrustedProxies:             trustedProxies,
+		SaveAccess:                 saveAccess,
+		RetrieveAccess:             retrieveAccess,
 		Close:                      closer(logger, closeFns),
 	}, nil
 }
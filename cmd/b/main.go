@@ -11,12 +11,18 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/zllovesuki/b/access"
+	"github.com/zllovesuki/b/account"
 	"github.com/zllovesuki/b/box"
+	"github.com/zllovesuki/b/metrics"
+	"github.com/zllovesuki/b/ratelimit"
 	"github.com/zllovesuki/b/service"
 	"github.com/zllovesuki/b/service/file"
 	"github.com/zllovesuki/b/service/index"
 	"github.com/zllovesuki/b/service/link"
 	"github.com/zllovesuki/b/service/text"
+	"github.com/zllovesuki/b/service/user"
+	"github.com/zllovesuki/b/tracing"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -43,27 +49,58 @@ func main() {
 	defer dep.Close()
 
 	index, err := index.NewService(index.Options{
-		Logger: logger,
-		Asset:  asset,
+		Logger:   logger,
+		Asset:    asset,
+		OIDC:     dep.OIDC,
+		Accounts: dep.Accounts,
 	})
 	if err != nil {
 		logger.Fatal("unable to get index service", zap.Error(err))
 	}
 
+	// owners is left nil unless accounts are enabled, so services skip recording owners
+	var owners service.Ownership
+	if dep.Accounts != nil {
+		owners = dep.Accounts
+	}
+	// likewise, events is left nil unless webhooks are configured
+	var events service.Events
+	if dep.Events != nil {
+		events = dep.Events
+	}
+
 	l, err := link.NewService(link.Options{
-		BaseURL: dep.BaseURL,
-		Backend: dep.LinkServiceBackend,
-		Logger:  logger,
+		BaseURL:      dep.BaseURL,
+		Backend:      dep.LinkServiceBackend,
+		Logger:       logger,
+		TTL:          dep.LinkServiceTTL,
+		MaxSize:      dep.LinkServiceMaxSize,
+		Owners:       owners,
+		Events:       events,
+		Interstitial: dep.LinkServiceInterstitial,
+		StatsBackend: dep.LinkServiceStatsBackend,
+		ContinueKey:  []byte(dep.LinkServiceContinueSecret),
 	})
 	if err != nil {
 		logger.Fatal("unable to get link service", zap.Error(err))
 	}
+	// clicks queued before exit are counted before backends are closed
+	defer l.Close()
 
 	t, err := text.NewService(text.Options{
-		BaseURL: dep.BaseURL,
-		Asset:   asset,
-		Backend: dep.TextServiceBackend,
-		Logger:  logger,
+		BaseURL:         dep.BaseURL,
+		Backend:         dep.TextServiceBackend,
+		MetadataBackend: dep.TextServiceMetadataBackend,
+		Logger:          logger,
+		TTL:             dep.TextServiceTTL,
+		MaxSize:         dep.TextServiceMaxSize,
+		Owners:          owners,
+		Events:          events,
+		Theme:           dep.TextServiceTheme,
+		HighlightSize:   dep.TextServiceHighlightSize,
+		MarkdownSize:    dep.TextServiceMarkdownSize,
+		DiffSize:        dep.TextServiceDiffSize,
+		MaxRevisions:    dep.TextServiceMaxRevisions,
 	})
 	if err != nil {
 		logger.Fatal("unable to get text service", zap.Error(err))
@@ -74,29 +111,71 @@ func main() {
 		MetadataBackend: dep.FileServiceMetadataBackend,
 		FileBackend:     dep.FileServiceFastBackend,
 		Logger:          logger,
+		TTL:             dep.FileServiceTTL,
+		MaxSize:         dep.FileServiceMaxSize,
+		Owners:          owners,
+		Events:          events,
 	})
 	if err != nil {
 		logger.Fatal("unable to get file service", zap.Error(err))
 	}
 
+	adminServices := []adminRoutable{f, l, t}
+
+	var u *user.Service
+	if dep.Accounts != nil {
+		u, err = user.NewService(user.Options{
+			Accounts: dep.Accounts,
+			Logger:   logger,
+			Items:    []service.Items{f, l, t},
+		})
+		if err != nil {
+			logger.Fatal("unable to get user service", zap.Error(err))
+		}
+		adminServices = append(adminServices, u)
+	}
+
 	r := chi.NewRouter()
 
 	r.Use(middleware.Heartbeat("/healthz"))
 	r.Use(middleware.RequestID)
+	r.Use(access.RealIP(dep.TrustedProxies))
+	r.Use(tracing.Middleware)
+	r.Use(metrics.Middleware)
+	r.Use(service.AccessLog(logger))
 	r.Use(service.Recovery(logger))
-	r.Mount("/debug", middleware.Profiler())
+	if dep.Accounts != nil {
+		r.Use(account.Middleware(dep.Accounts, logger))
+	}
 
 	r.Mount("/", index.Route())
 
+	// limited applies the rate limit of the service to the route group, if configured
+	limited := func(router chi.Router, name, group string) chi.Router {
+		limit, ok := dep.RateLimits[name+"."+group]
+		if !ok {
+			return router
+		}
+		return router.With(ratelimit.Middleware(dep.RateLimitStore, name+"."+group, limit, logger))
+	}
+
 	postGroup := r.Group(nil)
+	postGroup.Use(access.Filter(dep.SaveAccess, logger))
 	postGroup.Use(middleware.NoCache)
-	f.SaveRoute(postGroup)
-	l.SaveRoute(postGroup)
-	t.SaveRoute(postGroup)
+	// web UI sessions are limited to the scopes granted at login
+	saveGroup := postGroup.With(account.RequireScope(account.ScopeSave))
+	f.SaveRoute(limited(saveGroup, "file", "save"))
+	l.SaveRoute(limited(saveGroup, "link", "save"))
+	t.SaveRoute(limited(saveGroup, "text", "save"))
+	if u != nil {
+		u.Route(postGroup.With(account.RequireScope(account.ScopeMe)))
+	}
 
-	f.RetrieveRoute(r)
-	l.RetrieveRoute(r)
-	t.RetrieveRoute(r)
+	getGroup := r.Group(nil)
+	getGroup.Use(access.Filter(dep.RetrieveAccess, logger))
+	f.RetrieveRoute(limited(getGroup, "file", "retrieve"))
+	l.RetrieveRoute(limited(getGroup, "link", "retrieve"))
+	t.RetrieveRoute(limited(getGroup, "text", "retrieve"))
 
 	sigs := make(chan os.Signal, 1)
 
@@ -115,12 +194,40 @@ func main() {
 	sugar := logger.Sugar()
 
 	sugar.Infof("listening for connection on port %s", dep.Port)
+
+	var adminSrv *http.Server
+	if dep.Admin.enabled() {
+		adminSrv = &http.Server{
+			Handler: adminRouter(logger, dep.Admin, adminServices...),
+		}
+		listener, err := adminListener(dep.Admin.Listen)
+		if err != nil {
+			logger.Fatal("unable to listen for admin connection", zap.Error(err))
+		}
+
+		go func() {
+			if err := adminSrv.Serve(listener); err != http.ErrServerClosed {
+				logger.Fatal("failed to serve admin connection", zap.Error(err))
+			}
+		}()
+
+		if !dep.Admin.hasCredentials() {
+			sugar.Warnf("admin listener has no credentials configured, ensure %s is not publicly reachable", dep.Admin.Listen)
+		}
+		sugar.Infof("listening for admin connection on %s", dep.Admin.Listen)
+	}
 	<-sigs
 	sugar.Info("stopping server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if adminSrv != nil {
+		if err := adminSrv.Shutdown(ctx); err != nil {
+			logger.Error("failed to shutdown admin listener gracefully", zap.Error(err))
+		}
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("failed to shutdown gracefully", zap.Error(err))
 	}
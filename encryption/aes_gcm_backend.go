@@ -45,6 +45,14 @@ func (a *AESGCM) SaveTTL(c context.Context, identifier string, data []byte, ttl
 	return a.backend.SaveTTL(c, identifier, ciphertext, ttl)
 }
 
+func (a *AESGCM) Overwrite(c context.Context, identifier string, data []byte, ttl time.Duration) error {
+	ciphertext, err := a.encrypt(data)
+	if err != nil {
+		return errors.Wrap(err, "encrypting during overwrite")
+	}
+	return a.backend.Overwrite(c, identifier, ciphertext, ttl)
+}
+
 func (a *AESGCM) Retrieve(c context.Context, identifier string) ([]byte, error) {
 	ciphertext, err := a.backend.Retrieve(c, identifier)
 	if err != nil {
@@ -53,6 +61,20 @@ func (a *AESGCM) Retrieve(c context.Context, identifier string) ([]byte, error)
 	return a.decrypt(ciphertext)
 }
 
+// Stat returns information from the underlying backend. Size reported is of the ciphertext
+func (a *AESGCM) Stat(c context.Context, identifier string) (app.Info, error) {
+	return a.backend.Stat(c, identifier)
+}
+
+// List returns documents from the underlying backend. Sizes reported are of the ciphertext
+func (a *AESGCM) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	return a.backend.List(c, prefix, cursor, limit)
+}
+
+func (a *AESGCM) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	return a.backend.Touch(c, identifier, ttl)
+}
+
 func (a *AESGCM) encrypt(data []byte) ([]byte, error) {
 	block, err := aes.NewCipher(a.key)
 	if err != nil {
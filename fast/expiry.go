@@ -0,0 +1,26 @@
+package fast
+
+import (
+	"context"
+	"sync"
+)
+
+// expiryHooks implements app.ExpiryNotifier for backends removing expired data on access
+type expiryHooks struct {
+	mu  sync.RWMutex
+	fns []func(c context.Context, identifier string)
+}
+
+func (e *expiryHooks) OnExpire(fn func(c context.Context, identifier string)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fns = append(e.fns, fn)
+}
+
+func (e *expiryHooks) expired(c context.Context, identifier string) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, fn := range e.fns {
+		fn(c, identifier)
+	}
+}
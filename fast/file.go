@@ -5,19 +5,25 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/zllovesuki/b/app"
 )
 
+// tempDir holds files being written by Overwrite, under the data directory
+const tempDir = ".tmp"
+
 // FileFastBackend is a file-backed app.FastBackend implementation with support for TTL
 type FileFastBackend struct {
+	expiryHooks
 	dataDir string
 }
 
 var _ app.FastBackend = &FileFastBackend{}
 var _ app.Removable = &FileFastBackend{}
+var _ app.ExpiryNotifier = &FileFastBackend{}
 
 func NewFileFastBackend(dataDir string) (*FileFastBackend, error) {
 	if dataDir == "" {
@@ -49,45 +55,97 @@ func (f *FileFastBackend) SaveTTL(c context.Context, identifier string, r io.Rea
 
 	p := filepath.Join(f.dataDir, identifier)
 
-	exist := true
-
-	if _, err := os.Stat(p); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			exist = false
-		} else {
-			return 0, errors.Wrap(err, "testing file existence")
+	exceeded, err := f.exceeded(p)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// just a new file in general
+	case err != nil:
+		return 0, err
+	case !exceeded:
+		return 0, app.ErrConflict
+	default:
+		// replace file if ttl exceeded
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return 0, errors.Wrap(err, "removing expired file")
 		}
 	}
 
-	if exist {
-		r, err := os.OpenFile(p, os.O_RDONLY, 0600)
-		if err != nil {
-			return 0, errors.Wrap(err, "opening file for ttl checking")
-		}
-		defer r.Close()
+	// saves racing for the same identifier conflict instead of writing to the same file
+	w, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return 0, app.ErrConflict
+	} else if err != nil {
+		return 0, errors.Wrap(err, "cannot open file")
+	}
 
-		ex, err := app.TTLExceeded(r)
-		if err != nil {
-			return 0, errors.Wrap(err, "checking ttl of the file")
-		}
-		if !ex {
-			return 0, app.ErrConflict
-		}
+	return writeFile(c, w, r, ttl)
+}
+
+// Overwrite writes the data to a temporary file, then renames it over the file of the identifier
+func (f *FileFastBackend) Overwrite(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
+	defer r.Close()
+
+	// temporary files are kept in a directory, which List skips
+	dir := filepath.Join(f.dataDir, tempDir)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return 0, errors.Wrap(err, "creating temporary directory")
+	}
+	w, err := os.CreateTemp(dir, identifier+"-*")
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot create temporary file")
 	}
 
-	// overwrite file if ttl exceeded, or just a new file in general
-	w, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	written, err := writeFile(c, w, r, ttl)
 	if err != nil {
-		return 0, errors.Wrap(err, "cannot open file")
+		return written, err
+	}
+	if err := os.Rename(w.Name(), filepath.Join(f.dataDir, identifier)); err != nil {
+		os.Remove(w.Name())
+		return 0, errors.Wrap(err, "replacing file")
+	}
+	return written, nil
+}
+
+// exceeded reports whether the ttl of the file at p is exceeded, or os.ErrNotExist if there is none
+func (f *FileFastBackend) exceeded(p string) (bool, error) {
+	r, err := os.OpenFile(p, os.O_RDONLY, 0600)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, err
+	} else if err != nil {
+		return false, errors.Wrap(err, "opening file for ttl checking")
+	}
+	defer r.Close()
+
+	ex, err := app.TTLExceeded(r)
+	if err != nil {
+		return false, errors.Wrap(err, "checking ttl of the file")
+	}
+	return ex, nil
+}
+
+// writeFile writes the ttl header and data to w, then closes it. The file is removed on error,
+// so that no partial file is left behind
+func writeFile(c context.Context, w *os.File, r io.Reader, ttl time.Duration) (int64, error) {
+	fail := func(err error) error {
+		w.Close()
+		os.Remove(w.Name())
+		return err
 	}
-	defer w.Close()
 
 	if err := app.WriteTTL(w, ttl); err != nil {
-		return 0, err
+		return 0, fail(err)
 	}
 
 	buf := make([]byte, 2<<20) // 2Mi buffer
-	return io.CopyBuffer(w, app.NewCtxReader(c, r), buf)
+	written, err := io.CopyBuffer(w, app.NewCtxReader(c, r), buf)
+	if err != nil {
+		return written, fail(err)
+	}
+	if err := w.Close(); err != nil {
+		return written, fail(errors.Wrap(err, "closing file"))
+	}
+
+	return written, nil
 }
 
 func (f *FileFastBackend) Retrieve(c context.Context, identifier string) (io.ReadCloser, error) {
@@ -113,7 +171,9 @@ func (f *FileFastBackend) Retrieve(c context.Context, identifier string) (io.Rea
 			// then compaction on access
 			// TODO(zllovesuki): investigate and see if this makes sense.
 			// this may fail if there are inflight requests downloading the file
-			os.Remove(p)
+			if os.Remove(p) == nil {
+				f.expired(c, identifier)
+			}
 		}
 	}()
 
@@ -122,12 +182,121 @@ func (f *FileFastBackend) Retrieve(c context.Context, identifier string) (io.Rea
 		return nil, errors.Wrap(err, "error checking ttl of the file")
 	}
 	if expired {
-		return nil, app.ErrNotFound
+		return nil, app.ErrExpired
 	}
 
 	return file, nil
 }
 
+func (f *FileFastBackend) Stat(c context.Context, identifier string) (app.Info, error) {
+	info, err := f.info(identifier)
+	if err != nil {
+		return app.Info{}, err
+	}
+	if !info.Expires.IsZero() && time.Now().After(info.Expires) {
+		return app.Info{}, app.ErrExpired
+	}
+	return info, nil
+}
+
+func (f *FileFastBackend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	// entries are sorted by filename
+	entries, err := os.ReadDir(f.dataDir)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "reading data directory")
+	}
+
+	now := time.Now()
+	items := make([]app.Info, 0, limit)
+	scanned := 0
+	next := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || name <= cursor {
+			continue
+		}
+		if scanned == limit {
+			break
+		}
+		if err := c.Err(); err != nil {
+			return nil, "", err
+		}
+		scanned++
+		next = name
+
+		info, err := f.info(name)
+		if errors.Is(err, app.ErrNotFound) {
+			// removed since reading the directory
+			continue
+		} else if err != nil {
+			return nil, "", err
+		}
+		if !info.Expires.IsZero() && now.After(info.Expires) {
+			continue
+		}
+		items = append(items, info)
+	}
+
+	if scanned < limit {
+		next = ""
+	}
+	return items, next, nil
+}
+
+func (f *FileFastBackend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	p := filepath.Join(f.dataDir, identifier)
+
+	file, err := os.OpenFile(p, os.O_RDWR, 0600)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return app.ErrNotFound
+		}
+		return errors.Wrap(err, "cannot open file")
+	}
+	defer file.Close()
+
+	created, current, err := app.ReadTTL(file)
+	if err != nil {
+		return errors.Wrap(err, "error reading ttl of the file")
+	}
+	if current != 0 && time.Now().After(created.Add(current)) {
+		return app.ErrExpired
+	}
+
+	return app.RewriteTTL(file, created, app.RenewTTL(created, ttl))
+}
+
+// info reads the header of the file without checking for expiration
+func (f *FileFastBackend) info(identifier string) (app.Info, error) {
+	p := filepath.Join(f.dataDir, identifier)
+
+	file, err := os.OpenFile(p, os.O_RDONLY, 0600)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return app.Info{}, app.ErrNotFound
+		}
+		return app.Info{}, errors.Wrap(err, "cannot open file")
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return app.Info{}, errors.Wrap(err, "cannot stat file")
+	}
+
+	created, ttl, err := app.ReadTTL(file)
+	if err != nil {
+		return app.Info{}, errors.Wrap(err, "error reading ttl of the file")
+	}
+
+	return app.Info{
+		ID:      identifier,
+		Size:    stat.Size() - app.HeaderSize(),
+		Created: created,
+		Expires: app.Expiry(created, ttl),
+	}, nil
+}
+
 func (f *FileFastBackend) Close() error {
 	return nil
 }
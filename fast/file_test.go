@@ -2,9 +2,12 @@ package fast
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/require"
@@ -41,6 +44,11 @@ func TestRemoveOnAccess(t *testing.T) {
 
 	path := filepath.Join(p, key)
 
+	var expired []string
+	b.OnExpire(func(c context.Context, identifier string) {
+		expired = append(expired, identifier)
+	})
+
 	_, err := b.SaveTTL(context.Background(), key, reader(), ttl/2)
 	require.NoError(t, err)
 
@@ -55,6 +63,26 @@ func TestRemoveOnAccess(t *testing.T) {
 	// ensure that we delete on access
 	_, err = os.Stat(path)
 	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Equal(t, []string{key}, expired)
+
+	// expiration is only notified once
+	_, err = b.Retrieve(context.Background(), key)
+	require.ErrorIs(t, err, app.ErrNotFound)
+	require.Equal(t, []string{key}, expired)
+}
+
+func TestRemovePartialOnError(t *testing.T) {
+	b, clean := getFixtures(t)
+	defer clean()
+
+	key := "remove-partial"
+	r := io.MultiReader(apptest.GetReaderFn(t)(), iotest.ErrReader(errors.New("client disconnected")))
+
+	_, err := b.SaveTTL(context.Background(), key, io.NopCloser(r), 0)
+	require.Error(t, err)
+
+	_, err = os.Stat(filepath.Join(p, key))
+	require.ErrorIs(t, err, os.ErrNotExist)
 }
 
 func TestFileDelete(t *testing.T) {
@@ -63,3 +91,71 @@ func TestFileDelete(t *testing.T) {
 
 	apptest.TestRemovableFastBackend(t, b)
 }
+
+func TestFileList(t *testing.T) {
+	b, clean := getFixtures(t)
+	defer clean()
+
+	apptest.TestListFastBackend(t, b)
+}
+
+func TestFileStat(t *testing.T) {
+	b, clean := getFixtures(t)
+	defer clean()
+
+	apptest.TestStatFastBackend(t, b)
+}
+
+func TestFileTouch(t *testing.T) {
+	b, clean := getFixtures(t)
+	defer clean()
+
+	apptest.TestTouchFastBackend(t, b)
+}
+
+func TestFileOverwrite(t *testing.T) {
+	b, clean := getFixtures(t)
+	defer clean()
+
+	apptest.TestOverwriteFastBackend(t, b)
+
+	t.Run("temporary files should not be listed", func(t *testing.T) {
+		items, _, err := b.List(context.Background(), "", "", 1000)
+		require.NoError(t, err)
+		for _, item := range items {
+			require.NotEqual(t, tempDir, item.ID)
+		}
+	})
+}
+
+func TestFileConcurrentSave(t *testing.T) {
+	b, clean := getFixtures(t)
+	defer clean()
+
+	ctx := context.Background()
+	reader := apptest.GetReaderFn(t)
+
+	// saves racing for the same identifier, of which only one may create the file
+	const saves = 8
+	errs := make(chan error, saves)
+	start := make(chan struct{})
+	for i := 0; i < saves; i++ {
+		go func() {
+			<-start
+			_, err := b.SaveTTL(ctx, "racing", reader(), 0)
+			errs <- err
+		}()
+	}
+	close(start)
+
+	saved := 0
+	for i := 0; i < saves; i++ {
+		err := <-errs
+		if err == nil {
+			saved++
+			continue
+		}
+		require.ErrorIs(t, err, app.ErrConflict)
+	}
+	require.Equal(t, 1, saved)
+}
@@ -0,0 +1,266 @@
+package fast
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+
+	"github.com/pkg/errors"
+)
+
+// QuotaFastBackend wraps an app.RemovableFastBackend to reject uploads once the stored bytes
+// reach the quota. Usage is tracked in memory and periodically recounted from the backend,
+// as data removed on expiration is not observed by the wrapper
+type QuotaFastBackend struct {
+	backend app.RemovableFastBackend
+	quota   int64
+	stop    chan struct{}
+	once    sync.Once
+	// recount serializes recounts
+	recount sync.Mutex
+
+	mu   sync.Mutex
+	used int64
+	// inflight are the bytes reserved by uploads not finished yet, which a recount may not list.
+	// settled are the bytes of uploads finished during a recount, which it may not have listed
+	inflight int64
+	settled  int64
+	scanning bool
+}
+
+var _ app.RemovableFastBackend = &QuotaFastBackend{}
+var _ app.ExpiryNotifier = &QuotaFastBackend{}
+
+// NewQuotaFastBackend returns a quota enforcing wrapper around backend. Current usage is counted
+// on creation, then recounted every interval
+func NewQuotaFastBackend(backend app.RemovableFastBackend, quota int64, interval time.Duration) (*QuotaFastBackend, error) {
+	if backend == nil {
+		return nil, errors.New("missing backend")
+	}
+	if quota <= 0 {
+		return nil, errors.New("quota must be positive")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+
+	q := &QuotaFastBackend{
+		backend: backend,
+		quota:   quota,
+		stop:    make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := q.Recount(ctx); err != nil {
+		return nil, err
+	}
+
+	go q.recountLoop(interval)
+
+	return q, nil
+}
+
+// Used returns the number of bytes currently accounted for
+func (q *QuotaFastBackend) Used() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.used
+}
+
+// Recount sets usage to the sum of sizes of all unexpired data in the backend, along with the
+// bytes of uploads in flight. Usage may be overcounted until the next recount, such as data
+// deleted during the scan, but never undercounted
+func (q *QuotaFastBackend) Recount(c context.Context) error {
+	q.recount.Lock()
+	defer q.recount.Unlock()
+
+	q.mu.Lock()
+	q.scanning = true
+	q.settled = 0
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		q.scanning = false
+		q.mu.Unlock()
+	}()
+
+	var used int64
+	cursor := ""
+	for {
+		items, next, err := q.backend.List(c, "", cursor, 1000)
+		if err != nil {
+			return errors.Wrap(err, "listing backend for quota usage")
+		}
+		for _, item := range items {
+			used += item.Size
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	q.mu.Lock()
+	q.used = used + q.inflight + q.settled
+	q.mu.Unlock()
+	return nil
+}
+
+// reserve accounts n more bytes of an upload against the quota, reporting whether it is exceeded
+func (q *QuotaFastBackend) reserve(n int64) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.used += n
+	q.inflight += n
+	return q.used > q.quota
+}
+
+// settle ends the reservation of the n bytes of an upload, releasing them if the upload failed
+func (q *QuotaFastBackend) settle(n int64, failed bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.inflight -= n
+	if failed {
+		q.used -= n
+	} else if q.scanning {
+		q.settled += n
+	}
+}
+
+// release removes n bytes no longer stored from the usage
+func (q *QuotaFastBackend) release(n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.used -= n
+}
+
+func (q *QuotaFastBackend) recountLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			// keep the last known usage on error
+			q.Recount(ctx)
+			cancel()
+		}
+	}
+}
+
+func (q *QuotaFastBackend) SaveTTL(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
+	if q.Used() >= q.quota {
+		r.Close()
+		return 0, app.ErrQuotaExceeded
+	}
+
+	reader := &quotaReader{
+		ReadCloser: r,
+		q:          q,
+	}
+	written, err := q.backend.SaveTTL(c, identifier, reader, ttl)
+	// backends do not keep partial data on error
+	q.settle(reader.n, err != nil)
+	if err != nil {
+		if reader.exceeded {
+			return 0, app.ErrQuotaExceeded
+		}
+		return written, err
+	}
+	return written, nil
+}
+
+// Overwrite accounts the new data against the quota like SaveTTL, releasing the replaced data once
+// overwritten
+func (q *QuotaFastBackend) Overwrite(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
+	if q.Used() >= q.quota {
+		r.Close()
+		return 0, app.ErrQuotaExceeded
+	}
+
+	info, statErr := q.backend.Stat(c, identifier)
+	reader := &quotaReader{
+		ReadCloser: r,
+		q:          q,
+	}
+	written, err := q.backend.Overwrite(c, identifier, reader, ttl)
+	q.settle(reader.n, err != nil)
+	if err != nil {
+		if reader.exceeded {
+			return 0, app.ErrQuotaExceeded
+		}
+		return written, err
+	}
+	if statErr == nil {
+		q.release(info.Size)
+	}
+	return written, nil
+}
+
+func (q *QuotaFastBackend) Retrieve(c context.Context, identifier string) (io.ReadCloser, error) {
+	return q.backend.Retrieve(c, identifier)
+}
+
+// OnExpire registers fn with the wrapped backend, if it removes expired data lazily
+func (q *QuotaFastBackend) OnExpire(fn func(c context.Context, identifier string)) {
+	if n, ok := q.backend.(app.ExpiryNotifier); ok {
+		n.OnExpire(fn)
+	}
+}
+
+func (q *QuotaFastBackend) Stat(c context.Context, identifier string) (app.Info, error) {
+	return q.backend.Stat(c, identifier)
+}
+
+func (q *QuotaFastBackend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	return q.backend.List(c, prefix, cursor, limit)
+}
+
+func (q *QuotaFastBackend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	return q.backend.Touch(c, identifier, ttl)
+}
+
+func (q *QuotaFastBackend) Delete(c context.Context, identifier string) error {
+	info, statErr := q.backend.Stat(c, identifier)
+	if err := q.backend.Delete(c, identifier); err != nil {
+		return err
+	}
+	if statErr == nil {
+		q.release(info.Size)
+	}
+	return nil
+}
+
+func (q *QuotaFastBackend) Close() error {
+	q.once.Do(func() {
+		close(q.stop)
+	})
+	return q.backend.Close()
+}
+
+// quotaReader accounts bytes against the quota as they are read
+type quotaReader struct {
+	io.ReadCloser
+	q        *QuotaFastBackend
+	n        int64
+	exceeded bool
+}
+
+func (r *quotaReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.n += int64(n)
+		if r.q.reserve(int64(n)) {
+			r.exceeded = true
+			return n, app.ErrQuotaExceeded
+		}
+	}
+	return n, err
+}
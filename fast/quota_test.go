@@ -0,0 +1,148 @@
+package fast
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/apptest"
+)
+
+func TestQuotaFastBackend(t *testing.T) {
+	b, clean := getFixtures(t)
+	defer clean()
+
+	ctx := context.Background()
+	reader := apptest.GetReaderFn(t)
+
+	// existing data should be accounted for on creation
+	written, err := b.SaveTTL(ctx, "existing", reader(), 0)
+	require.NoError(t, err)
+
+	q, err := NewQuotaFastBackend(b, written*5/2, time.Hour)
+	require.NoError(t, err)
+	defer q.Close()
+	require.Equal(t, written, q.Used())
+
+	_, err = q.SaveTTL(ctx, "first", reader(), 0)
+	require.NoError(t, err)
+	require.Equal(t, written*2, q.Used())
+
+	t.Run("upload exceeding quota should be rejected without partial data", func(t *testing.T) {
+		_, err := q.SaveTTL(ctx, "second", reader(), 0)
+		require.ErrorIs(t, err, app.ErrQuotaExceeded)
+		require.Equal(t, written*2, q.Used())
+
+		_, err = b.Stat(ctx, "second")
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+
+	t.Run("delete should release usage", func(t *testing.T) {
+		err := q.Delete(ctx, "first")
+		require.NoError(t, err)
+		require.Equal(t, written, q.Used())
+
+		_, err = q.SaveTTL(ctx, "second", reader(), 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("full quota should reject before reading", func(t *testing.T) {
+		q, err := NewQuotaFastBackend(b, written, time.Hour)
+		require.NoError(t, err)
+		defer q.Close()
+
+		_, err = q.SaveTTL(ctx, "third", reader(), 0)
+		require.ErrorIs(t, err, app.ErrQuotaExceeded)
+	})
+
+	t.Run("recount should observe expired data", func(t *testing.T) {
+		_, err := b.SaveTTL(ctx, "expiring", reader(), time.Millisecond*100)
+		require.NoError(t, err)
+
+		err = q.Recount(ctx)
+		require.NoError(t, err)
+		require.Equal(t, written*3, q.Used())
+
+		<-time.After(time.Millisecond * 500)
+
+		err = q.Recount(ctx)
+		require.NoError(t, err)
+		require.Equal(t, written*2, q.Used())
+	})
+}
+
+func TestQuotaRecountInFlight(t *testing.T) {
+	ctx := context.Background()
+
+	for name, fail := range map[string]bool{
+		"finished upload should stay counted": false,
+		"failed upload should be released":    true,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			b := app.NewMockRemovableFastBackend(ctrl)
+
+			var stored int64
+			b.EXPECT().
+				List(gomock.Any(), "", "", gomock.Any()).
+				DoAndReturn(func(context.Context, string, string, int) ([]app.Info, string, error) {
+					if stored == 0 {
+						return nil, "", nil
+					}
+					return []app.Info{{ID: "upload", Size: stored}}, "", nil
+				}).
+				AnyTimes()
+
+			reading := make(chan struct{})
+			release := make(chan struct{})
+			b.EXPECT().
+				SaveTTL(gomock.Any(), "upload", gomock.Any(), time.Duration(0)).
+				DoAndReturn(func(_ context.Context, _ string, r io.ReadCloser, _ time.Duration) (int64, error) {
+					n, err := io.Copy(io.Discard, r)
+					require.NoError(t, err)
+					close(reading)
+					<-release
+					if fail {
+						return 0, errors.New("upload failed")
+					}
+					stored = n
+					return n, nil
+				})
+
+			b.EXPECT().Close().Return(nil)
+
+			q, err := NewQuotaFastBackend(b, 100, time.Hour)
+			require.NoError(t, err)
+			defer q.Close()
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				q.SaveTTL(ctx, "upload", io.NopCloser(strings.NewReader("0123456789")), 0)
+			}()
+
+			<-reading
+			// the upload is not listed yet
+			require.NoError(t, q.Recount(ctx))
+			require.Equal(t, int64(10), q.Used())
+
+			close(release)
+			<-done
+
+			if fail {
+				require.Equal(t, int64(0), q.Used())
+			} else {
+				require.Equal(t, int64(10), q.Used())
+				require.NoError(t, q.Recount(ctx))
+				require.Equal(t, int64(10), q.Used())
+			}
+		})
+	}
+}
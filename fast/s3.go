@@ -44,13 +44,33 @@ const (
 	metaTTL     = "B-Time-To-Live"
 )
 
+// parseTTL returns the creation time and ttl from the object's user metadata
+func parseTTL(info minio.ObjectInfo) (time.Time, time.Duration, error) {
+	whenStr := info.UserMetadata[metaCreated]
+	ttlStr := info.UserMetadata[metaTTL]
+
+	when, err := time.Parse(time.RFC3339, whenStr)
+	if err != nil {
+		return time.Time{}, 0, errors.Wrap(err, "parsing created date")
+	}
+
+	exp, err := time.ParseDuration(ttlStr)
+	if err != nil {
+		return time.Time{}, 0, errors.Wrap(err, "parsing ttl")
+	}
+
+	return when, exp, nil
+}
+
 type S3FastBackend struct {
+	expiryHooks
 	config S3Config
 	mc     *minio.Client
 }
 
 var _ app.FastBackend = &S3FastBackend{}
 var _ app.Removable = &S3FastBackend{}
+var _ app.ExpiryNotifier = &S3FastBackend{}
 
 func NewS3FastBackend(conf S3Config) (*S3FastBackend, error) {
 	if err := conf.validate(); err != nil {
@@ -95,41 +115,33 @@ func (s *S3FastBackend) Save(c context.Context, identifier string, r io.ReadClos
 	return s.SaveTTL(c, identifier, r, 0)
 }
 
+// SaveTTL uploads the data unless the identifier exists. S3 lacks conditional uploads,
+// so saves racing for the same identifier may both succeed, the last one winning
 func (s *S3FastBackend) SaveTTL(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
 	defer r.Close()
 
-	var err error
-	exist := true
-
-	var info minio.ObjectInfo
-	info, err = s.mc.StatObject(c, s.config.Bucket, identifier, minio.StatObjectOptions{})
-	if err != nil {
-		resp := minio.ToErrorResponse(err)
-		if resp.StatusCode == http.StatusNotFound {
-			exist = false
-		} else {
-			return 0, errors.Wrap(err, "stat object for checking existence")
+	info, statErr := s.info(c, identifier)
+	if statErr == nil {
+		if info.Expires.IsZero() || time.Now().UTC().Before(info.Expires) {
+			return 0, app.ErrConflict
 		}
+	} else if !errors.Is(statErr, app.ErrNotFound) {
+		return 0, errors.Wrap(statErr, "checking existence")
 	}
 
-	if exist {
-		whenStr := info.UserMetadata[metaCreated]
-		ttlStr := info.UserMetadata[metaTTL]
+	return s.put(c, identifier, r, ttl)
+}
 
-		when, err := time.Parse(time.RFC3339, whenStr)
-		if err != nil {
-			return 0, errors.Wrap(err, "parsing created date")
-		}
+// Overwrite uploads the data regardless of the identifier existing. Objects are replaced at once
+// when the upload completes
+func (s *S3FastBackend) Overwrite(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
+	defer r.Close()
 
-		exp, err := time.ParseDuration(ttlStr)
-		if err != nil {
-			return 0, errors.Wrap(err, "parsing ttl")
-		}
+	return s.put(c, identifier, r, ttl)
+}
 
-		if exp == 0 || time.Now().UTC().Before(when.UTC().Add(exp)) {
-			return 0, app.ErrConflict
-		}
-	}
+func (s *S3FastBackend) put(c context.Context, identifier string, r io.Reader, ttl time.Duration) (int64, error) {
+	var err error
 
 	defer func() {
 		if err == nil {
@@ -157,38 +169,22 @@ func (s *S3FastBackend) SaveTTL(c context.Context, identifier string, r io.ReadC
 }
 
 func (s *S3FastBackend) Retrieve(c context.Context, identifier string) (io.ReadCloser, error) {
-	info, err := s.mc.StatObject(c, s.config.Bucket, identifier, minio.StatObjectOptions{})
+	info, err := s.info(c, identifier)
 	if err != nil {
-		resp := minio.ToErrorResponse(err)
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, app.ErrNotFound
-		} else {
-			return nil, errors.Wrap(err, "testing existence")
-		}
-	}
-
-	whenStr := info.UserMetadata[metaCreated]
-	ttlStr := info.UserMetadata[metaTTL]
-
-	when, err := time.Parse(time.RFC3339, whenStr)
-	if err != nil {
-		return nil, errors.Wrap(err, "parsing created date")
-	}
-
-	exp, err := time.ParseDuration(ttlStr)
-	if err != nil {
-		return nil, errors.Wrap(err, "parsing ttl")
+		return nil, err
 	}
 
-	expired := exp != 0 && time.Now().UTC().After(when.UTC().Add(exp))
+	expired := !info.Expires.IsZero() && time.Now().UTC().After(info.Expires)
 	defer func() {
 		if expired {
 			// delete on access
-			s.Delete(c, identifier)
+			if s.Delete(c, identifier) == nil {
+				s.expired(c, identifier)
+			}
 		}
 	}()
 	if expired {
-		return nil, app.ErrNotFound
+		return nil, app.ErrExpired
 	}
 
 	reader, err := s.mc.GetObject(c, s.config.Bucket, identifier, minio.GetObjectOptions{})
@@ -199,6 +195,112 @@ func (s *S3FastBackend) Retrieve(c context.Context, identifier string) (io.ReadC
 	return reader, nil
 }
 
+func (s *S3FastBackend) Stat(c context.Context, identifier string) (app.Info, error) {
+	info, err := s.info(c, identifier)
+	if err != nil {
+		return app.Info{}, err
+	}
+	if !info.Expires.IsZero() && time.Now().UTC().After(info.Expires) {
+		return app.Info{}, app.ErrExpired
+	}
+	return info, nil
+}
+
+// Touch copies the object onto itself with updated metadata, as S3 metadata cannot be modified in place.
+// Note that a single copy is limited to objects up to 5GiB by S3
+func (s *S3FastBackend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	info, err := s.Stat(c, identifier)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.mc.CopyObject(c, minio.CopyDestOptions{
+		Bucket:          s.config.Bucket,
+		Object:          identifier,
+		ReplaceMetadata: true,
+		UserMetadata: map[string]string{
+			metaCreated: info.Created.Format(time.RFC3339),
+			metaTTL:     app.RenewTTL(info.Created, ttl).String(),
+		},
+	}, minio.CopySrcOptions{
+		Bucket: s.config.Bucket,
+		Object: identifier,
+	})
+	if err != nil {
+		return errors.Wrap(err, "copying object with new metadata")
+	}
+
+	return nil
+}
+
+// info stats the object without checking for expiration
+func (s *S3FastBackend) info(c context.Context, identifier string) (app.Info, error) {
+	object, err := s.mc.StatObject(c, s.config.Bucket, identifier, minio.StatObjectOptions{})
+	if err != nil {
+		resp := minio.ToErrorResponse(err)
+		if resp.StatusCode == http.StatusNotFound {
+			return app.Info{}, app.ErrNotFound
+		}
+		return app.Info{}, errors.Wrap(err, "stat object")
+	}
+
+	when, exp, err := parseTTL(object)
+	if err != nil {
+		return app.Info{}, err
+	}
+
+	return app.Info{
+		ID:      identifier,
+		Size:    object.Size,
+		Created: when.UTC(),
+		Expires: app.Expiry(when.UTC(), exp),
+	}, nil
+}
+
+func (s *S3FastBackend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+
+	objects := s.mc.ListObjects(ctx, s.config.Bucket, minio.ListObjectsOptions{
+		Prefix:     prefix,
+		StartAfter: cursor,
+		MaxKeys:    limit,
+	})
+
+	now := time.Now().UTC()
+	items := make([]app.Info, 0, limit)
+	scanned := 0
+	next := ""
+	for object := range objects {
+		if object.Err != nil {
+			return nil, "", errors.Wrap(object.Err, "listing objects")
+		}
+		scanned++
+		next = object.Key
+
+		// user metadata is not returned by ListObjects on S3
+		info, err := s.info(c, object.Key)
+		if errors.Is(err, app.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, "", err
+		}
+		if !info.Expires.IsZero() && now.After(info.Expires) {
+			continue
+		}
+		items = append(items, info)
+
+		if scanned == limit {
+			break
+		}
+	}
+
+	if scanned < limit {
+		next = ""
+	}
+	return items, next, nil
+}
+
 func (s *S3FastBackend) Close() error {
 	return nil
 }
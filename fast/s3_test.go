@@ -99,3 +99,27 @@ func TestS3Delete(t *testing.T) {
 
 	apptest.TestRemovableFastBackend(t, b)
 }
+
+func TestS3List(t *testing.T) {
+	b := getS3Fixtures(t)
+
+	apptest.TestListFastBackend(t, b)
+}
+
+func TestS3Stat(t *testing.T) {
+	b := getS3Fixtures(t)
+
+	apptest.TestStatFastBackend(t, b)
+}
+
+func TestS3Touch(t *testing.T) {
+	b := getS3Fixtures(t)
+
+	apptest.TestTouchFastBackend(t, b)
+}
+
+func TestS3Overwrite(t *testing.T) {
+	b := getS3Fixtures(t)
+
+	apptest.TestOverwriteFastBackend(t, b)
+}
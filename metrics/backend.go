@@ -0,0 +1,189 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+
+	"github.com/pkg/errors"
+)
+
+// results of backend operations used as label values
+const (
+	resultOK       = "ok"
+	resultConflict = "conflict"
+	resultNotFound = "not_found"
+	resultExpired  = "expired"
+	resultQuota    = "quota_exceeded"
+	resultError    = "error"
+)
+
+func result(err error) string {
+	switch {
+	case err == nil:
+		return resultOK
+	case errors.Is(err, app.ErrConflict):
+		return resultConflict
+	case errors.Is(err, app.ErrExpired):
+		return resultExpired
+	case errors.Is(err, app.ErrNotFound):
+		return resultNotFound
+	case errors.Is(err, app.ErrQuotaExceeded):
+		return resultQuota
+	default:
+		return resultError
+	}
+}
+
+func observe(kind, op string, start time.Time, err error) {
+	backendDuration.WithLabelValues(kind, op).Observe(time.Since(start).Seconds())
+	backendOperations.WithLabelValues(kind, op, result(err)).Inc()
+}
+
+// Backend wraps an app.RemovableBackend to record latency and outcome of every operation
+type Backend struct {
+	kind    string
+	backend app.RemovableBackend
+}
+
+var _ app.RemovableBackend = &Backend{}
+
+// NewBackend returns an instrumented app.RemovableBackend. kind is used as the backend label (e.g. redis)
+func NewBackend(kind string, b app.RemovableBackend) *Backend {
+	return &Backend{
+		kind:    kind,
+		backend: b,
+	}
+}
+
+func (b *Backend) SaveTTL(c context.Context, identifier string, data []byte, ttl time.Duration) error {
+	start := time.Now()
+	err := b.backend.SaveTTL(c, identifier, data, ttl)
+	observe(b.kind, "save", start, err)
+	return err
+}
+
+func (b *Backend) Overwrite(c context.Context, identifier string, data []byte, ttl time.Duration) error {
+	start := time.Now()
+	err := b.backend.Overwrite(c, identifier, data, ttl)
+	observe(b.kind, "overwrite", start, err)
+	return err
+}
+
+func (b *Backend) Retrieve(c context.Context, identifier string) ([]byte, error) {
+	start := time.Now()
+	data, err := b.backend.Retrieve(c, identifier)
+	observe(b.kind, "retrieve", start, err)
+	return data, err
+}
+
+func (b *Backend) Stat(c context.Context, identifier string) (app.Info, error) {
+	start := time.Now()
+	info, err := b.backend.Stat(c, identifier)
+	observe(b.kind, "stat", start, err)
+	return info, err
+}
+
+func (b *Backend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	start := time.Now()
+	items, next, err := b.backend.List(c, prefix, cursor, limit)
+	observe(b.kind, "list", start, err)
+	return items, next, err
+}
+
+func (b *Backend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	start := time.Now()
+	err := b.backend.Touch(c, identifier, ttl)
+	observe(b.kind, "touch", start, err)
+	return err
+}
+
+func (b *Backend) Delete(c context.Context, identifier string) error {
+	start := time.Now()
+	err := b.backend.Delete(c, identifier)
+	observe(b.kind, "delete", start, err)
+	return err
+}
+
+func (b *Backend) Close() error {
+	return b.backend.Close()
+}
+
+// FastBackend wraps an app.RemovableFastBackend to record latency and outcome of every operation
+type FastBackend struct {
+	kind    string
+	backend app.RemovableFastBackend
+}
+
+var _ app.RemovableFastBackend = &FastBackend{}
+var _ app.ExpiryNotifier = &FastBackend{}
+
+// NewFastBackend returns an instrumented app.RemovableFastBackend. kind is used as the backend label (e.g. s3)
+func NewFastBackend(kind string, f app.RemovableFastBackend) *FastBackend {
+	return &FastBackend{
+		kind:    kind,
+		backend: f,
+	}
+}
+
+func (f *FastBackend) SaveTTL(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
+	start := time.Now()
+	written, err := f.backend.SaveTTL(c, identifier, r, ttl)
+	observe(f.kind, "save", start, err)
+	return written, err
+}
+
+func (f *FastBackend) Overwrite(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
+	start := time.Now()
+	written, err := f.backend.Overwrite(c, identifier, r, ttl)
+	observe(f.kind, "overwrite", start, err)
+	return written, err
+}
+
+func (f *FastBackend) Retrieve(c context.Context, identifier string) (io.ReadCloser, error) {
+	start := time.Now()
+	r, err := f.backend.Retrieve(c, identifier)
+	observe(f.kind, "retrieve", start, err)
+	return r, err
+}
+
+func (f *FastBackend) Stat(c context.Context, identifier string) (app.Info, error) {
+	start := time.Now()
+	info, err := f.backend.Stat(c, identifier)
+	observe(f.kind, "stat", start, err)
+	return info, err
+}
+
+func (f *FastBackend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	start := time.Now()
+	items, next, err := f.backend.List(c, prefix, cursor, limit)
+	observe(f.kind, "list", start, err)
+	return items, next, err
+}
+
+func (f *FastBackend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	start := time.Now()
+	err := f.backend.Touch(c, identifier, ttl)
+	observe(f.kind, "touch", start, err)
+	return err
+}
+
+func (f *FastBackend) Delete(c context.Context, identifier string) error {
+	start := time.Now()
+	err := f.backend.Delete(c, identifier)
+	observe(f.kind, "delete", start, err)
+	return err
+}
+
+// OnExpire registers fn with the wrapped backend, if it removes expired data lazily
+func (f *FastBackend) OnExpire(fn func(c context.Context, identifier string)) {
+	if n, ok := f.backend.(app.ExpiryNotifier); ok {
+		n.OnExpire(fn)
+	}
+}
+
+func (f *FastBackend) Close() error {
+	return f.backend.Close()
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/zllovesuki/b/app"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBackend := app.NewMockRemovableBackend(ctrl)
+	b := NewBackend("test-backend", mockBackend)
+
+	ctx := context.Background()
+
+	mockBackend.EXPECT().SaveTTL(gomock.Any(), "a", []byte("a"), gomock.Any()).Return(nil)
+	mockBackend.EXPECT().SaveTTL(gomock.Any(), "b", []byte("b"), gomock.Any()).Return(app.ErrConflict)
+	mockBackend.EXPECT().Retrieve(gomock.Any(), "c").Return(nil, app.ErrNotFound)
+	mockBackend.EXPECT().Retrieve(gomock.Any(), "d").Return(nil, app.ErrExpired)
+	mockBackend.EXPECT().Delete(gomock.Any(), "e").Return(fmt.Errorf("error"))
+
+	require.NoError(t, b.SaveTTL(ctx, "a", []byte("a"), 0))
+	require.ErrorIs(t, b.SaveTTL(ctx, "b", []byte("b"), 0), app.ErrConflict)
+	_, err := b.Retrieve(ctx, "c")
+	require.ErrorIs(t, err, app.ErrNotFound)
+	_, err = b.Retrieve(ctx, "d")
+	require.ErrorIs(t, err, app.ErrNotFound)
+	require.Error(t, b.Delete(ctx, "e"))
+
+	require.Equal(t, float64(1), testutil.ToFloat64(backendOperations.WithLabelValues("test-backend", "save", resultOK)))
+	require.Equal(t, float64(1), testutil.ToFloat64(backendOperations.WithLabelValues("test-backend", "save", resultConflict)))
+	require.Equal(t, float64(1), testutil.ToFloat64(backendOperations.WithLabelValues("test-backend", "retrieve", resultNotFound)))
+	require.Equal(t, float64(1), testutil.ToFloat64(backendOperations.WithLabelValues("test-backend", "retrieve", resultExpired)))
+	require.Equal(t, float64(1), testutil.ToFloat64(backendOperations.WithLabelValues("test-backend", "delete", resultError)))
+}
+
+func TestFastBackendResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBackend := app.NewMockRemovableFastBackend(ctrl)
+	f := NewFastBackend("test-fast", mockBackend)
+
+	ctx := context.Background()
+	body := io.NopCloser(bytes.NewBufferString("hello"))
+
+	mockBackend.EXPECT().SaveTTL(gomock.Any(), "a", body, gomock.Any()).Return(int64(5), nil)
+	mockBackend.EXPECT().Retrieve(gomock.Any(), "a").Return(body, nil)
+
+	written, err := f.SaveTTL(ctx, "a", body, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), written)
+
+	r, err := f.Retrieve(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, body, r)
+
+	require.Equal(t, float64(1), testutil.ToFloat64(backendOperations.WithLabelValues("test-fast", "save", resultOK)))
+	require.Equal(t, float64(1), testutil.ToFloat64(backendOperations.WithLabelValues("test-fast", "retrieve", resultOK)))
+}
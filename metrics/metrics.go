@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "b"
+
+// Define collectors exported by the application
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests handled, partitioned by service, route, method and status code.",
+	}, []string{"service", "route", "method", "code"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests, partitioned by service, route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "route", "method"})
+
+	httpUploaded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "uploaded_bytes_total",
+		Help:      "Number of bytes read from request bodies, partitioned by service.",
+	}, []string{"service"})
+
+	httpDownloaded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "downloaded_bytes_total",
+		Help:      "Number of bytes written to response bodies, partitioned by service.",
+	}, []string{"service"})
+
+	backendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "backend",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of backend operations, partitioned by backend type and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"backend", "operation"})
+
+	backendOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "backend",
+		Name:      "operations_total",
+		Help:      "Number of backend operations, partitioned by backend type, operation and result.",
+	}, []string{"backend", "operation", "result"})
+)
+
+// Handler returns the http.Handler serving metrics in Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// services maps the route prefix to the service name used as label
+var services = map[string]string{
+	"f": "file",
+	"l": "link",
+	"t": "text",
+}
+
+// serviceName derives the service label from a chi route pattern (e.g. /f-{id} => file)
+func serviceName(route string) string {
+	switch route {
+	case "":
+		return "unknown"
+	case "/", "/*":
+		return "index"
+	}
+	segment := strings.TrimPrefix(route, "/")
+	if i := strings.Index(segment, "-"); i > 0 {
+		if name, ok := services[segment[:i]]; ok {
+			return name
+		}
+	}
+	return "other"
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Middleware records request count, latency and bytes transferred for every request.
+// Route patterns are used as labels instead of raw paths to keep cardinality bounded
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		var body *countingReader
+		if r.Body != nil {
+			body = &countingReader{ReadCloser: r.Body}
+			r.Body = body
+		}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := ""
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			route = rctx.RoutePattern()
+		}
+		service := serviceName(route)
+		if route == "" {
+			route = "unknown"
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		httpRequests.WithLabelValues(service, route, r.Method, strconv.Itoa(status)).Inc()
+		httpDuration.WithLabelValues(service, route, r.Method).Observe(time.Since(start).Seconds())
+		if body != nil {
+			httpUploaded.WithLabelValues(service).Add(float64(body.n))
+		}
+		httpDownloaded.WithLabelValues(service).Add(float64(ww.BytesWritten()))
+	})
+}
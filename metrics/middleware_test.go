@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceName(t *testing.T) {
+	cases := map[string]string{
+		"":                          "unknown",
+		"/":                         "index",
+		"/f-{id:[a-zA-Z0-9]+}":      "file",
+		"/l-{id:[a-zA-Z0-9]+}":      "link",
+		"/t-{id:[a-zA-Z0-9]+}.html": "text",
+		"/healthz":                  "other",
+	}
+	for route, expected := range cases {
+		require.Equal(t, expected, serviceName(route), route)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	route := "/t-{id:[a-zA-Z0-9]+}"
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Put(route, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("world"))
+	})
+
+	req, err := http.NewRequest("PUT", "/t-hello", bytes.NewBufferString("hello world"))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusCreated, recorder.Result().StatusCode)
+	require.Equal(t, float64(1), testutil.ToFloat64(httpRequests.WithLabelValues("text", route, "PUT", "201")))
+	require.Equal(t, float64(11), testutil.ToFloat64(httpUploaded.WithLabelValues("text")))
+	require.Equal(t, float64(5), testutil.ToFloat64(httpDownloaded.WithLabelValues("text")))
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	m := NewMemoryStore()
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	limit, err := NewLimit(1, time.Second, 2)
+	require.NoError(t, err)
+
+	testStore(t, m, limit, func(d time.Duration) {
+		now = now.Add(d)
+	})
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	m := NewMemoryStore()
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	limit, err := NewLimit(1, time.Second, 1)
+	require.NoError(t, err)
+
+	allowed, _, err := m.Take(context.Background(), "idle", limit)
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.Len(t, m.buckets, 1)
+
+	now = now.Add(sweepInterval + time.Second)
+	allowed, _, err = m.Take(context.Background(), "active", limit)
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.Len(t, m.buckets, 1)
+	require.Contains(t, m.buckets, "active")
+}
+
+func TestNewLimit(t *testing.T) {
+	limit, err := NewLimit(60, time.Minute, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1.0, limit.Rate)
+	require.Equal(t, 60, limit.Burst)
+
+	_, err = NewLimit(0, time.Minute, 0)
+	require.Error(t, err)
+
+	_, err = NewLimit(1, 0, 0)
+	require.Error(t, err)
+}
+
+// testStore expects limit to be 1 request per second with burst of 2,
+// and advance to move the clock of the store forward
+func testStore(t *testing.T, s Store, limit Limit, advance func(time.Duration)) {
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := s.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+
+	allowed, wait, err := s.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Greater(t, wait, time.Duration(0))
+	require.LessOrEqual(t, wait, time.Second)
+
+	// other clients have their own buckets
+	allowed, _, err = s.Take(ctx, "other", limit)
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	advance(wait)
+
+	allowed, _, err = s.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	allowed, _, err = s.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	require.False(t, allowed)
+}
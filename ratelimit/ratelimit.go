@@ -0,0 +1,94 @@
+// Package ratelimit provides per-client token bucket rate limiting for the public routes
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Limit describes a token bucket
+type Limit struct {
+	// Rate is the number of tokens replenished per second
+	Rate float64
+	// Burst is the capacity of the bucket, i.e. the number of requests allowed at once
+	Burst int
+}
+
+// NewLimit returns a Limit allowing requests per period on average, with at most burst at once.
+// Burst defaults to requests if 0
+func NewLimit(requests int, per time.Duration, burst int) (Limit, error) {
+	if requests <= 0 || per <= 0 || burst < 0 {
+		return Limit{}, errors.New("requests and period must be positive")
+	}
+	if burst == 0 {
+		burst = requests
+	}
+	return Limit{
+		Rate:  float64(requests) / per.Seconds(),
+		Burst: burst,
+	}, nil
+}
+
+// refill returns how long it takes to fill an empty bucket
+func (l Limit) refill() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Store keeps the state of token buckets
+type Store interface {
+	// Take removes a token from the bucket identified by key. If the bucket is empty,
+	// Take returns false and how long until a token is available
+	Take(c context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
+
+type clientCtxKey struct{}
+
+// WithClient returns a context identifying the client, such as an authenticated API token,
+// which is used as the rate limiting key instead of the client IP
+func WithClient(c context.Context, client string) context.Context {
+	return context.WithValue(c, clientCtxKey{}, client)
+}
+
+// ClientKey returns the identity of the client set by WithClient, or the client IP otherwise
+func ClientKey(r *http.Request) string {
+	if client, ok := r.Context().Value(clientCtxKey{}).(string); ok && client != "" {
+		return "client:" + client
+	}
+	return "ip:" + service.ClientIP(r)
+}
+
+// Middleware limits requests of each client to limit. name partitions the buckets, such that
+// limits of different services and routes are independent. Requests are allowed if the store fails
+func Middleware(store Store, name string, limit Limit, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := name + ":" + ClientKey(r)
+			allowed, wait, err := store.Take(r.Context(), key, limit)
+			if err != nil {
+				service.Logger(r, logger).Error("unable to take from rate limit store", zap.Error(err), zap.String("key", key))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !allowed {
+				retry := int64(math.Ceil(wait.Seconds()))
+				if retry < 1 {
+					retry = 1
+				}
+				w.Header().Set("Retry-After", strconv.FormatInt(retry, 10))
+				response.WriteError(w, r, response.ErrTooManyRequests().
+					AddMessages("Please retry after "+strconv.FormatInt(retry, 10)+" seconds"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
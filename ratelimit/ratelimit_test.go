@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zllovesuki/b/response"
+	"go.uber.org/zap"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(c context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("unavailable")
+}
+
+func limited(t *testing.T, s Store) http.Handler {
+	limit, err := NewLimit(1, time.Minute, 1)
+	require.NoError(t, err)
+
+	return Middleware(s, "test", limit, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func TestMiddleware(t *testing.T) {
+	h := limited(t, NewMemoryStore())
+
+	req := httptest.NewRequest("GET", "http://example.com/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+
+	var resp response.V1Response
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+	require.NotNil(t, resp.Error)
+
+	// different port of the same address is the same client
+	req.RemoteAddr = "192.0.2.1:4321"
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	req.RemoteAddr = "192.0.2.2:1234"
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestMiddlewareClient(t *testing.T) {
+	h := limited(t, NewMemoryStore())
+
+	for _, client := range []string{"alice", "bob"} {
+		req := httptest.NewRequest("GET", "http://example.com/", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req = req.WithContext(WithClient(req.Context(), client))
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+	}
+}
+
+func TestMiddlewareFailOpen(t *testing.T) {
+	h := limited(t, failingStore{})
+
+	req := httptest.NewRequest("GET", "http://example.com/", nil)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+}
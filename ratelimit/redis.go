@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+)
+
+// takeScript implements the token bucket atomically, using the clock of redis such that
+// all nodes agree on the time. Buckets expire once they would have been refilled.
+// Returns the remaining tokens, or -1 and microseconds until the next token if empty
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(state[1])
+local last = tonumber(state[2])
+if tokens == nil then
+	tokens = burst
+	last = now
+end
+
+tokens = math.min(burst, tokens + (now - last) / 1000000 * rate)
+if tokens < 1 then
+	return {-1, math.ceil((1 - tokens) / rate * 1000000)}
+end
+
+tokens = tokens - 1
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "last", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {tokens, 0}
+`)
+
+// RedisStore keeps token buckets in redis, such that limits are shared by multiple nodes
+type RedisStore struct {
+	cli    *redis.Client
+	prefix string
+}
+
+var _ Store = &RedisStore{}
+
+// NewRedisStore returns a Store using cli. Keys of buckets are prefixed with "ratelimit:"
+func NewRedisStore(cli *redis.Client) *RedisStore {
+	return &RedisStore{
+		cli:    cli,
+		prefix: "ratelimit:",
+	}
+}
+
+func (r *RedisStore) Take(c context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	res, err := takeScript.Run(c, r.cli, []string{r.prefix + key}, limit.Rate, limit.Burst).Int64Slice()
+	if err != nil {
+		return false, 0, errors.Wrap(err, "unexpected error from redis when taking token")
+	}
+	if len(res) != 2 {
+		return false, 0, errors.New("unexpected reply from redis when taking token")
+	}
+	if res[0] < 0 {
+		return false, time.Duration(res[1]) * time.Microsecond, nil
+	}
+	return true, 0, nil
+}
@@ -0,0 +1,29 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore(t *testing.T) {
+	cli := redis.NewClient(&redis.Options{
+		Addr: "127.0.0.1:6379",
+	})
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, cli.Ping(ctx).Err())
+
+	s := NewRedisStore(cli)
+	s.prefix = "ratelimit-testing:" + time.Now().Format(time.RFC3339Nano) + ":"
+
+	limit, err := NewLimit(1, time.Second, 2)
+	require.NoError(t, err)
+
+	testStore(t, s, limit, time.Sleep)
+}
@@ -68,3 +68,33 @@ func ErrInvalidJson() *Error {
 func ErrorMethodNotAllowed() *Error {
 	return makeError(http.StatusMethodNotAllowed).AddMessages("Method not allowed")
 }
+
+func ErrUnauthorized() *Error {
+	return makeError(http.StatusUnauthorized).
+		WithMessage("Unauthorized")
+}
+
+func ErrForbidden() *Error {
+	return makeError(http.StatusForbidden).
+		WithMessage("Forbidden")
+}
+
+func ErrPayloadTooLarge() *Error {
+	return makeError(http.StatusRequestEntityTooLarge).
+		WithMessage("Payload too large")
+}
+
+func ErrUnsupportedMediaType() *Error {
+	return makeError(http.StatusUnsupportedMediaType).
+		WithMessage("Unsupported media type")
+}
+
+func ErrInsufficientStorage() *Error {
+	return makeError(http.StatusInsufficientStorage).
+		WithMessage("Insufficient storage")
+}
+
+func ErrTooManyRequests() *Error {
+	return makeError(http.StatusTooManyRequests).
+		WithMessage("Too many requests")
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+var prefixRegex = regexp.MustCompile(`^[a-zA-Z0-9]*$`)
+
+// Page describes a listing request from the admin API
+type Page struct {
+	Prefix string
+	Cursor string
+	Limit  int
+}
+
+// ParsePage parses prefix, cursor and limit from query string of listing request
+func ParsePage(r *http.Request) (Page, *response.Error) {
+	q := r.URL.Query()
+	p := Page{
+		Prefix: q.Get("prefix"),
+		Cursor: q.Get("cursor"),
+		Limit:  defaultPageSize,
+	}
+	if !prefixRegex.MatchString(p.Prefix) {
+		return p, response.ErrBadRequest().AddMessages("prefix must be alphanumeric")
+	}
+	if l := q.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > maxPageSize {
+			return p, response.ErrBadRequest().AddMessages("limit must be between 1 and " + strconv.Itoa(maxPageSize))
+		}
+		p.Limit = limit
+	}
+	return p, nil
+}
+
+// Entry is the admin API representation of a stored item
+type Entry struct {
+	ID       string      `json:"id"`
+	URL      string      `json:"url"`
+	Size     int64       `json:"size"`
+	Created  *time.Time  `json:"created"`
+	Expires  *time.Time  `json:"expires"`
+	Owner    string      `json:"owner,omitempty"`
+	Metadata interface{} `json:"metadata,omitempty"`
+}
+
+// Listing is the admin API response for listing items
+type Listing struct {
+	Items  []Entry `json:"items"`
+	Cursor string  `json:"cursor"`
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// NewEntry converts app.Info of a backend key into an admin API entry, stripping the key prefix
+func NewEntry(baseURL, prefix string, info app.Info) Entry {
+	id := strings.TrimPrefix(info.ID, prefix)
+	return Entry{
+		ID:      id,
+		URL:     Ret(baseURL, prefix, id),
+		Size:    info.Size,
+		Created: timePtr(info.Created),
+		Expires: timePtr(info.Expires),
+	}
+}
+
+// ListFunc is the signature of List on app.Backend and app.FastBackend
+type ListFunc func(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error)
+
+// ListHandler returns a handler for the admin API listing items stored under prefix
+func ListHandler(baseURL, prefix string, logger *zap.Logger, list ListFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, perr := ParsePage(r)
+		if perr != nil {
+			response.WriteError(w, r, perr)
+			return
+		}
+
+		items, next, err := list(r.Context(), prefix+page.Prefix, page.Cursor, page.Limit)
+		if err != nil {
+			Logger(r, logger).Error("unable to list from backend", zap.Error(err), zap.String("prefix", prefix+page.Prefix))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to list items"))
+			return
+		}
+
+		listing := Listing{
+			Items:  make([]Entry, 0, len(items)),
+			Cursor: next,
+		}
+		for _, item := range items {
+			listing.Items = append(listing.Items, NewEntry(baseURL, prefix, item))
+		}
+
+		response.WriteResponse(w, r, listing)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+//go:generate mockgen -destination=events_mocks.go -package=service github.com/zllovesuki/b/service Events
+
+import (
+	"context"
+	"time"
+)
+
+// Actions of events emitted by services
+const (
+	ActionCreated = "created"
+	ActionDeleted = "deleted"
+	ActionExpired = "expired"
+)
+
+// Event notifies that an item was created, deleted or expired
+type Event struct {
+	// Type is the kind of the item and the action, such as "file.created"
+	Type string    `json:"type"`
+	Kind string    `json:"kind"`
+	ID   string    `json:"id"`
+	URL  string    `json:"url"`
+	Time time.Time `json:"time"`
+	// Owner is the user who saved the item, if any
+	Owner string `json:"owner,omitempty"`
+	// Expires is set on created events of items which expire
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// NewEvent returns the event of the item of kind identified by id
+func NewEvent(kind, action, id, url string) Event {
+	return Event{
+		Type: kind + "." + action,
+		Kind: kind,
+		ID:   id,
+		URL:  url,
+		Time: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// NewCreatedEvent returns the created event of the saved item
+func NewCreatedEvent(kind, id, owner string, saved Saved) Event {
+	e := NewEvent(kind, ActionCreated, id, saved.URL)
+	e.Owner = owner
+	e.Expires = saved.Expires
+	return e
+}
+
+// Events receives events emitted by services. Emit must not block on delivery
+type Events interface {
+	Emit(c context.Context, e Event)
+}
+
+// Emit sends the event to events, if configured
+func Emit(c context.Context, events Events, e Event) {
+	if events == nil {
+		return
+	}
+	events.Emit(c, e)
+}
@@ -9,6 +9,8 @@ import (
 	"mime"
 	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,8 +24,10 @@ import (
 )
 
 const (
-	filePrefix = "f-"
-	metaPrefix = "fm-"
+	kind         = "file"
+	filePrefix   = "f-"
+	metaPrefix   = "fm-"
+	secretPrefix = "fs-"
 )
 
 type Options struct {
@@ -31,12 +35,22 @@ type Options struct {
 	MetadataBackend app.RemovableBackend
 	FileBackend     app.RemovableFastBackend
 	Logger          *zap.Logger
+	// TTL is the optional policy enforced on save and extension
+	TTL *service.TTLPolicy
+	// MaxSize caps the size of the upload in bytes. 0 means unlimited
+	MaxSize int64
+	// Owners optionally records the owner of files saved by authenticated users
+	Owners service.Ownership
+	// Events optionally receives created, deleted and expired events of files
+	Events service.Events
 }
 
 type Service struct {
 	Options
 }
 
+var _ service.Items = &Service{}
+
 func (o *Options) validate() error {
 	if o.BaseURL == "" {
 		return errors.New("baseurl cannot be empty")
@@ -50,6 +64,9 @@ func (o *Options) validate() error {
 	if o.Logger == nil {
 		return errors.New("missing logger")
 	}
+	if o.MaxSize < 0 {
+		return errors.New("max size cannot be negative")
+	}
 	return nil
 }
 
@@ -57,9 +74,27 @@ func NewService(option Options) (*Service, error) {
 	if err := option.validate(); err != nil {
 		return nil, err
 	}
-	return &Service{
+	s := &Service{
 		Options: option,
-	}, nil
+	}
+	if n, ok := option.FileBackend.(app.ExpiryNotifier); ok && option.Events != nil {
+		n.OnExpire(s.expired)
+	}
+	return s, nil
+}
+
+// expired emits the expired event of files removed by the file backend
+func (s *Service) expired(c context.Context, identifier string) {
+	if !strings.HasPrefix(identifier, filePrefix) {
+		return
+	}
+	id := strings.TrimPrefix(identifier, filePrefix)
+	service.Emit(c, s.Events, service.NewEvent(kind, service.ActionExpired, id, service.Ret(s.BaseURL, filePrefix, id)))
+}
+
+// logger returns the request-scoped logger if available
+func (s *Service) logger(r *http.Request) *zap.Logger {
+	return service.Logger(r, s.Logger)
 }
 
 type Metadata struct {
@@ -77,7 +112,7 @@ func (s *Service) retrieveFile(w http.ResponseWriter, r *http.Request) {
 		response.WriteError(w, r, response.ErrNotFound().AddMessages("File either expired or does not exist"))
 		return
 	} else if err != nil {
-		s.Logger.Error("unable to retrieve from metadata backend", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("unable to retrieve from metadata backend", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Failed to locate file via metadata backend"))
 		return
 	}
@@ -85,18 +120,18 @@ func (s *Service) retrieveFile(w http.ResponseWriter, r *http.Request) {
 	var meta Metadata
 	err = json.Unmarshal(m, &meta)
 	if err != nil {
-		s.Logger.Error("unable to decode file metadata", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("unable to decode file metadata", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Invalid file metadata"))
 		return
 	}
 
 	fileReader, err := s.FileBackend.Retrieve(r.Context(), filePrefix+id)
 	if errors.Is(err, app.ErrNotFound) {
-		s.Logger.Error("file backend returned not found when metadata exists", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("file backend returned not found when metadata exists", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Failed to locate file via metadata backend"))
 		return
 	} else if err != nil {
-		s.Logger.Error("unable to retrieve from file backend", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("unable to retrieve from file backend", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Failed to locate file via metadata backend"))
 		return
 	}
@@ -108,8 +143,45 @@ func (s *Service) retrieveFile(w http.ResponseWriter, r *http.Request) {
 	// TODO(zllovesuki): This fails on macOS with Firefox (server has closed the connection)
 	written, err := io.Copy(w, app.NewCtxReader(r.Context(), fileReader))
 	if err != nil {
-		s.Logger.Warn("piping file buffer", zap.Error(err), zap.Int64("bytes-written", written))
+		s.logger(r).Warn("piping file buffer", zap.Error(err), zap.Int64("bytes-written", written))
+	}
+}
+
+func (s *Service) statFile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	m, err := s.MetadataBackend.Retrieve(r.Context(), metaPrefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve from metadata backend", zap.Error(err), zap.String("id", id))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var meta Metadata
+	err = json.Unmarshal(m, &meta)
+	if err != nil {
+		s.logger(r).Error("unable to decode file metadata", zap.Error(err), zap.String("id", id))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	info, err := s.FileBackend.Stat(r.Context(), filePrefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from file backend", zap.Error(err), zap.String("id", id))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
+
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": meta.Filename}))
+	w.Header().Set("Content-Type", meta.ContentType)
+	service.WriteInfoHeaders(w, info)
+	w.WriteHeader(http.StatusOK)
 }
 
 func (s *Service) saveFile(w http.ResponseWriter, r *http.Request) {
@@ -117,6 +189,26 @@ func (s *Service) saveFile(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
+	var ttl time.Duration
+	ttl, err = s.TTL.Parse(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	var qr *service.QR
+	qr, err = service.ParseSavedQR(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	if s.MaxSize > 0 && r.ContentLength > s.MaxSize {
+		response.WriteError(w, r, s.errTooLarge())
+		return
+	}
+	service.LimitBody(w, r, s.MaxSize)
+
 	var form *multipart.Reader
 	form, err = r.MultipartReader()
 	if err != nil {
@@ -124,22 +216,25 @@ func (s *Service) saveFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = s.MetadataBackend.Retrieve(r.Context(), metaPrefix+id)
+	_, err = s.MetadataBackend.Stat(r.Context(), metaPrefix+id)
 	if err == nil {
 		response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
 		return
 	} else if errors.Is(err, app.ErrNotFound) {
 		// fallthrough, allow override on expired file
 	} else {
-		s.Logger.Error("unable to check metadata backend prior to processing", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("unable to check metadata backend prior to processing", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save file"))
 		return
 	}
 
 	var p *multipart.Part
 	p, err = form.NextPart()
-	if err != nil && err != io.EOF {
-		s.Logger.Error("unable to read next part from multipart reader", zap.Error(err))
+	if service.TooLarge(err) {
+		response.WriteError(w, r, s.errTooLarge())
+		return
+	} else if err != nil && err != io.EOF {
+		s.logger(r).Error("unable to read next part from multipart reader", zap.Error(err))
 		response.WriteError(w, r, response.ErrUnexpected())
 		return
 	}
@@ -158,6 +253,25 @@ func (s *Service) saveFile(w http.ResponseWriter, r *http.Request) {
 	}
 	contentType := http.DetectContentType(buf)
 
+	var secret string
+	var digest []byte
+	secret, digest, err = service.NewSecret()
+	if err != nil {
+		s.logger(r).Error("unable to generate secret", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save file"))
+		return
+	}
+
+	err = s.MetadataBackend.SaveTTL(r.Context(), secretPrefix+id, digest, ttl)
+	if errors.Is(err, app.ErrConflict) {
+		response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to save secret to metadata backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save file"))
+		return
+	}
+
 	// we will check if we encoutered any error during upload path and clean up
 	defer func() {
 		if err == nil {
@@ -166,30 +280,63 @@ func (s *Service) saveFile(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 		defer cancel()
 		var wg sync.WaitGroup
-		wg.Add(2)
+		wg.Add(3)
 		go func() {
 			defer wg.Done()
 			if err := s.FileBackend.Delete(ctx, filePrefix+id); err != nil {
-				s.Logger.Error("removing failed upload from file backend", zap.Error(err), zap.String("id", id))
+				s.logger(r).Error("removing failed upload from file backend", zap.Error(err), zap.String("id", id))
 			}
 		}()
 		go func() {
 			defer wg.Done()
 			if err := s.MetadataBackend.Delete(ctx, metaPrefix+id); err != nil {
-				s.Logger.Error("removing failed upload from metadata backend", zap.Error(err), zap.String("id", id))
+				s.logger(r).Error("removing failed upload from metadata backend", zap.Error(err), zap.String("id", id))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := s.MetadataBackend.Delete(ctx, secretPrefix+id); err != nil {
+				s.logger(r).Error("removing secret of failed upload from metadata backend", zap.Error(err), zap.String("id", id))
 			}
 		}()
 		wg.Wait()
 	}()
 
+	if owner := service.User(r); owner != "" && s.Owners != nil {
+		err = s.Owners.Own(r.Context(), kind, id, owner, ttl)
+		if errors.Is(err, app.ErrConflict) {
+			response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+			return
+		} else if err != nil {
+			s.logger(r).Error("unable to record owner", zap.Error(err), zap.String("id", id))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save file"))
+			return
+		}
+
+		defer func() {
+			if err == nil {
+				return
+			}
+			if err := s.Owners.Disown(context.Background(), kind, id); err != nil {
+				s.logger(r).Error("removing owner of failed upload", zap.Error(err), zap.String("id", id))
+			}
+		}()
+	}
+
 	var written int64
-	written, err = s.FileBackend.SaveTTL(r.Context(), filePrefix+id, io.NopCloser(app.NewCtxReader(r.Context(), file)), 0)
+	written, err = s.FileBackend.SaveTTL(r.Context(), filePrefix+id, io.NopCloser(app.NewCtxReader(r.Context(), file)), ttl)
 	if errors.Is(err, app.ErrConflict) {
-		s.Logger.Error("metadata backend reported no conflict when checking but reported conflict on save", zap.String("id", id))
+		s.logger(r).Error("metadata backend reported no conflict when checking but reported conflict on save", zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save file"))
 		return
+	} else if service.TooLarge(err) {
+		response.WriteError(w, r, s.errTooLarge())
+		return
+	} else if errors.Is(err, app.ErrQuotaExceeded) {
+		response.WriteError(w, r, response.ErrInsufficientStorage().AddMessages("Storage quota exceeded"))
+		return
 	} else if err != nil {
-		s.Logger.Error("unable to save to file backend", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("unable to save to file backend", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save file"))
 		return
 	}
@@ -207,28 +354,212 @@ func (s *Service) saveFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = s.MetadataBackend.SaveTTL(r.Context(), metaPrefix+id, buf, 0)
+	err = s.MetadataBackend.SaveTTL(r.Context(), metaPrefix+id, buf, ttl)
 	if errors.Is(err, app.ErrConflict) {
-		s.Logger.Error("conflicting identifier in metadata backend when previous lookup reports no conflict", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("conflicting identifier in metadata backend when previous lookup reports no conflict", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save file metadata"))
 		return
 	} else if err != nil {
-		s.Logger.Error("unable to save to metadata backend", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("unable to save to metadata backend", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save file metadata"))
 		return
 	}
 
+	saved := service.NewSaved(s.BaseURL, filePrefix, id, ttl)
+	service.Emit(r.Context(), s.Events, service.NewCreatedEvent(kind, id, service.User(r), saved))
+
+	w.Header().Set(service.HeaderSecret, secret)
+	service.WriteSaved(w, r, service.WithQR(saved, s.logger(r), qr))
+}
+
+func (s *Service) qrFile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	q, err := service.ParseQR(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	_, err = s.MetadataBackend.Stat(r.Context(), metaPrefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("File either expired or does not exist"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from metadata backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve file"))
+		return
+	}
+
+	service.WriteQR(w, r, s.logger(r), q, service.Ret(s.BaseURL, filePrefix, id))
+}
+
+func (s *Service) errTooLarge() *response.Error {
+	return response.ErrPayloadTooLarge().AddMessages(fmt.Sprintf("File cannot be larger than %d bytes", s.MaxSize))
+}
+
+func (s *Service) touchFile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ttl, err := s.TTL.ParseExtend(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	digest, err := s.MetadataBackend.Retrieve(r.Context(), secretPrefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("File either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve secret from metadata backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to extend file"))
+		return
+	}
+
+	if !service.VerifySecret(r.Header.Get(service.HeaderSecret), digest) {
+		response.WriteError(w, r, response.ErrForbidden().AddMessages("Invalid secret"))
+		return
+	}
+
+	saved, err := s.Touch(r.Context(), id, ttl)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("File either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to touch in backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to extend file"))
+		return
+	}
+
+	service.WriteSaved(w, r, saved)
+}
+
+// Kind identifies files in service.Ownership
+func (s *Service) Kind() string {
+	return kind
+}
+
+// Policy returns the ttl policy of files
+func (s *Service) Policy() *service.TTLPolicy {
+	return s.TTL
+}
+
+// Inspect returns the entry of the file
+func (s *Service) Inspect(c context.Context, id string) (service.Entry, error) {
+	info, err := s.FileBackend.Stat(c, filePrefix+id)
+	if err != nil {
+		return service.Entry{}, err
+	}
+	return service.NewEntry(s.BaseURL, filePrefix, info), nil
+}
+
+// Touch extends the expiration of the file, along with its metadata, secret and owner
+func (s *Service) Touch(c context.Context, id string, ttl time.Duration) (service.Saved, error) {
+	touch := []struct {
+		key   string
+		touch func(context.Context, string, time.Duration) error
+	}{
+		{filePrefix + id, s.FileBackend.Touch},
+		{metaPrefix + id, s.MetadataBackend.Touch},
+		{secretPrefix + id, s.MetadataBackend.Touch},
+	}
+	for _, t := range touch {
+		if err := t.touch(c, t.key, ttl); err != nil {
+			return service.Saved{}, errors.Wrapf(err, "touching %s", t.key)
+		}
+	}
+	if s.Owners != nil {
+		if err := s.Owners.TouchOwner(c, kind, id, ttl); err != nil && !errors.Is(err, app.ErrNotFound) {
+			return service.Saved{}, err
+		}
+	}
+	return service.NewSaved(s.BaseURL, filePrefix, id, ttl), nil
+}
+
+// Remove deletes the file, along with its metadata, secret and owner
+func (s *Service) Remove(c context.Context, id string) error {
+	if err := s.FileBackend.Delete(c, filePrefix+id); err != nil {
+		return errors.Wrap(err, "deleting from file backend")
+	}
+	for _, key := range []string{metaPrefix + id, secretPrefix + id} {
+		if err := s.MetadataBackend.Delete(c, key); err != nil {
+			return errors.Wrap(err, "deleting from metadata backend")
+		}
+	}
+	if s.Owners != nil {
+		if err := s.Owners.Disown(c, kind, id); err != nil {
+			return err
+		}
+	}
+	service.Emit(c, s.Events, service.NewEvent(kind, service.ActionDeleted, id, service.Ret(s.BaseURL, filePrefix, id)))
+	return nil
+}
+
+func (s *Service) inspectFile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	m, err := s.MetadataBackend.Retrieve(r.Context(), metaPrefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("File either expired or does not exist"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve from metadata backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Failed to locate file via metadata backend"))
+		return
+	}
+
+	var meta Metadata
+	err = json.Unmarshal(m, &meta)
+	if err != nil {
+		s.logger(r).Error("unable to decode file metadata", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Invalid file metadata"))
+		return
+	}
+
+	info, err := s.FileBackend.Stat(r.Context(), filePrefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		// metadata without file, show what we know so it can be deleted
+		size, _ := strconv.ParseInt(meta.Size, 10, 64)
+		info = app.Info{
+			ID:   filePrefix + id,
+			Size: size,
+		}
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from file backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to stat file"))
+		return
+	}
+
+	entry := service.NewEntry(s.BaseURL, filePrefix, info)
+	entry.Owner = service.OwnerOf(r.Context(), s.Owners, kind, id)
+	entry.Metadata = meta
+
+	response.WriteResponse(w, r, entry)
+}
+
+func (s *Service) deleteFile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := s.Remove(r.Context(), id); err != nil {
+		s.logger(r).Error("unable to delete file", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to delete file"))
+		return
+	}
+
 	response.WriteResponse(w, r, service.Ret(s.BaseURL, filePrefix, id))
 }
 
-// SaveRoute returns a mountable router for saving file.
+// SaveRoute returns a mountable router for saving file and extending its expiration.
 // Alternatively, it can mount directly to the provided router
 func (s *Service) SaveRoute(r chi.Router) http.Handler {
 	if r == nil {
 		r = chi.NewRouter()
 	}
 
+	r.Put(service.Prefix(filePrefix, "{id:[a-zA-Z0-9]+}/{ttl}"), s.saveFile)
 	r.Put(service.Prefix(filePrefix, "{id:[a-zA-Z0-9]+}"), s.saveFile)
+	r.Patch(service.Prefix(filePrefix, "{id:[a-zA-Z0-9]+}/{ttl}"), s.touchFile)
+	r.Patch(service.Prefix(filePrefix, "{id:[a-zA-Z0-9]+}"), s.touchFile)
 
 	return r
 }
@@ -241,6 +572,22 @@ func (s *Service) RetrieveRoute(r chi.Router) http.Handler {
 	}
 
 	r.Get(service.Prefix(filePrefix, "{id:[a-zA-Z0-9]+}"), s.retrieveFile)
+	r.Head(service.Prefix(filePrefix, "{id:[a-zA-Z0-9]+}"), s.statFile)
+	r.Get(service.Prefix(filePrefix, "{id:[a-zA-Z0-9]+}/qr"), s.qrFile)
+
+	return r
+}
+
+// AdminRoute returns a mountable router for listing, inspecting and deleting files.
+// Alternatively, it can mount directly to the provided router.
+func (s *Service) AdminRoute(r chi.Router) http.Handler {
+	if r == nil {
+		r = chi.NewRouter()
+	}
+
+	r.Get("/file", service.ListHandler(s.BaseURL, filePrefix, s.Logger, s.FileBackend.List))
+	r.Get("/file/{id:[a-zA-Z0-9]+}", s.inspectFile)
+	r.Delete("/file/{id:[a-zA-Z0-9]+}", s.deleteFile)
 
 	return r
 }
@@ -14,7 +14,6 @@ import (
 	"time"
 
 	"github.com/zllovesuki/b/app"
-	"github.com/zllovesuki/b/response"
 	"github.com/zllovesuki/b/service"
 
 	"github.com/golang/mock/gomock"
@@ -249,6 +248,70 @@ func getMultipart(t *testing.T, file *os.File, meta Metadata) (io.Reader, *multi
 	return body, writer, length
 }
 
+func TestHeadFile(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		meta := Metadata{
+			Filename:    "image.jpg",
+			ContentType: "image/jpeg",
+		}
+		buf, err := json.Marshal(meta)
+		require.NoError(t, err)
+
+		r, err := http.NewRequest("HEAD", service.Prefix(filePrefix, id), nil)
+		require.NoError(t, err)
+
+		dep.mockMetadataBackend.EXPECT().
+			Retrieve(gomock.Any(), metaPrefix+id).
+			Return(buf, nil)
+
+		dep.mockFileBackend.EXPECT().
+			Stat(gomock.Any(), filePrefix+id).
+			Return(app.Info{
+				ID:   filePrefix + id,
+				Size: 1024,
+			}, nil)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, meta.ContentType, resp.Header.Get("Content-Type"))
+		require.Equal(t, "1024", resp.Header.Get("Content-Length"))
+		require.Contains(t, resp.Header.Get("Content-Disposition"), meta.Filename)
+	})
+
+	t.Run("file backend reported missing but metadata reported found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		buf, err := json.Marshal(Metadata{})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest("HEAD", service.Prefix(filePrefix, id), nil)
+		require.NoError(t, err)
+
+		dep.mockMetadataBackend.EXPECT().
+			Retrieve(gomock.Any(), metaPrefix+id).
+			Return(buf, nil)
+
+		dep.mockFileBackend.EXPECT().
+			Stat(gomock.Any(), filePrefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
 func TestSaveFile(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		dep, finish := getFixtures(t)
@@ -271,8 +334,12 @@ func TestSaveFile(t *testing.T) {
 		r.Header.Add("Content-Type", writer.FormDataContentType())
 
 		dep.mockMetadataBackend.EXPECT().
-			Retrieve(gomock.Any(), metaPrefix+id).
-			Return(nil, app.ErrNotFound)
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		dep.mockMetadataBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
 
 		dep.mockMetadataBackend.EXPECT().
 			SaveTTL(gomock.Any(), metaPrefix+id, buf, time.Duration(0)).
@@ -287,10 +354,57 @@ func TestSaveFile(t *testing.T) {
 		resp := dep.recorder.Result()
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 
-		var ret response.V1Response
+		var ret struct {
+			Result service.Saved
+		}
 		err = json.NewDecoder(resp.Body).Decode(&ret)
 		require.NoError(t, err)
-		require.Equal(t, service.Ret(dep.baseURL, filePrefix, id), ret.Result)
+		require.Equal(t, service.Ret(dep.baseURL, filePrefix, id), ret.Result.URL)
+		require.Nil(t, ret.Result.Expires)
+		require.Empty(t, resp.Header.Get(service.HeaderExpires))
+	})
+
+	t.Run("ttl request should work", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "wqrewr"
+		ttl := time.Hour
+		meta := Metadata{
+			Version:     1,
+			Filename:    "image.jpg",
+			ContentType: "image/jpeg",
+		}
+
+		body, writer, length := getMultipart(t, dep.testFile, meta)
+		meta.Size = fmt.Sprint(length)
+		buf, err := json.Marshal(meta)
+		require.NoError(t, err)
+
+		r, err := http.NewRequest("PUT", service.Prefix(filePrefix, id+"/1h"), body)
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+
+		dep.mockMetadataBackend.EXPECT().
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		dep.mockMetadataBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), ttl).
+			Return(nil)
+
+		dep.mockMetadataBackend.EXPECT().
+			SaveTTL(gomock.Any(), metaPrefix+id, buf, ttl).
+			Return(nil)
+
+		dep.mockFileBackend.EXPECT().
+			SaveTTL(gomock.Any(), filePrefix+id, gomock.Any(), ttl).
+			Return(length, nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
 	t.Run("conflicting id should return conflict", func(t *testing.T) {
@@ -314,8 +428,8 @@ func TestSaveFile(t *testing.T) {
 		r.Header.Add("Content-Type", writer.FormDataContentType())
 
 		dep.mockMetadataBackend.EXPECT().
-			Retrieve(gomock.Any(), metaPrefix+id).
-			Return(buf, nil)
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{ID: metaPrefix + id, Size: int64(len(buf))}, nil)
 
 		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
 
@@ -344,8 +458,12 @@ func TestSaveFile(t *testing.T) {
 		r.Header.Add("Content-Type", writer.FormDataContentType())
 
 		dep.mockMetadataBackend.EXPECT().
-			Retrieve(gomock.Any(), metaPrefix+id).
-			Return(nil, app.ErrNotFound)
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		dep.mockMetadataBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
 
 		dep.mockMetadataBackend.EXPECT().
 			SaveTTL(gomock.Any(), metaPrefix+id, buf, time.Duration(0)).
@@ -360,10 +478,14 @@ func TestSaveFile(t *testing.T) {
 		resp := dep.recorder.Result()
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 
-		var ret response.V1Response
+		var ret struct {
+			Result service.Saved
+		}
 		err = json.NewDecoder(resp.Body).Decode(&ret)
 		require.NoError(t, err)
-		require.Equal(t, service.Ret(dep.baseURL, filePrefix, id), ret.Result)
+		require.Equal(t, service.Ret(dep.baseURL, filePrefix, id), ret.Result.URL)
+		require.Nil(t, ret.Result.Expires)
+		require.Empty(t, resp.Header.Get(service.HeaderExpires))
 	})
 
 	t.Run("metadata backend error", func(t *testing.T) {
@@ -384,8 +506,8 @@ func TestSaveFile(t *testing.T) {
 		r.Header.Add("Content-Type", writer.FormDataContentType())
 
 		dep.mockMetadataBackend.EXPECT().
-			Retrieve(gomock.Any(), metaPrefix+id).
-			Return(nil, fmt.Errorf("error"))
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{}, fmt.Errorf("error"))
 
 		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
 
@@ -411,8 +533,12 @@ func TestSaveFile(t *testing.T) {
 		r.Header.Add("Content-Type", writer.FormDataContentType())
 
 		dep.mockMetadataBackend.EXPECT().
-			Retrieve(gomock.Any(), metaPrefix+id).
-			Return(nil, app.ErrNotFound)
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		dep.mockMetadataBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
 
 		dep.mockFileBackend.EXPECT().
 			SaveTTL(gomock.Any(), filePrefix+id, gomock.Any(), time.Duration(0)).
@@ -422,6 +548,9 @@ func TestSaveFile(t *testing.T) {
 		dep.mockMetadataBackend.EXPECT().
 			Delete(gomock.Any(), metaPrefix+id).
 			Return(nil)
+		dep.mockMetadataBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
 		dep.mockFileBackend.EXPECT().
 			Delete(gomock.Any(), filePrefix+id).
 			Return(nil)
@@ -453,8 +582,12 @@ func TestSaveFile(t *testing.T) {
 		r.Header.Add("Content-Type", writer.FormDataContentType())
 
 		dep.mockMetadataBackend.EXPECT().
-			Retrieve(gomock.Any(), metaPrefix+id).
-			Return(nil, app.ErrNotFound)
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		dep.mockMetadataBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
 
 		dep.mockFileBackend.EXPECT().
 			SaveTTL(gomock.Any(), filePrefix+id, gomock.Any(), time.Duration(0)).
@@ -468,6 +601,9 @@ func TestSaveFile(t *testing.T) {
 		dep.mockMetadataBackend.EXPECT().
 			Delete(gomock.Any(), metaPrefix+id).
 			Return(nil)
+		dep.mockMetadataBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
 		dep.mockFileBackend.EXPECT().
 			Delete(gomock.Any(), filePrefix+id).
 			Return(nil)
@@ -478,3 +614,183 @@ func TestSaveFile(t *testing.T) {
 		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	})
 }
+
+func TestSaveFileMaxSize(t *testing.T) {
+	dep, finish := getFixtures(t)
+	defer finish()
+
+	dep.service.MaxSize = 1024
+
+	body, writer, _ := getMultipart(t, dep.testFile, Metadata{
+		Filename: "image.jpg",
+	})
+
+	r, err := http.NewRequest("PUT", service.Prefix(filePrefix, "wqrewr"), body)
+	require.NoError(t, err)
+	r.Header.Add("Content-Type", writer.FormDataContentType())
+
+	dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+	resp := dep.recorder.Result()
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestAdminFile(t *testing.T) {
+	t.Run("inspect should return metadata", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		meta := Metadata{
+			Version:     1,
+			Filename:    "image.jpg",
+			ContentType: "image/jpeg",
+			Size:        "1024",
+		}
+		buf, err := json.Marshal(meta)
+		require.NoError(t, err)
+
+		r, err := http.NewRequest("GET", "/file/"+id, nil)
+		require.NoError(t, err)
+
+		dep.mockMetadataBackend.EXPECT().
+			Retrieve(gomock.Any(), metaPrefix+id).
+			Return(buf, nil)
+
+		dep.mockFileBackend.EXPECT().
+			Stat(gomock.Any(), filePrefix+id).
+			Return(app.Info{ID: filePrefix + id, Size: 1024}, nil)
+
+		dep.service.AdminRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var ret struct {
+			Result service.Entry
+		}
+		err = json.NewDecoder(resp.Body).Decode(&ret)
+		require.NoError(t, err)
+		require.Equal(t, id, ret.Result.ID)
+		require.Equal(t, int64(1024), ret.Result.Size)
+	})
+
+	t.Run("delete should remove file and metadata", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("DELETE", "/file/"+id, nil)
+		require.NoError(t, err)
+
+		dep.mockFileBackend.EXPECT().
+			Delete(gomock.Any(), filePrefix+id).
+			Return(nil)
+		dep.mockMetadataBackend.EXPECT().
+			Delete(gomock.Any(), metaPrefix+id).
+			Return(nil)
+		dep.mockMetadataBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+
+		dep.service.AdminRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestTouchFile(t *testing.T) {
+	secret, digest, err := service.NewSecret()
+	require.NoError(t, err)
+
+	t.Run("happy path", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		ttl := 60
+
+		r, err := http.NewRequest("PATCH", service.Prefix(filePrefix, fmt.Sprintf("%s/%d", id, ttl)), nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, secret)
+
+		dep.mockMetadataBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(digest, nil)
+		dep.mockFileBackend.EXPECT().
+			Touch(gomock.Any(), filePrefix+id, time.Second*time.Duration(ttl)).
+			Return(nil)
+		dep.mockMetadataBackend.EXPECT().
+			Touch(gomock.Any(), metaPrefix+id, time.Second*time.Duration(ttl)).
+			Return(nil)
+		dep.mockMetadataBackend.EXPECT().
+			Touch(gomock.Any(), secretPrefix+id, time.Second*time.Duration(ttl)).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("invalid secret should be forbidden", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("PATCH", service.Prefix(filePrefix, id), nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, "nope")
+
+		dep.mockMetadataBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(digest, nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestQRFile(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockMetadataBackend.EXPECT().
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{}, nil)
+
+		r, err := http.NewRequest("GET", service.Prefix(filePrefix, id+"/qr")+"?size=128", nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusOK, dep.recorder.Code)
+		require.Equal(t, "image/png", dep.recorder.Header().Get("Content-Type"))
+	})
+
+	t.Run("missing file should return not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockMetadataBackend.EXPECT().
+			Stat(gomock.Any(), metaPrefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		r, err := http.NewRequest("GET", service.Prefix(filePrefix, id+"/qr"), nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusNotFound, dep.recorder.Code)
+	})
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+)
+
+// headers describing stored items
+const (
+	HeaderCreated = "X-B-Created"
+	HeaderExpires = "X-B-Expires"
+)
+
+// WriteInfoHeaders sets Content-Length and timestamps of the stored item on the response.
+// Timestamps unknown to the backend are omitted
+func WriteInfoHeaders(w http.ResponseWriter, info app.Info) {
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	if !info.Created.IsZero() {
+		w.Header().Set(HeaderCreated, info.Created.UTC().Format(time.RFC3339))
+	}
+	if !info.Expires.IsZero() {
+		w.Header().Set(HeaderExpires, info.Expires.UTC().Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	idAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	idLength   = 10
+)
+
+// NewID returns a random alphanumeric identifier, for items whose identifier is chosen by the server
+func NewID() (string, error) {
+	id := make([]byte, 0, idLength)
+	buf := make([]byte, idLength*2)
+	for len(id) < idLength {
+		if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+			return "", errors.Wrap(err, "generating identifier")
+		}
+		for _, b := range buf {
+			// discard bytes beyond the largest multiple of the alphabet to avoid bias
+			if int(b) >= 256-256%len(idAlphabet) {
+				continue
+			}
+			id = append(id, idAlphabet[int(b)%len(idAlphabet)])
+			if len(id) == idLength {
+				break
+			}
+		}
+	}
+	return string(id), nil
+}
@@ -1,26 +1,38 @@
 package index
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/zllovesuki/b/account"
 	"github.com/zllovesuki/b/box"
+	"github.com/zllovesuki/b/service"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
+// discoveryTimeout bounds fetching the configuration of the OIDC provider at startup
+const discoveryTimeout = 10 * time.Second
+
 type Options struct {
 	Logger *zap.Logger
 	Asset  box.AssetExtractor
+	// OIDC enables login to the web UI with an OpenID Connect provider. Optional
+	OIDC *OIDCOptions
+	// Accounts stores users and sessions. Required if OIDC is enabled
+	Accounts *account.Store
 }
 
 type Service struct {
 	Options
 	indexPath string
+	oidc      *oidcLogin
 }
 
 func NewService(option Options) (*Service, error) {
@@ -31,10 +43,20 @@ func NewService(option Options) (*Service, error) {
 	if indexPath == "" {
 		return nil, errors.New("unable to extract index.html")
 	}
-	return &Service{
+	s := &Service{
 		Options:   option,
 		indexPath: indexPath,
-	}, nil
+	}
+	if option.OIDC != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+		defer cancel()
+		login, err := newOIDCLogin(ctx, option.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		s.oidc = login
+	}
+	return s, nil
 }
 
 func (o *Options) validate() error {
@@ -44,13 +66,21 @@ func (o *Options) validate() error {
 	if o.Asset == nil {
 		return errors.New("missing asset extractor")
 	}
+	if o.OIDC != nil && o.Accounts == nil {
+		return errors.New("oidc requires an account store")
+	}
 	return nil
 }
 
+// logger returns the request-scoped logger if available
+func (s *Service) logger(r *http.Request) *zap.Logger {
+	return service.Logger(r, s.Logger)
+}
+
 func (s *Service) index(w http.ResponseWriter, r *http.Request) {
 	file, err := os.Open(s.indexPath)
 	if err != nil {
-		s.Logger.Error("unable to open index.html", zap.String("path", s.indexPath), zap.Error(err))
+		s.logger(r).Error("unable to open index.html", zap.String("path", s.indexPath), zap.Error(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "unexpected error")
 		return
@@ -66,5 +96,14 @@ func (s *Service) Route() http.Handler {
 
 	r.Get("/", s.index)
 
+	if s.oidc != nil {
+		r.Route("/auth", func(r chi.Router) {
+			r.Get("/login", s.login)
+			r.Get("/callback", s.callback)
+			r.Get("/session", s.session)
+			r.Post("/logout", s.logout)
+		})
+	}
+
 	return r
 }
@@ -0,0 +1,332 @@
+package index
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/account"
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+const (
+	stateCookie = "b_oidc"
+	stateTTL    = 10 * time.Minute
+
+	defaultUsernameClaim = "preferred_username"
+	defaultSessionTTL    = 24 * time.Hour
+)
+
+// OIDCOptions configures login to the web UI with an OpenID Connect provider
+type OIDCOptions struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the absolute URL of /auth/callback registered with the provider
+	RedirectURL string
+	// Scopes requested from the provider. Defaults to openid, profile and email
+	Scopes []string
+	// UsernameClaim is the claim of the ID token used as display name. Defaults to preferred_username.
+	// Users are identified by the issuer and subject of the ID token instead
+	UsernameClaim string
+	// Grants maps scopes of b to the claims granting them: a scope is granted if any of its claims
+	// contains any of the values listed. Every scope is granted to every user if empty
+	Grants map[string]map[string][]string
+	// SessionTTL is how long sessions last. Defaults to 24 hours
+	SessionTTL time.Duration
+}
+
+func (o *OIDCOptions) validate() error {
+	if o.Issuer == "" {
+		return errors.New("missing oidc issuer")
+	}
+	if o.ClientID == "" {
+		return errors.New("missing oidc client id")
+	}
+	if o.RedirectURL == "" {
+		return errors.New("missing oidc redirect url")
+	}
+	for scope := range o.Grants {
+		if !knownScope(scope) {
+			return errors.Errorf("unknown scope %s in oidc grants", scope)
+		}
+	}
+	if len(o.Scopes) == 0 {
+		o.Scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	}
+	if o.UsernameClaim == "" {
+		o.UsernameClaim = defaultUsernameClaim
+	}
+	if o.SessionTTL <= 0 {
+		o.SessionTTL = defaultSessionTTL
+	}
+	return nil
+}
+
+func knownScope(scope string) bool {
+	for _, s := range account.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// grant returns the scopes granted by the claims
+func (o *OIDCOptions) grant(claims map[string]interface{}) []string {
+	if len(o.Grants) == 0 {
+		return append([]string{}, account.Scopes...)
+	}
+	scopes := make([]string, 0, len(account.Scopes))
+	for _, scope := range account.Scopes {
+		for claim, values := range o.Grants[scope] {
+			if claimContains(claims[claim], values) {
+				scopes = append(scopes, scope)
+				break
+			}
+		}
+	}
+	return scopes
+}
+
+// claimContains reports whether the claim, either a single value or a list, contains any of the values
+func claimContains(claim interface{}, values []string) bool {
+	var have []interface{}
+	switch v := claim.(type) {
+	case nil:
+		return false
+	case []interface{}:
+		have = v
+	default:
+		have = []interface{}{v}
+	}
+	for _, h := range have {
+		s := fmt.Sprint(h)
+		for _, v := range values {
+			if s == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type oidcLogin struct {
+	*OIDCOptions
+	verifier *oidc.IDTokenVerifier
+	oauth    oauth2.Config
+	secure   bool
+}
+
+func newOIDCLogin(ctx context.Context, o *OIDCOptions) (*oidcLogin, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	redirect, err := url.Parse(o.RedirectURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing oidc redirect url")
+	}
+	provider, err := oidc.NewProvider(ctx, o.Issuer)
+	if err != nil {
+		return nil, errors.Wrap(err, "discovering oidc provider")
+	}
+	return &oidcLogin{
+		OIDCOptions: o,
+		verifier:    provider.Verifier(&oidc.Config{ClientID: o.ClientID}),
+		oauth: oauth2.Config{
+			ClientID:     o.ClientID,
+			ClientSecret: o.ClientSecret,
+			RedirectURL:  o.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       o.Scopes,
+		},
+		secure: redirect.Scheme == "https",
+	}, nil
+}
+
+func randomHex() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SessionRes is the response of /auth/session
+type SessionRes struct {
+	User string `json:"user"`
+	// Name is the display name of the user, if given by the issuer
+	Name   string   `json:"name,omitempty"`
+	Scopes []string `json:"scopes"`
+}
+
+func (s *Service) login(w http.ResponseWriter, r *http.Request) {
+	state, err := randomHex()
+	if err != nil {
+		s.logger(r).Error("unable to generate state", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to login"))
+		return
+	}
+	nonce, err := randomHex()
+	if err != nil {
+		s.logger(r).Error("unable to generate nonce", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to login"))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    state + "." + nonce,
+		Path:     "/auth",
+		MaxAge:   int(stateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   s.oidc.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, s.oidc.oauth.AuthCodeURL(state, oidc.Nonce(nonce)), http.StatusFound)
+}
+
+func (s *Service) callback(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages("Login expired, please try again"))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Path:     "/auth",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.oidc.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	state, nonce, _ := strings.Cut(cookie.Value, ".")
+	query := r.URL.Query()
+	if subtle.ConstantTimeCompare([]byte(state), []byte(query.Get("state"))) != 1 {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages("Invalid login state, please try again"))
+		return
+	}
+	if reason := query.Get("error"); reason != "" {
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Login failed: "+reason))
+		return
+	}
+
+	token, err := s.oidc.oauth.Exchange(r.Context(), query.Get("code"))
+	if err != nil {
+		s.logger(r).Error("unable to exchange authorization code", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Unable to exchange authorization code"))
+		return
+	}
+	rawID, ok := token.Extra("id_token").(string)
+	if !ok {
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Missing ID token"))
+		return
+	}
+	idToken, err := s.oidc.verifier.Verify(r.Context(), rawID)
+	if err != nil {
+		s.logger(r).Error("unable to verify id token", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Invalid ID token"))
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(nonce), []byte(idToken.Nonce)) != 1 {
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Invalid ID token nonce"))
+		return
+	}
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		s.logger(r).Error("unable to decode id token claims", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Invalid ID token claims"))
+		return
+	}
+
+	name, _ := claims[s.oidc.UsernameClaim].(string)
+	scopes := s.oidc.grant(claims)
+	if len(scopes) == 0 {
+		response.WriteError(w, r, response.ErrForbidden().AddMessages("You are not granted access"))
+		return
+	}
+	// users are identified by issuer and subject, as usernames are neither unique nor stable
+	u, err := s.Accounts.Provision(r.Context(), idToken.Issuer, idToken.Subject, name)
+	if errors.Is(err, account.ErrInvalidSubject) {
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Missing ID token subject"))
+		return
+	} else if errors.Is(err, app.ErrConflict) {
+		response.WriteError(w, r, response.ErrForbidden().AddMessages("User is taken by another account"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to provision user", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to login"))
+		return
+	}
+	session, err := s.Accounts.CreateSession(r.Context(), u.ID, scopes, s.oidc.SessionTTL)
+	if err != nil {
+		s.logger(r).Error("unable to create session", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to login"))
+		return
+	}
+
+	s.logger(r).Info("user logged in", zap.String("user", u.ID), zap.Strings("scopes", scopes))
+	http.SetCookie(w, &http.Cookie{
+		Name:     account.SessionCookie,
+		Value:    session,
+		Path:     "/",
+		MaxAge:   int(s.oidc.SessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   s.oidc.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+func (s *Service) logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(account.SessionCookie); err == nil {
+		err := s.Accounts.DeleteSession(r.Context(), cookie.Value)
+		if err != nil && !errors.Is(err, account.ErrInvalidCredentials) {
+			s.logger(r).Error("unable to delete session", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to logout"))
+			return
+		}
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     account.SessionCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.oidc.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *Service) session(w http.ResponseWriter, r *http.Request) {
+	session := account.SessionFrom(r)
+	if session == nil {
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Not logged in"))
+		return
+	}
+	u, err := s.Accounts.User(r.Context(), session.User)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Not logged in"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve user", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve session"))
+		return
+	}
+	response.WriteResponse(w, r, SessionRes{
+		User:   session.User,
+		Name:   u.Name,
+		Scopes: session.Scopes,
+	})
+}
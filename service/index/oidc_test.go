@@ -0,0 +1,347 @@
+package index
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zllovesuki/b/account"
+	"github.com/zllovesuki/b/backend"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const testClientID = "b"
+
+type testAsset string
+
+func (a testAsset) Get(path string) string {
+	return string(a)
+}
+
+func (a testAsset) Close() {}
+
+// mockIssuer is a minimal OpenID Connect provider issuing ID tokens with claims
+type mockIssuer struct {
+	*httptest.Server
+	t      *testing.T
+	key    *rsa.PrivateKey
+	claims map[string]interface{}
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockIssuer{
+		t:   t,
+		key: key,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/keys", m.keys)
+	mux.HandleFunc("/token", m.token)
+	m.Server = httptest.NewServer(mux)
+	return m
+}
+
+func (m *mockIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                m.URL,
+		"authorization_endpoint":                m.URL + "/authorize",
+		"token_endpoint":                        m.URL + "/token",
+		"jwks_uri":                              m.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *mockIssuer) keys(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{
+			{
+				Key:       &m.key.PublicKey,
+				KeyID:     "test",
+				Algorithm: string(jose.RS256),
+				Use:       "sig",
+			},
+		},
+	})
+}
+
+func (m *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	require.NoError(m.t, r.ParseForm())
+	if r.PostForm.Get("code") != "code" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := map[string]interface{}{
+		"iss": m.URL,
+		"aud": testClientID,
+		"sub": "subject",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	payload, err := json.Marshal(claims)
+	require.NoError(m.t, err)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: m.key}, (&jose.SignerOptions{}).WithHeader("kid", "test"))
+	require.NoError(m.t, err)
+	signed, err := signer.Sign(payload)
+	require.NoError(m.t, err)
+	idToken, err := signed.CompactSerialize()
+	require.NoError(m.t, err)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+type oidcFixtures struct {
+	issuer   *mockIssuer
+	accounts *account.Store
+	handler  http.Handler
+}
+
+func getOIDCFixtures(t *testing.T) (*oidcFixtures, func()) {
+	issuer := newMockIssuer(t)
+
+	dir := t.TempDir()
+	index := filepath.Join(dir, "index.html")
+	require.NoError(t, os.WriteFile(index, []byte("index"), 0644))
+
+	b, err := backend.NewSQLiteBackend(filepath.Join(dir, "account.db"))
+	require.NoError(t, err)
+	accounts, err := account.NewStore(b)
+	require.NoError(t, err)
+
+	logger := zaptest.NewLogger(t)
+	s, err := NewService(Options{
+		Logger:   logger,
+		Asset:    testAsset(index),
+		Accounts: accounts,
+		OIDC: &OIDCOptions{
+			Issuer:       issuer.URL,
+			ClientID:     testClientID,
+			ClientSecret: "secret",
+			RedirectURL:  "http://b.example.com/auth/callback",
+			Grants: map[string]map[string][]string{
+				account.ScopeSave: {
+					"groups": {"uploaders"},
+				},
+				account.ScopeMe: {
+					"groups": {"uploaders", "viewers"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	return &oidcFixtures{
+			issuer:   issuer,
+			accounts: accounts,
+			handler:  account.Middleware(accounts, logger)(s.Route()),
+		}, func() {
+			issuer.Close()
+			require.NoError(t, b.Close())
+		}
+}
+
+func (f *oidcFixtures) do(t *testing.T, method, target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, nil)
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+	recorder := httptest.NewRecorder()
+	f.handler.ServeHTTP(recorder, r)
+	return recorder
+}
+
+func findCookie(t *testing.T, recorder *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range recorder.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	require.FailNow(t, "cookie not found", name)
+	return nil
+}
+
+// login goes through the authorization code flow with claims, returning the response of the callback
+func (f *oidcFixtures) login(t *testing.T, claims map[string]interface{}) *httptest.ResponseRecorder {
+	recorder := f.do(t, "GET", "/auth/login")
+	require.Equal(t, http.StatusFound, recorder.Code)
+	state := findCookie(t, recorder, stateCookie)
+	require.True(t, state.HttpOnly)
+
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, f.issuer.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	query := location.Query()
+	require.Equal(t, testClientID, query.Get("client_id"))
+	require.Equal(t, "http://b.example.com/auth/callback", query.Get("redirect_uri"))
+
+	f.issuer.claims = map[string]interface{}{
+		"nonce": query.Get("nonce"),
+	}
+	for k, v := range claims {
+		f.issuer.claims[k] = v
+	}
+	return f.do(t, "GET", "/auth/callback?code=code&state="+url.QueryEscape(query.Get("state")), state)
+}
+
+func TestOIDCLogin(t *testing.T) {
+	f, finish := getOIDCFixtures(t)
+	defer finish()
+
+	recorder := f.do(t, "GET", "/auth/session")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = f.login(t, map[string]interface{}{
+		"preferred_username": "alice",
+		"groups":             []string{"viewers"},
+	})
+	require.Equal(t, http.StatusFound, recorder.Code)
+	require.Equal(t, "/", recorder.Header().Get("Location"))
+	session := findCookie(t, recorder, account.SessionCookie)
+	require.True(t, session.HttpOnly)
+	require.Equal(t, http.SameSiteLaxMode, session.SameSite)
+
+	recorder = f.do(t, "GET", "/auth/session", session)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var res struct {
+		Result SessionRes `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&res))
+	id := account.ProvisionedID(f.issuer.URL, "subject")
+	require.Equal(t, id, res.Result.User)
+	require.Equal(t, "alice", res.Result.Name)
+	require.Equal(t, []string{account.ScopeMe}, res.Result.Scopes)
+
+	u, err := f.accounts.User(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, f.issuer.URL, u.Issuer)
+	require.Equal(t, "subject", u.Subject)
+
+	recorder = f.do(t, "POST", "/auth/logout", session)
+	require.Equal(t, http.StatusSeeOther, recorder.Code)
+	require.Equal(t, -1, findCookie(t, recorder, account.SessionCookie).MaxAge)
+
+	_, err = f.accounts.Session(context.Background(), session.Value)
+	require.ErrorIs(t, err, account.ErrInvalidCredentials)
+}
+
+func TestOIDCLoginDenied(t *testing.T) {
+	f, finish := getOIDCFixtures(t)
+	defer finish()
+
+	_, err := f.accounts.CreateUser(context.Background(), account.ProvisionedID(f.issuer.URL, "taken"), "correct horse")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		claims map[string]interface{}
+	}{
+		{
+			name: "no scope granted",
+			claims: map[string]interface{}{
+				"preferred_username": "alice",
+				"groups":             []string{"strangers"},
+			},
+		},
+		{
+			name: "local user",
+			claims: map[string]interface{}{
+				"sub":    "taken",
+				"groups": "uploaders",
+			},
+		},
+	}
+	for _, tc := range cases {
+		recorder := f.login(t, tc.claims)
+		require.Equal(t, http.StatusForbidden, recorder.Code, tc.name)
+	}
+}
+
+func TestOIDCLoginIdentifiedBySubject(t *testing.T) {
+	f, finish := getOIDCFixtures(t)
+	defer finish()
+
+	users := map[string]string{}
+	for _, sub := range []string{"alice", "mallory"} {
+		recorder := f.login(t, map[string]interface{}{
+			"sub":                sub,
+			"preferred_username": "john.doe",
+			"groups":             "uploaders",
+		})
+		require.Equal(t, http.StatusFound, recorder.Code, sub)
+
+		session, err := f.accounts.Session(context.Background(), findCookie(t, recorder, account.SessionCookie).Value)
+		require.NoError(t, err)
+		users[sub] = session.User
+	}
+	require.Equal(t, account.ProvisionedID(f.issuer.URL, "alice"), users["alice"])
+	require.NotEqual(t, users["alice"], users["mallory"])
+}
+
+func TestOIDCCallbackInvalid(t *testing.T) {
+	f, finish := getOIDCFixtures(t)
+	defer finish()
+
+	// missing state cookie
+	recorder := f.do(t, "GET", "/auth/callback?code=code&state=state")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// mismatched state
+	recorder = f.do(t, "GET", "/auth/login")
+	state := findCookie(t, recorder, stateCookie)
+	recorder = f.do(t, "GET", "/auth/callback?code=code&state=forged", state)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// rejected code
+	recorder = f.do(t, "GET", "/auth/login")
+	state = findCookie(t, recorder, stateCookie)
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	recorder = f.do(t, "GET", "/auth/callback?code=nope&state="+location.Query().Get("state"), state)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// mismatched nonce
+	f.issuer.claims = map[string]interface{}{
+		"nonce":              "forged",
+		"preferred_username": "alice",
+	}
+	recorder = f.do(t, "GET", "/auth/callback?code=code&state="+location.Query().Get("state"), state)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestOIDCGrant(t *testing.T) {
+	o := &OIDCOptions{}
+	require.Equal(t, account.Scopes, o.grant(nil))
+
+	o.Grants = map[string]map[string][]string{
+		account.ScopeSave: {
+			"email_verified": {"true"},
+		},
+	}
+	require.Equal(t, []string{account.ScopeSave}, o.grant(map[string]interface{}{"email_verified": true}))
+	require.Empty(t, o.grant(map[string]interface{}{"email_verified": false}))
+}
@@ -0,0 +1,197 @@
+package link
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// interstitialPrefix marks links saved to always show the preview before redirecting
+	interstitialPrefix = "li-"
+	// continueParam carries the token minted by the preview to redirect without showing it again.
+	// referrerParam carries the referrer of the preview, as the referrer of the redirect is the
+	// preview itself
+	continueParam = "go"
+	referrerParam = "ref"
+	// continueTTL is how long the continue link of a preview is valid
+	continueTTL = 10 * time.Minute
+)
+
+var previewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="en">
+    <head>
+        <meta charset="UTF-8" />
+        <meta name="viewport" content="width=device-width, initial-scale=1" />
+        <meta name="robots" content="noindex" />
+        <title>l-{{ .ID }} → {{ .Host }}</title>
+        <style>
+            body {
+                max-width: 40em;
+                margin: 0 auto;
+                padding: 2em 1em;
+                font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif;
+                line-height: 1.5;
+            }
+            .host {
+                font-size: 1.5em;
+                font-weight: bold;
+            }
+            .url {
+                font-family: SFMono-Regular, Consolas, Liberation Mono, Menlo, monospace;
+                overflow-wrap: anywhere;
+            }
+            dt {
+                font-weight: bold;
+            }
+            .continue {
+                display: inline-block;
+                margin-top: 1em;
+                padding: 0.5em 1em;
+                border: 1px solid;
+                border-radius: 4px;
+                color: inherit;
+                text-decoration: none;
+            }
+        </style>
+    </head>
+    <body>
+        {{- if .Interstitial }}
+        <p>You are about to leave for another website. Only continue if you trust it.</p>
+        {{- end }}
+        <p>l-{{ .ID }} redirects to</p>
+        <p class="host">{{ .Host }}</p>
+        <p class="url">{{ .URL }}</p>
+        <dl>
+            <dt>Created</dt>
+            <dd>{{ if .Created }}{{ .Created }}{{ else }}Unknown{{ end }}</dd>
+            <dt>Expires</dt>
+            <dd>{{ if .Expires }}{{ .Expires }}{{ else }}Never{{ end }}</dd>
+        </dl>
+        <a class="continue" href="{{ .Continue }}" rel="noreferrer nofollow">Continue to {{ .Host }}</a>
+    </body>
+</html>
+`))
+
+type previewData struct {
+	ID           string
+	URL          string
+	Host         string
+	Created      string
+	Expires      string
+	Interstitial bool
+	// Continue is the link redirecting to URL through the service, such that the click is counted
+	Continue string
+}
+
+// interstitial reports whether the link always shows the preview before redirecting
+func (s *Service) interstitial(c context.Context, id string) (bool, error) {
+	if s.Interstitial {
+		return true, nil
+	}
+	_, err := s.Backend.Retrieve(c, interstitialPrefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *Service) previewLink(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	long, err := s.Backend.Retrieve(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link"))
+		return
+	}
+
+	s.preview(w, r, id, string(long), false)
+}
+
+// preview renders the destination of the link, along with when it was created and expires.
+// The interstitial variant warns about leaving for another website
+func (s *Service) preview(w http.ResponseWriter, r *http.Request, id, long string, interstitial bool) {
+	info, err := s.Backend.Stat(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link"))
+		return
+	}
+
+	data := previewData{
+		ID:           id,
+		URL:          long,
+		Host:         long,
+		Interstitial: interstitial,
+		Continue:     s.continueURL(r, id, time.Now()),
+	}
+	if u, err := url.Parse(long); err == nil && u.Host != "" {
+		data.Host = u.Host
+	}
+	if !info.Created.IsZero() {
+		data.Created = info.Created.UTC().Format(time.RFC1123)
+	}
+	if !info.Expires.IsZero() {
+		data.Expires = info.Expires.UTC().Format(time.RFC1123)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := previewPage.Execute(w, data); err != nil {
+		s.logger(r).Warn("rendering link preview", zap.Error(err), zap.String("id", id))
+	}
+}
+
+// continueToken returns the token allowing to continue past the preview of the link until expires
+func (s *Service) continueToken(id string, expires int64) string {
+	exp := strconv.FormatInt(expires, 10)
+	mac := hmac.New(sha256.New, s.ContinueKey)
+	mac.Write([]byte(id + "." + exp))
+	return exp + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// continueURL returns the link redirecting past the preview, along with the referrer of the preview
+func (s *Service) continueURL(r *http.Request, id string, now time.Time) string {
+	q := url.Values{continueParam: {s.continueToken(id, now.Add(continueTTL).Unix())}}
+	if ref := referrerHost(r); ref != directReferrer {
+		q.Set(referrerParam, ref)
+	}
+	return service.Prefix(prefix, id) + "?" + q.Encode()
+}
+
+// continued reports whether the request carries a valid continue token of the link, minted by
+// its preview. Links shared with a forged or expired token show the preview again
+func (s *Service) continued(r *http.Request, id string, now time.Time) bool {
+	token := r.URL.Query().Get(continueParam)
+	exp, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(token), []byte(s.continueToken(id, expires)))
+}
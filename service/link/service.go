@@ -1,8 +1,13 @@
 package link
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/zllovesuki/b/app"
@@ -16,19 +21,46 @@ import (
 )
 
 const (
-	prefix = "l-"
+	kind         = "link"
+	prefix       = "l-"
+	secretPrefix = "ls-"
+	// defaultMaxSize is large enough for most urls
+	defaultMaxSize = 3192
 )
 
 type Options struct {
 	BaseURL string
-	Backend app.Backend
+	Backend app.RemovableBackend
 	Logger  *zap.Logger
+	// TTL is the optional policy enforced on save and extension
+	TTL *service.TTLPolicy
+	// MaxSize caps the size of the request body in bytes. Defaults to 3192 bytes
+	MaxSize int64
+	// Owners optionally records the owner of links saved by authenticated users
+	Owners service.Ownership
+	// Events optionally receives created, deleted and expired events of links
+	Events service.Events
+	// Interstitial shows the preview of every link before redirecting, instead of only
+	// links saved with interstitial
+	Interstitial bool
+	// StatsBackend optionally aggregates the clicks of links by day, recorded in the background
+	StatsBackend app.RemovableBackend
+	// ContinueKey signs the links continuing past previews, and must be shared by replicas.
+	// Generated randomly if empty
+	ContinueKey []byte
 }
 
 type Service struct {
 	Options
+	shard  string
+	clicks chan click
+	stop   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
 }
 
+var _ service.Items = &Service{}
+
 func (o *Options) validate() error {
 	if o.BaseURL == "" {
 		return errors.New("baseurl cannot be empty")
@@ -39,6 +71,9 @@ func (o *Options) validate() error {
 	if o.Logger == nil {
 		return errors.New("missing logger")
 	}
+	if o.MaxSize < 0 {
+		return errors.New("max size cannot be negative")
+	}
 	return nil
 }
 
@@ -46,23 +81,64 @@ func NewService(option Options) (*Service, error) {
 	if err := option.validate(); err != nil {
 		return nil, err
 	}
-	return &Service{
+	if option.MaxSize == 0 {
+		option.MaxSize = defaultMaxSize
+	}
+	if len(option.ContinueKey) == 0 {
+		option.ContinueKey = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, option.ContinueKey); err != nil {
+			return nil, errors.Wrap(err, "generating continue key")
+		}
+	}
+	s := &Service{
 		Options: option,
-	}, nil
+	}
+	if option.StatsBackend != nil {
+		shard, err := newShard()
+		if err != nil {
+			return nil, errors.Wrap(err, "generating stats shard")
+		}
+		s.shard = shard
+		s.clicks = make(chan click, clickQueueSize)
+		s.stop = make(chan struct{})
+		s.wg.Add(1)
+		go s.work()
+	}
+	return s, nil
+}
+
+// logger returns the request-scoped logger if available
+func (s *Service) logger(r *http.Request) *zap.Logger {
+	return service.Logger(r, s.Logger)
 }
 
 type SaveLinkReq struct {
 	URL string `json:"url"`
+	// Interstitial shows the preview of the link before redirecting
+	Interstitial bool `json:"interstitial"`
 }
 
 func (s *Service) saveLink(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	ttl := service.ParseTTL(r)
+	ttl, err := s.TTL.Parse(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
 
-	var req SaveLinkReq
-	r.Body = http.MaxBytesReader(w, r.Body, 3192) // only read upto 3kb
-	err := json.NewDecoder(r.Body).Decode(&req)
+	qr, err := service.ParseSavedQR(r)
 	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	var req SaveLinkReq
+	service.LimitBody(w, r, s.MaxSize)
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if service.TooLarge(err) {
+		response.WriteError(w, r, response.ErrPayloadTooLarge().AddMessages(fmt.Sprintf("Request cannot be larger than %d bytes", s.MaxSize)))
+		return
+	} else if err != nil {
 		response.WriteError(w, r, response.ErrInvalidJson())
 		return
 	}
@@ -74,17 +150,184 @@ func (s *Service) saveLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = s.Backend.SaveTTL(r.Context(), prefix+id, []byte(req.URL), time.Second*time.Duration(ttl))
+	secret, digest, err := service.NewSecret()
+	if err != nil {
+		s.logger(r).Error("unable to generate secret", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save link"))
+		return
+	}
+
+	// secret is saved first so a conflict leaves the existing link untouched
+	err = s.Backend.SaveTTL(r.Context(), secretPrefix+id, digest, ttl)
 	if errors.Is(err, app.ErrConflict) {
 		response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
 		return
 	} else if err != nil {
-		s.Logger.Error("unable to save to backend", zap.Error(err))
+		s.logger(r).Error("unable to save secret to backend", zap.Error(err))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save link"))
 		return
 	}
 
-	response.WriteResponse(w, r, service.Ret(s.BaseURL, prefix, id))
+	// remove the secret if we could not save the link
+	defer func() {
+		if err == nil {
+			return
+		}
+		if err := s.Backend.Delete(r.Context(), secretPrefix+id); err != nil {
+			s.logger(r).Error("removing secret of failed save from backend", zap.Error(err), zap.String("id", id))
+		}
+	}()
+
+	if owner := service.User(r); owner != "" && s.Owners != nil {
+		err = s.Owners.Own(r.Context(), kind, id, owner, ttl)
+		if errors.Is(err, app.ErrConflict) {
+			response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+			return
+		} else if err != nil {
+			s.logger(r).Error("unable to record owner", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save link"))
+			return
+		}
+
+		defer func() {
+			if err == nil {
+				return
+			}
+			if err := s.Owners.Disown(r.Context(), kind, id); err != nil {
+				s.logger(r).Error("removing owner of failed save", zap.Error(err), zap.String("id", id))
+			}
+		}()
+	}
+
+	if req.Interstitial {
+		err = s.Backend.SaveTTL(r.Context(), interstitialPrefix+id, []byte("1"), ttl)
+		if errors.Is(err, app.ErrConflict) {
+			response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+			return
+		} else if err != nil {
+			s.logger(r).Error("unable to save interstitial to backend", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save link"))
+			return
+		}
+
+		defer func() {
+			if err == nil {
+				return
+			}
+			if err := s.Backend.Delete(r.Context(), interstitialPrefix+id); err != nil {
+				s.logger(r).Error("removing interstitial of failed save from backend", zap.Error(err), zap.String("id", id))
+			}
+		}()
+	}
+
+	err = s.Backend.SaveTTL(r.Context(), prefix+id, []byte(req.URL), ttl)
+	if errors.Is(err, app.ErrConflict) {
+		response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to save to backend", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save link"))
+		return
+	}
+
+	saved := service.NewSaved(s.BaseURL, prefix, id, ttl)
+	service.Emit(r.Context(), s.Events, service.NewCreatedEvent(kind, id, service.User(r), saved))
+
+	w.Header().Set(service.HeaderSecret, secret)
+	service.WriteSaved(w, r, service.WithQR(saved, s.logger(r), qr))
+}
+
+func (s *Service) touchLink(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ttl, err := s.TTL.ParseExtend(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	digest, err := s.Backend.Retrieve(r.Context(), secretPrefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve secret from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to extend link"))
+		return
+	}
+
+	if !service.VerifySecret(r.Header.Get(service.HeaderSecret), digest) {
+		response.WriteError(w, r, response.ErrForbidden().AddMessages("Invalid secret"))
+		return
+	}
+
+	saved, err := s.Touch(r.Context(), id, ttl)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to touch in backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to extend link"))
+		return
+	}
+
+	service.WriteSaved(w, r, saved)
+}
+
+// Kind identifies links in service.Ownership
+func (s *Service) Kind() string {
+	return kind
+}
+
+// Policy returns the ttl policy of links
+func (s *Service) Policy() *service.TTLPolicy {
+	return s.TTL
+}
+
+// Inspect returns the entry of the link
+func (s *Service) Inspect(c context.Context, id string) (service.Entry, error) {
+	info, err := s.Backend.Stat(c, prefix+id)
+	if err != nil {
+		return service.Entry{}, err
+	}
+	return service.NewEntry(s.BaseURL, prefix, info), nil
+}
+
+// Touch extends the expiration of the link, along with its secret and owner
+func (s *Service) Touch(c context.Context, id string, ttl time.Duration) (service.Saved, error) {
+	for _, key := range []string{prefix + id, secretPrefix + id} {
+		if err := s.Backend.Touch(c, key, ttl); err != nil {
+			return service.Saved{}, err
+		}
+	}
+	// only links saved with interstitial are marked
+	if err := s.Backend.Touch(c, interstitialPrefix+id, ttl); err != nil && !errors.Is(err, app.ErrNotFound) {
+		return service.Saved{}, err
+	}
+	if s.Owners != nil {
+		if err := s.Owners.TouchOwner(c, kind, id, ttl); err != nil && !errors.Is(err, app.ErrNotFound) {
+			return service.Saved{}, err
+		}
+	}
+	return service.NewSaved(s.BaseURL, prefix, id, ttl), nil
+}
+
+// Remove deletes the link, along with its secret, owner and clicks
+func (s *Service) Remove(c context.Context, id string) error {
+	for _, key := range []string{prefix + id, secretPrefix + id, interstitialPrefix + id} {
+		if err := s.Backend.Delete(c, key); err != nil {
+			return err
+		}
+	}
+	if err := s.removeStats(c, id); err != nil {
+		return err
+	}
+	if s.Owners != nil {
+		if err := s.Owners.Disown(c, kind, id); err != nil {
+			return err
+		}
+	}
+	service.Emit(c, s.Events, service.NewEvent(kind, service.ActionDeleted, id, service.Ret(s.BaseURL, prefix, id)))
+	return nil
 }
 
 func (s *Service) retrieveLink(w http.ResponseWriter, r *http.Request) {
@@ -95,28 +338,140 @@ func (s *Service) retrieveLink(w http.ResponseWriter, r *http.Request) {
 		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
 		return
 	} else if err != nil {
-		s.Logger.Error("unable to retrieve from backend", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("unable to retrieve from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link"))
+		return
+	}
+
+	if r.URL.Query().Has("preview") {
+		s.preview(w, r, id, string(long), false)
+		return
+	}
+	// continuing from the preview redirects, and counts the click, without asking again
+	if s.continued(r, id, time.Now()) {
+		s.record(r, id)
+		http.Redirect(w, r, string(long), http.StatusFound)
+		return
+	}
+	interstitial, err := s.interstitial(r.Context(), id)
+	if err != nil {
+		s.logger(r).Error("unable to retrieve interstitial from backend", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link"))
 		return
 	}
+	if interstitial {
+		s.preview(w, r, id, string(long), true)
+		return
+	}
 
+	s.record(r, id)
 	http.Redirect(w, r, string(long), http.StatusFound)
 }
 
-// SaveRoute returns a mountable router for saving url redirect
+func (s *Service) qrLink(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	q, err := service.ParseQR(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+	// .png is always a png
+	if chi.URLParam(r, "ext") == "png" {
+		q.SVG = false
+	}
+
+	_, err = s.Backend.Stat(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link"))
+		return
+	}
+
+	service.WriteQR(w, r, s.logger(r), q, service.Ret(s.BaseURL, prefix, id))
+}
+
+func (s *Service) statLink(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	info, err := s.Backend.Stat(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	service.WriteInfoHeaders(w, info)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) inspectLink(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	info, err := s.Backend.Stat(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link"))
+		return
+	}
+
+	long, err := s.Backend.Retrieve(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link"))
+		return
+	}
+
+	entry := service.NewEntry(s.BaseURL, prefix, info)
+	entry.Owner = service.OwnerOf(r.Context(), s.Owners, kind, id)
+	entry.Metadata = SaveLinkReq{
+		URL: string(long),
+	}
+
+	response.WriteResponse(w, r, entry)
+}
+
+func (s *Service) deleteLink(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := s.Remove(r.Context(), id); err != nil {
+		s.logger(r).Error("unable to delete from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to delete link"))
+		return
+	}
+
+	response.WriteResponse(w, r, service.Ret(s.BaseURL, prefix, id))
+}
+
+// SaveRoute returns a mountable router for saving url redirect and extending its expiration.
 // Alternatively, it can mount directly to the provided router.
 func (s *Service) SaveRoute(r chi.Router) http.Handler {
 	if r == nil {
 		r = chi.NewRouter()
 	}
 
-	r.Put(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/{ttl:[0-9]+}"), s.saveLink)
+	r.Put(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/{ttl}"), s.saveLink)
 	r.Put(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.saveLink)
+	r.Patch(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/{ttl}"), s.touchLink)
+	r.Patch(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.touchLink)
 
 	return r
 }
 
-// RetrieveRoute returns a mountable router for retrieving url redirect
+// RetrieveRoute returns a mountable router for retrieving url redirect and its clicks.
 // Alternatively, it can mount directly to the provided router.
 func (s *Service) RetrieveRoute(r chi.Router) http.Handler {
 	if r == nil {
@@ -124,6 +479,27 @@ func (s *Service) RetrieveRoute(r chi.Router) http.Handler {
 	}
 
 	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.retrieveLink)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}+"), s.previewLink)
+	r.Head(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.statLink)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}.{ext:png}"), s.qrLink)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/qr"), s.qrLink)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/stats"), s.statsLink)
+
+	return r
+}
+
+// AdminRoute returns a mountable router for listing, inspecting and deleting url redirects,
+// and retrieving their clicks.
+// Alternatively, it can mount directly to the provided router.
+func (s *Service) AdminRoute(r chi.Router) http.Handler {
+	if r == nil {
+		r = chi.NewRouter()
+	}
+
+	r.Get("/link", service.ListHandler(s.BaseURL, prefix, s.Logger, s.Backend.List))
+	r.Get("/link/{id:[a-zA-Z0-9]+}", s.inspectLink)
+	r.Get("/link/{id:[a-zA-Z0-9]+}/stats", s.adminStatsLink)
+	r.Delete("/link/{id:[a-zA-Z0-9]+}", s.deleteLink)
 
 	return r
 }
@@ -2,34 +2,38 @@ package link
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/zllovesuki/b/app"
-	"github.com/zllovesuki/b/response"
 	"github.com/zllovesuki/b/service"
 
 	"github.com/golang/mock/gomock"
+	"github.com/skip2/go-qrcode"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 )
 
 type testDependencies struct {
 	baseURL     string
-	mockBackend *app.MockBackend
+	mockBackend *app.MockRemovableBackend
 	recorder    *httptest.ResponseRecorder
 	service     *Service
 }
 
 func getFixtures(t *testing.T) (*testDependencies, func()) {
 	ctrl := gomock.NewController(t)
-	mockBackend := app.NewMockBackend(ctrl)
+	mockBackend := app.NewMockRemovableBackend(ctrl)
 
 	recorder := httptest.NewRecorder()
 
@@ -68,6 +72,9 @@ func TestGetLink(t *testing.T) {
 		dep.mockBackend.EXPECT().
 			Retrieve(gomock.Any(), prefix+id).
 			Return([]byte(ret), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), interstitialPrefix+id).
+			Return(nil, app.ErrNotFound)
 
 		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
 
@@ -118,6 +125,56 @@ func TestGetLink(t *testing.T) {
 	})
 }
 
+func TestHeadLink(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		r, err := http.NewRequest("HEAD", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{
+				ID:      prefix + id,
+				Size:    18,
+				Created: created,
+				Expires: created.Add(time.Hour),
+			}, nil)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "2023-01-02T03:04:05Z", resp.Header.Get(service.HeaderCreated))
+		require.Equal(t, "2023-01-02T04:04:05Z", resp.Header.Get(service.HeaderExpires))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("HEAD", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
 func TestSaveLink(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		dep, finish := getFixtures(t)
@@ -134,6 +191,13 @@ func TestSaveLink(t *testing.T) {
 		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), bytes.NewBuffer(body))
 		require.NoError(t, err)
 
+		var digest []byte
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			DoAndReturn(func(_ context.Context, _ string, data []byte, _ time.Duration) error {
+				digest = data
+				return nil
+			})
 		dep.mockBackend.EXPECT().
 			SaveTTL(gomock.Any(), prefix+id, []byte(req.URL), time.Duration(0)).
 			Return(nil)
@@ -142,11 +206,16 @@ func TestSaveLink(t *testing.T) {
 
 		resp := dep.recorder.Result()
 		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.True(t, service.VerifySecret(resp.Header.Get(service.HeaderSecret), digest))
 
-		var ret response.V1Response
+		var ret struct {
+			Result service.Saved
+		}
 		err = json.NewDecoder(resp.Body).Decode(&ret)
 		require.NoError(t, err)
-		require.Equal(t, service.Ret(dep.baseURL, prefix, id), ret.Result)
+		require.Equal(t, service.Ret(dep.baseURL, prefix, id), ret.Result.URL)
+		require.Nil(t, ret.Result.Expires)
+		require.Empty(t, resp.Header.Get(service.HeaderExpires))
 	})
 
 	t.Run("bad url should return bad request", func(t *testing.T) {
@@ -186,7 +255,7 @@ func TestSaveLink(t *testing.T) {
 		require.NoError(t, err)
 
 		dep.mockBackend.EXPECT().
-			SaveTTL(gomock.Any(), prefix+id, []byte(req.URL), time.Duration(0)).
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
 			Return(app.ErrConflict)
 
 		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
@@ -210,17 +279,25 @@ func TestSaveLink(t *testing.T) {
 		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), bytes.NewBuffer(body))
 		require.NoError(t, err)
 
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
 		dep.mockBackend.EXPECT().
 			SaveTTL(gomock.Any(), prefix+id, []byte(req.URL), time.Duration(0)).
 			Return(fmt.Errorf("error"))
 
+		// secret should be removed as the link was not saved
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+
 		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
 
 		resp := dep.recorder.Result()
 		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	})
 
-	t.Run("ttl request should be validated at router level", func(t *testing.T) {
+	t.Run("invalid ttl should return bad request", func(t *testing.T) {
 		dep, finish := getFixtures(t)
 		defer finish()
 
@@ -231,7 +308,7 @@ func TestSaveLink(t *testing.T) {
 
 		resp := dep.recorder.Result()
 
-		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
 	t.Run("ttl request should work", func(t *testing.T) {
@@ -250,6 +327,9 @@ func TestSaveLink(t *testing.T) {
 		r, err := http.NewRequest("PUT", service.Prefix(prefix, fmt.Sprintf("%s/%d", id, ttl)), bytes.NewBuffer(body))
 		require.NoError(t, err)
 
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Second*time.Duration(ttl)).
+			Return(nil)
 		dep.mockBackend.EXPECT().
 			SaveTTL(gomock.Any(), prefix+id, []byte(req.URL), time.Second*time.Duration(ttl)).
 			Return(nil)
@@ -283,3 +363,1267 @@ func TestSaveLink(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 }
+
+func TestSaveLinkMaxSize(t *testing.T) {
+	dep, finish := getFixtures(t)
+	defer finish()
+
+	req := SaveLinkReq{
+		URL: "https://google.com/" + strings.Repeat("a", defaultMaxSize),
+	}
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	r, err := http.NewRequest("PUT", service.Prefix(prefix, "hello"), bytes.NewBuffer(body))
+	require.NoError(t, err)
+
+	dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+	resp := dep.recorder.Result()
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestSaveLinkTTLPolicy(t *testing.T) {
+	req := SaveLinkReq{
+		URL: "https://google.com",
+	}
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	t.Run("default ttl should apply and be returned", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.TTL = &service.TTLPolicy{
+			Default: time.Hour,
+			Max:     time.Hour * 24,
+		}
+		id := "hello"
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), bytes.NewBuffer(body))
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Hour).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, []byte(req.URL), time.Hour).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var ret struct {
+			Result service.Saved
+		}
+		err = json.NewDecoder(resp.Body).Decode(&ret)
+		require.NoError(t, err)
+		require.NotNil(t, ret.Result.Expires)
+		require.WithinDuration(t, time.Now().Add(time.Hour), *ret.Result.Expires, time.Second*5)
+		require.Equal(t, ret.Result.Expires.Format(time.RFC3339), resp.Header.Get(service.HeaderExpires))
+	})
+
+	t.Run("explicit permanence should override default ttl", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.TTL = &service.TTLPolicy{
+			Default:        time.Hour,
+			AllowPermanent: true,
+		}
+		id := "hello"
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id+"/0"), bytes.NewBuffer(body))
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, []byte(req.URL), time.Duration(0)).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("ttl beyond maximum should return bad request", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.TTL = &service.TTLPolicy{
+			Max: time.Hour * 24,
+		}
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, "hello/30d"), bytes.NewBuffer(body))
+		require.NoError(t, err)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("extending to permanent should be rejected when disallowed", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.TTL = &service.TTLPolicy{
+			Max: time.Hour * 24,
+		}
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, "hello"), nil)
+		require.NoError(t, err)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestTouchLink(t *testing.T) {
+	secret, digest, err := service.NewSecret()
+	require.NoError(t, err)
+
+	t.Run("happy path", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		ttl := 60
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, fmt.Sprintf("%s/%d", id, ttl)), nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, secret)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(digest, nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), prefix+id, time.Second*time.Duration(ttl)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), secretPrefix+id, time.Second*time.Duration(ttl)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), interstitialPrefix+id, time.Second*time.Duration(ttl)).
+			Return(app.ErrNotFound)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("no ttl should make link permanent", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, secret)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(digest, nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), prefix+id, time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), secretPrefix+id, time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), interstitialPrefix+id, time.Duration(0)).
+			Return(app.ErrNotFound)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("default ttl should not apply when extending", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.TTL = &service.TTLPolicy{
+			Default:        time.Hour,
+			AllowPermanent: true,
+		}
+
+		id := "hello"
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, id)+"?ttl=never", nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, secret)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(digest, nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), prefix+id, time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), secretPrefix+id, time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), interstitialPrefix+id, time.Duration(0)).
+			Return(app.ErrNotFound)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("invalid secret should be forbidden", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, "nope")
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(digest, nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, secret)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(nil, app.ErrNotFound)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestAdminLink(t *testing.T) {
+	t.Run("list should strip prefix and return cursor", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		r, err := http.NewRequest("GET", "/link?prefix=he&limit=1", nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			List(gomock.Any(), prefix+"he", "", 1).
+			Return([]app.Info{{ID: prefix + "hello", Size: 18}}, prefix+"hello", nil)
+
+		dep.service.AdminRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var ret struct {
+			Result service.Listing
+		}
+		err = json.NewDecoder(resp.Body).Decode(&ret)
+		require.NoError(t, err)
+		require.Len(t, ret.Result.Items, 1)
+		require.Equal(t, "hello", ret.Result.Items[0].ID)
+		require.Equal(t, service.Ret(dep.baseURL, prefix, "hello"), ret.Result.Items[0].URL)
+		require.Equal(t, prefix+"hello", ret.Result.Cursor)
+	})
+
+	t.Run("list should reject invalid prefix", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		r, err := http.NewRequest("GET", "/link?prefix=*", nil)
+		require.NoError(t, err)
+
+		dep.service.AdminRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("inspect not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		r, err := http.NewRequest("GET", "/link/hello", nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+"hello").
+			Return(app.Info{}, app.ErrNotFound)
+
+		dep.service.AdminRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("delete should remove from backend", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		r, err := http.NewRequest("DELETE", "/link/hello", nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), prefix+"hello").
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+"hello").
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), interstitialPrefix+"hello").
+			Return(nil)
+
+		dep.service.AdminRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestSaveLinkOwner(t *testing.T) {
+	getOwnerFixtures := func(t *testing.T) (*testDependencies, *service.MockOwnership, func()) {
+		dep, finish := getFixtures(t)
+		ctrl := gomock.NewController(t)
+		owners := service.NewMockOwnership(ctrl)
+		dep.service.Owners = owners
+		return dep, owners, func() {
+			ctrl.Finish()
+			finish()
+		}
+	}
+
+	saveRequest := func(t *testing.T, id, user string) *http.Request {
+		body, err := json.Marshal(SaveLinkReq{
+			URL: "https://google.com",
+		})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), bytes.NewBuffer(body))
+		require.NoError(t, err)
+		if user != "" {
+			r = r.WithContext(service.WithUser(r.Context(), user))
+		}
+		return r
+	}
+
+	t.Run("authenticated save records owner", func(t *testing.T) {
+		dep, owners, finish := getOwnerFixtures(t)
+		defer finish()
+
+		id := "owned"
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+		owners.EXPECT().
+			Own(gomock.Any(), kind, id, "alice", time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, saveRequest(t, id, "alice"))
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("anonymous save has no owner", func(t *testing.T) {
+		dep, _, finish := getOwnerFixtures(t)
+		defer finish()
+
+		id := "anonymous"
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, saveRequest(t, id, ""))
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("failed save removes owner and secret", func(t *testing.T) {
+		dep, owners, finish := getOwnerFixtures(t)
+		defer finish()
+
+		id := "failed"
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+		owners.EXPECT().
+			Own(gomock.Any(), kind, id, "alice", time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			Return(fmt.Errorf("boom"))
+		owners.EXPECT().
+			Disown(gomock.Any(), kind, id).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, saveRequest(t, id, "alice"))
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("item owned by others should return conflict", func(t *testing.T) {
+		dep, owners, finish := getOwnerFixtures(t)
+		defer finish()
+
+		id := "taken"
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+		owners.EXPECT().
+			Own(gomock.Any(), kind, id, "alice", time.Duration(0)).
+			Return(app.ErrConflict)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, saveRequest(t, id, "alice"))
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("touch and remove carry the owner along", func(t *testing.T) {
+		dep, owners, finish := getOwnerFixtures(t)
+		defer finish()
+
+		id := "owned"
+		ttl := time.Hour
+
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), prefix+id, ttl).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), secretPrefix+id, ttl).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), interstitialPrefix+id, ttl).
+			Return(app.ErrNotFound)
+		owners.EXPECT().
+			TouchOwner(gomock.Any(), kind, id, ttl).
+			Return(app.ErrNotFound)
+
+		saved, err := dep.service.Touch(context.Background(), id, ttl)
+		require.NoError(t, err)
+		require.NotNil(t, saved.Expires)
+
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), prefix+id).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), interstitialPrefix+id).
+			Return(nil)
+		owners.EXPECT().
+			Disown(gomock.Any(), kind, id).
+			Return(nil)
+
+		require.NoError(t, dep.service.Remove(context.Background(), id))
+	})
+}
+
+func TestLinkEvents(t *testing.T) {
+	getEventFixtures := func(t *testing.T) (*testDependencies, *service.MockEvents, func()) {
+		dep, finish := getFixtures(t)
+		ctrl := gomock.NewController(t)
+		events := service.NewMockEvents(ctrl)
+		dep.service.Events = events
+		return dep, events, func() {
+			ctrl.Finish()
+			finish()
+		}
+	}
+
+	t.Run("save emits created event", func(t *testing.T) {
+		dep, events, finish := getEventFixtures(t)
+		defer finish()
+
+		id := "created"
+		ttl := time.Hour
+
+		body, err := json.Marshal(SaveLinkReq{
+			URL: "https://google.com",
+		})
+		require.NoError(t, err)
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id)+"?ttl=3600", bytes.NewBuffer(body))
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), ttl).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), ttl).
+			Return(nil)
+		events.EXPECT().
+			Emit(gomock.Any(), gomock.Any()).
+			Do(func(c context.Context, e service.Event) {
+				require.Equal(t, "link.created", e.Type)
+				require.Equal(t, id, e.ID)
+				require.Equal(t, service.Ret(dep.baseURL, prefix, id), e.URL)
+				require.NotNil(t, e.Expires)
+			})
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("conflict emits nothing", func(t *testing.T) {
+		dep, _, finish := getEventFixtures(t)
+		defer finish()
+
+		id := "conflict"
+
+		body, err := json.Marshal(SaveLinkReq{
+			URL: "https://google.com",
+		})
+		require.NoError(t, err)
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), bytes.NewBuffer(body))
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(app.ErrConflict)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("remove emits deleted event", func(t *testing.T) {
+		dep, events, finish := getEventFixtures(t)
+		defer finish()
+
+		id := "deleted"
+
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), prefix+id).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), interstitialPrefix+id).
+			Return(nil)
+		events.EXPECT().
+			Emit(gomock.Any(), gomock.Any()).
+			Do(func(c context.Context, e service.Event) {
+				require.Equal(t, "link.deleted", e.Type)
+				require.Equal(t, id, e.ID)
+			})
+
+		require.NoError(t, dep.service.Remove(context.Background(), id))
+	})
+}
+
+func TestQRLink(t *testing.T) {
+	t.Run("png of the public url", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{}, nil)
+
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id+".png")+"?format=svg", nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+
+		expected, err := service.QR{Size: 256, Level: qrcode.Medium}.Encode(service.Ret(dep.baseURL, prefix, id))
+		require.NoError(t, err)
+		require.Equal(t, expected, dep.recorder.Body.Bytes())
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{}, nil)
+
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id+"/qr")+"?format=svg", nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusOK, dep.recorder.Code)
+		require.Equal(t, "image/svg+xml", dep.recorder.Header().Get("Content-Type"))
+	})
+
+	t.Run("missing link should return not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id+".png"), nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusNotFound, dep.recorder.Code)
+	})
+
+	t.Run("save should respond with the qr code if requested", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		body, err := json.Marshal(SaveLinkReq{URL: "https://google.com"})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id)+"?qr=png", bytes.NewBuffer(body))
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var ret struct {
+			Result service.Saved
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&ret))
+		require.True(t, strings.HasPrefix(ret.Result.QR, "data:image/png;base64,"))
+	})
+
+	t.Run("invalid qr code should be rejected before saving", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		body, err := json.Marshal(SaveLinkReq{URL: "https://google.com"})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, "hello")+"?qr=gif", bytes.NewBuffer(body))
+		require.NoError(t, err)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusBadRequest, dep.recorder.Code)
+	})
+}
+
+func TestPreviewLink(t *testing.T) {
+	const ret = "https://google.com/search?q=b"
+
+	get := func(dep *testDependencies, uri string) *http.Response {
+		r, err := http.NewRequest("GET", uri, nil)
+		require.NoError(t, err)
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+		return dep.recorder.Result()
+	}
+
+	expectPreview := func(dep *testDependencies, id string) {
+		created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return([]byte(ret), nil)
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{Created: created}, nil)
+	}
+
+	for _, uri := range []string{"hello+", "hello?preview"} {
+		t.Run(uri, func(t *testing.T) {
+			dep, finish := getFixtures(t)
+			defer finish()
+
+			expectPreview(dep, "hello")
+
+			resp := get(dep, service.Prefix(prefix, uri))
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+
+			body := dep.recorder.Body.String()
+			require.Contains(t, body, `<p class="host">google.com</p>`)
+			require.Contains(t, body, `<p class="url">https://google.com/search?q=b</p>`)
+			require.Contains(t, body, `href="/l-hello?go=`)
+			require.Contains(t, body, "Mon, 02 Jan 2023 03:04:05 UTC")
+			require.Contains(t, body, "Never")
+			require.NotContains(t, body, "You are about to leave")
+		})
+	}
+
+	t.Run("links saved with interstitial should show the preview", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		expectPreview(dep, "hello")
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), interstitialPrefix+"hello").
+			Return([]byte("1"), nil)
+
+		resp := get(dep, service.Prefix(prefix, "hello"))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, dep.recorder.Body.String(), "You are about to leave")
+	})
+
+	t.Run("global interstitial should show the preview", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.Interstitial = true
+		expectPreview(dep, "hello")
+
+		resp := get(dep, service.Prefix(prefix, "hello"))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, dep.recorder.Body.String(), "You are about to leave")
+	})
+
+	t.Run("continue should redirect without the preview", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.Interstitial = true
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"hello").
+			Return([]byte(ret), nil)
+
+		r, err := http.NewRequest("GET", service.Prefix(prefix, "hello"), nil)
+		require.NoError(t, err)
+
+		resp := get(dep, dep.service.continueURL(r, "hello", time.Now()))
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+		require.Equal(t, ret, resp.Header.Get("Location"))
+	})
+
+	t.Run("forged continue should show the preview", func(t *testing.T) {
+		r, err := http.NewRequest("GET", service.Prefix(prefix, "hello"), nil)
+		require.NoError(t, err)
+
+		for name, uri := range map[string]func(s *Service) string{
+			"bare": func(*Service) string {
+				return service.Prefix(prefix, "hello?go=1")
+			},
+			"forged": func(*Service) string {
+				return service.Prefix(prefix, fmt.Sprintf("hello?go=%d.00", time.Now().Add(time.Minute).Unix()))
+			},
+			"expired": func(s *Service) string {
+				return s.continueURL(r, "hello", time.Now().Add(-2*continueTTL))
+			},
+			"other link": func(s *Service) string {
+				return strings.Replace(s.continueURL(r, "world", time.Now()), "world", "hello", 1)
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				dep, finish := getFixtures(t)
+				defer finish()
+
+				dep.service.Interstitial = true
+				expectPreview(dep, "hello")
+
+				resp := get(dep, uri(dep.service))
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.Contains(t, dep.recorder.Body.String(), "You are about to leave")
+			})
+		}
+	})
+
+	t.Run("missing link should return not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"hello").
+			Return(nil, app.ErrNotFound)
+
+		resp := get(dep, service.Prefix(prefix, "hello+"))
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("save should mark links with interstitial", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		body, err := json.Marshal(SaveLinkReq{URL: ret, Interstitial: true})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), bytes.NewBuffer(body))
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), interstitialPrefix+id, []byte("1"), time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, []byte(ret), time.Duration(0)).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusOK, dep.recorder.Code)
+	})
+}
+
+func TestLinkStats(t *testing.T) {
+	getStatsFixtures := func(t *testing.T) (*testDependencies, *app.MockRemovableBackend, *service.MockOwnership, func()) {
+		ctrl := gomock.NewController(t)
+		mockBackend := app.NewMockRemovableBackend(ctrl)
+		statsBackend := app.NewMockRemovableBackend(ctrl)
+		owners := service.NewMockOwnership(ctrl)
+
+		s, err := NewService(Options{
+			BaseURL:      "http://hello",
+			Backend:      mockBackend,
+			Logger:       zaptest.NewLogger(t),
+			Owners:       owners,
+			StatsBackend: statsBackend,
+		})
+		require.NoError(t, err)
+
+		return &testDependencies{
+				baseURL:     "http://hello",
+				mockBackend: mockBackend,
+				recorder:    httptest.NewRecorder(),
+				service:     s,
+			}, statsBackend, owners, func() {
+				s.Close()
+				ctrl.Finish()
+			}
+	}
+
+	encode := func(b bucket) []byte {
+		buf, err := json.Marshal(b)
+		require.NoError(t, err)
+		return buf
+	}
+
+	expectStats := func(dep *testDependencies, statsBackend *app.MockRemovableBackend, id string) {
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{ID: prefix + id}, nil)
+		statsBackend.EXPECT().
+			List(gomock.Any(), statsPrefix+id+"-", "", statsPageSize).
+			Return([]app.Info{{ID: statsPrefix + id + "-2023-01-03"}}, "next", nil)
+		statsBackend.EXPECT().
+			List(gomock.Any(), statsPrefix+id+"-", "next", statsPageSize).
+			Return([]app.Info{{ID: statsPrefix + id + "-2023-01-02"}}, "", nil)
+		statsBackend.EXPECT().
+			Retrieve(gomock.Any(), statsPrefix+id+"-2023-01-03").
+			Return(encode(bucket{
+				Clicks:    3,
+				Referrers: map[string]int{"example.com": 2, directReferrer: 1},
+				Agents:    map[string]int{"firefox": 3},
+				Visitors:  map[string]int{"aaaa": 2, "bbbb": 1},
+			}), nil)
+		statsBackend.EXPECT().
+			Retrieve(gomock.Any(), statsPrefix+id+"-2023-01-02").
+			Return(encode(bucket{
+				Clicks:    1,
+				Referrers: map[string]int{directReferrer: 1},
+				Agents:    map[string]int{"curl": 1},
+				Visitors:  map[string]int{"aaaa": 1},
+			}), nil)
+	}
+
+	requireStats := func(t *testing.T, dep *testDependencies) {
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res struct {
+			Result Stats `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		require.Equal(t, 4, res.Result.Clicks)
+		require.Len(t, res.Result.Days, 2)
+		require.Equal(t, "2023-01-02", res.Result.Days[0].Date)
+		require.Equal(t, 1, res.Result.Days[0].Visitors)
+		require.Equal(t, "2023-01-03", res.Result.Days[1].Date)
+		require.Equal(t, 3, res.Result.Days[1].Clicks)
+		require.Equal(t, 2, res.Result.Days[1].Visitors)
+		require.Equal(t, 2, res.Result.Days[1].Referrers["example.com"])
+		require.Equal(t, 3, res.Result.Days[1].Agents["firefox"])
+	}
+
+	statsRequest := func(t *testing.T, id, user string) *http.Request {
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id+"/stats"), nil)
+		require.NoError(t, err)
+		if user != "" {
+			r = r.WithContext(service.WithUser(r.Context(), user))
+		}
+		return r
+	}
+
+	t.Run("redirect should be counted in the background", func(t *testing.T) {
+		dep, statsBackend, _, finish := getStatsFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return([]byte("https://google.com"), nil).
+			Times(2)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), interstitialPrefix+id).
+			Return(nil, app.ErrNotFound).
+			Times(2)
+
+		var saved []byte
+		statsBackend.EXPECT().
+			Retrieve(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key string) ([]byte, error) {
+				require.Equal(t, statsKey(id, time.Now(), dep.service.shard), key)
+				if saved == nil {
+					return nil, app.ErrNotFound
+				}
+				return saved, nil
+			}).
+			Times(2)
+		statsBackend.EXPECT().
+			Overwrite(gomock.Any(), gomock.Any(), gomock.Any(), statsRetention).
+			DoAndReturn(func(_ context.Context, _ string, buf []byte, _ time.Duration) error {
+				saved = buf
+				return nil
+			}).
+			Times(2)
+
+		for _, addr := range []string{"192.0.2.1:1234", "192.0.2.200:1234"} {
+			r, err := http.NewRequest("GET", service.Prefix(prefix, id), nil)
+			require.NoError(t, err)
+			r.RemoteAddr = addr
+			r.Header.Set("Referer", "https://Example.com/announcement")
+			r.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0")
+
+			recorder := httptest.NewRecorder()
+			dep.service.RetrieveRoute(nil).ServeHTTP(recorder, r)
+			require.Equal(t, http.StatusFound, recorder.Code)
+		}
+
+		dep.service.Close()
+
+		var b bucket
+		require.NoError(t, json.Unmarshal(saved, &b))
+		require.Equal(t, 2, b.Clicks)
+		require.Equal(t, map[string]int{"example.com": 2}, b.Referrers)
+		require.Equal(t, map[string]int{"firefox": 2}, b.Agents)
+		// both clients are in the same /24
+		require.Len(t, b.Visitors, 1)
+	})
+
+	t.Run("continuing past the interstitial should be counted", func(t *testing.T) {
+		dep, statsBackend, _, finish := getStatsFixtures(t)
+		defer finish()
+
+		id := "hello"
+		dep.service.Interstitial = true
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return([]byte("https://google.com"), nil).
+			Times(2)
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{ID: prefix + id}, nil)
+
+		var saved []byte
+		statsBackend.EXPECT().
+			Retrieve(gomock.Any(), gomock.Any()).
+			Return(nil, app.ErrNotFound)
+		statsBackend.EXPECT().
+			Overwrite(gomock.Any(), gomock.Any(), gomock.Any(), statsRetention).
+			DoAndReturn(func(_ context.Context, _ string, buf []byte, _ time.Duration) error {
+				saved = buf
+				return nil
+			})
+
+		// showing the interstitial is not a click
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+		r.Header.Set("Referer", "https://example.com/announcement")
+
+		recorder := httptest.NewRecorder()
+		dep.service.RetrieveRoute(nil).ServeHTTP(recorder, r)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "You are about to leave")
+
+		href := regexp.MustCompile(`class="continue" href="([^"]+)"`).FindStringSubmatch(recorder.Body.String())
+		require.Len(t, href, 2)
+		continueURL := html.UnescapeString(href[1])
+		require.True(t, strings.HasPrefix(continueURL, service.Prefix(prefix, id)+"?go="))
+		require.True(t, strings.HasSuffix(continueURL, "&ref=example.com"))
+
+		// following the continue link is
+		r, err = http.NewRequest("GET", continueURL, nil)
+		require.NoError(t, err)
+		r.Header.Set("Referer", "http://hello"+service.Prefix(prefix, id))
+
+		recorder = httptest.NewRecorder()
+		dep.service.RetrieveRoute(nil).ServeHTTP(recorder, r)
+		require.Equal(t, http.StatusFound, recorder.Code)
+		require.Equal(t, "https://google.com", recorder.Header().Get("Location"))
+
+		dep.service.Close()
+
+		var b bucket
+		require.NoError(t, json.Unmarshal(saved, &b))
+		require.Equal(t, 1, b.Clicks)
+		require.Equal(t, map[string]int{"example.com": 1}, b.Referrers)
+	})
+
+	t.Run("owner should see the stats", func(t *testing.T) {
+		dep, statsBackend, owners, finish := getStatsFixtures(t)
+		defer finish()
+
+		owners.EXPECT().
+			Owner(gomock.Any(), kind, "hello").
+			Return("alice", nil)
+		expectStats(dep, statsBackend, "hello")
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, statsRequest(t, "hello", "alice"))
+
+		requireStats(t, dep)
+	})
+
+	t.Run("shards of a day should be merged", func(t *testing.T) {
+		dep, statsBackend, _, finish := getStatsFixtures(t)
+		defer finish()
+
+		id := "hello"
+		statsBackend.EXPECT().
+			List(gomock.Any(), statsPrefix+id+"-", "", statsPageSize).
+			Return([]app.Info{
+				{ID: statsPrefix + id + "-2023-01-02"},
+				{ID: statsPrefix + id + "-2023-01-02-aaaaaaaa"},
+				{ID: statsPrefix + id + "-2023-01-02-bbbbbbbb"},
+			}, "", nil)
+		for shard, b := range map[string]bucket{
+			"":          {Clicks: 1, Referrers: map[string]int{directReferrer: 1}, Agents: map[string]int{"curl": 1}, Visitors: map[string]int{"aaaa": 1}},
+			"-aaaaaaaa": {Clicks: 2, Referrers: map[string]int{"example.com": 2}, Agents: map[string]int{"firefox": 2}, Visitors: map[string]int{"aaaa": 1, "bbbb": 1}},
+			"-bbbbbbbb": {Clicks: 3, Referrers: map[string]int{"example.com": 3}, Agents: map[string]int{"firefox": 3}, Visitors: map[string]int{"cccc": 3}},
+		} {
+			statsBackend.EXPECT().
+				Retrieve(gomock.Any(), statsPrefix+id+"-2023-01-02"+shard).
+				Return(encode(b), nil)
+		}
+
+		stats, err := dep.service.Stats(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, 6, stats.Clicks)
+		require.Equal(t, []DailyStats{{
+			Date:      "2023-01-02",
+			Clicks:    6,
+			Visitors:  3,
+			Referrers: map[string]int{directReferrer: 1, "example.com": 5},
+			Agents:    map[string]int{"curl": 1, "firefox": 5},
+		}}, stats.Days)
+	})
+
+	t.Run("admin should see the stats", func(t *testing.T) {
+		dep, statsBackend, _, finish := getStatsFixtures(t)
+		defer finish()
+
+		expectStats(dep, statsBackend, "hello")
+
+		r, err := http.NewRequest("GET", "/link/hello/stats", nil)
+		require.NoError(t, err)
+
+		dep.service.AdminRoute(nil).ServeHTTP(dep.recorder, r)
+
+		requireStats(t, dep)
+	})
+
+	t.Run("others should not see the stats", func(t *testing.T) {
+		dep, _, owners, finish := getStatsFixtures(t)
+		defer finish()
+
+		owners.EXPECT().
+			Owner(gomock.Any(), kind, "hello").
+			Return("alice", nil)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, statsRequest(t, "hello", "bob"))
+
+		require.Equal(t, http.StatusNotFound, dep.recorder.Code)
+	})
+
+	t.Run("anonymous should authenticate", func(t *testing.T) {
+		dep, _, _, finish := getStatsFixtures(t)
+		defer finish()
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, statsRequest(t, "hello", ""))
+
+		require.Equal(t, http.StatusUnauthorized, dep.recorder.Code)
+	})
+
+	t.Run("remove should delete the stats", func(t *testing.T) {
+		dep, statsBackend, owners, finish := getStatsFixtures(t)
+		defer finish()
+
+		id := "hello"
+		for _, key := range []string{prefix + id, secretPrefix + id, interstitialPrefix + id} {
+			dep.mockBackend.EXPECT().
+				Delete(gomock.Any(), key).
+				Return(nil)
+		}
+		statsBackend.EXPECT().
+			List(gomock.Any(), statsPrefix+id+"-", "", statsPageSize).
+			Return([]app.Info{{ID: statsPrefix + id + "-2023-01-02"}}, "", nil)
+		statsBackend.EXPECT().
+			Delete(gomock.Any(), statsPrefix+id+"-2023-01-02").
+			Return(nil)
+		owners.EXPECT().
+			Disown(gomock.Any(), kind, id).
+			Return(nil)
+
+		require.NoError(t, dep.service.Remove(context.Background(), id))
+	})
+}
+
+func TestClickAttributes(t *testing.T) {
+	for ua, family := range map[string]string{
+		"":            "unknown",
+		"curl/8.0.1":  "curl",
+		"Googlebot/2": "bot",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0": "edge",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15":           "safari",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36":                 "chrome",
+		"something else": "other",
+	} {
+		require.Equal(t, family, agentFamily(ua), ua)
+	}
+
+	require.Equal(t, visitorHash("a", "192.0.2.1"), visitorHash("a", "192.0.2.254"))
+	require.NotEqual(t, visitorHash("a", "192.0.2.1"), visitorHash("a", "192.0.3.1"))
+	require.NotEqual(t, visitorHash("a", "192.0.2.1"), visitorHash("b", "192.0.2.1"))
+	require.Equal(t, visitorHash("a", "2001:db8:1::1"), visitorHash("a", "2001:db8:1:2::1"))
+	require.Len(t, visitorHash("a", "192.0.2.1"), 8)
+}
+
+func TestClickBucketLimits(t *testing.T) {
+	b := bucket{Referrers: map[string]int{}}
+	for i := 0; i < maxReferrers; i++ {
+		ref := fmt.Sprintf("%d.example.com", i)
+		b.addReferrer(ref)
+		b.addReferrer(ref)
+	}
+	b.addReferrer("popular.example.com")
+	b.addReferrer("popular.example.com")
+	b.addReferrer("popular.example.com")
+	b.addReferrer("once.example.com")
+
+	require.Len(t, b.Referrers, maxReferrers+1)
+	require.Equal(t, 3, b.Referrers["popular.example.com"])
+	require.Equal(t, 1, b.Referrers["once.example.com"])
+	require.Equal(t, 4, b.Referrers[otherReferrer])
+	total := 0
+	for _, n := range b.Referrers {
+		total += n
+	}
+	require.Equal(t, 2*maxReferrers+4, total)
+
+	merged := bucket{Referrers: map[string]int{otherReferrer: 1}}
+	for i := 0; i < maxReferrers+2; i++ {
+		merged.Referrers[fmt.Sprintf("%02d.example.com", i)] = i + 1
+	}
+	merged.topReferrers()
+	require.Len(t, merged.Referrers, maxReferrers+1)
+	require.NotContains(t, merged.Referrers, "00.example.com")
+	require.NotContains(t, merged.Referrers, "01.example.com")
+	require.Equal(t, 1+1+2, merged.Referrers[otherReferrer])
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statsBackend := app.NewMockRemovableBackend(ctrl)
+
+	s, err := NewService(Options{
+		BaseURL:      "http://hello",
+		Backend:      app.NewMockRemovableBackend(ctrl),
+		Logger:       zaptest.NewLogger(t),
+		StatsBackend: statsBackend,
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	full := bucket{Clicks: maxVisitors, Referrers: map[string]int{}, Agents: map[string]int{}, Visitors: map[string]int{}}
+	for i := 0; i < maxVisitors; i++ {
+		full.Visitors[fmt.Sprintf("%08x", i)] = 1
+	}
+	buf, err := json.Marshal(full)
+	require.NoError(t, err)
+
+	var saved []byte
+	statsBackend.EXPECT().
+		Retrieve(gomock.Any(), gomock.Any()).
+		Return(buf, nil)
+	statsBackend.EXPECT().
+		Overwrite(gomock.Any(), gomock.Any(), gomock.Any(), statsRetention).
+		DoAndReturn(func(_ context.Context, _ string, buf []byte, _ time.Duration) error {
+			saved = buf
+			return nil
+		})
+
+	s.count(click{ID: "hello", Time: time.Now(), Referrer: directReferrer, Agent: "curl", Visitor: "ffffffff"})
+
+	b = bucket{}
+	require.NoError(t, json.Unmarshal(saved, &b))
+	require.Equal(t, maxVisitors+1, b.Clicks)
+	require.Len(t, b.Visitors, maxVisitors)
+}
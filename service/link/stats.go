@@ -0,0 +1,475 @@
+package link
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// statsPrefix holds the daily buckets of clicks of links, as lc-{id}-{date}-{shard}
+	statsPrefix = "lc-"
+	statsDate   = "2006-01-02"
+	// statsRetention is how long daily buckets are kept after their last click
+	statsRetention = 90 * 24 * time.Hour
+	statsPageSize  = 100
+
+	clickQueueSize = 1024
+
+	directReferrer = "direct"
+	// otherReferrer counts the clicks of referrers beyond the most clicked maxReferrers of the day
+	otherReferrer = "other"
+	maxReferrers  = 20
+	// maxVisitors bounds the distinct visitors tracked in a day, beyond which visitors are undercounted
+	maxVisitors = 1000
+)
+
+// agentFamilies maps substrings of user agents to their family, in order of precedence
+var agentFamilies = []struct {
+	needle string
+	family string
+}{
+	{"bot", "bot"},
+	{"crawler", "bot"},
+	{"spider", "bot"},
+	{"curl/", "curl"},
+	{"wget/", "wget"},
+	{"edg/", "edge"},
+	{"opr/", "opera"},
+	{"firefox/", "firefox"},
+	{"fxios/", "firefox"},
+	{"chrome/", "chrome"},
+	{"crios/", "chrome"},
+	{"safari/", "safari"},
+}
+
+// click is a redirect pending aggregation
+type click struct {
+	ID       string
+	Time     time.Time
+	Referrer string
+	Agent    string
+	Visitor  string
+}
+
+// bucket aggregates the clicks of a link during a day
+type bucket struct {
+	Clicks    int            `json:"clicks"`
+	Referrers map[string]int `json:"referrers"`
+	Agents    map[string]int `json:"agents"`
+	// Visitors counts clicks by coarse hash of the client address
+	Visitors map[string]int `json:"visitors"`
+}
+
+// DailyStats are the clicks of a link during a day
+type DailyStats struct {
+	Date   string `json:"date"`
+	Clicks int    `json:"clicks"`
+	// Visitors is the number of distinct client networks
+	Visitors  int            `json:"visitors"`
+	Referrers map[string]int `json:"referrers"`
+	Agents    map[string]int `json:"agents"`
+}
+
+// Stats are the clicks of a link, oldest day first
+type Stats struct {
+	ID     string       `json:"id"`
+	Clicks int          `json:"clicks"`
+	Days   []DailyStats `json:"days"`
+}
+
+// statsKey returns the key of the bucket of the day counted by the shard. Each process counts
+// clicks in its own shard, which no other process writes to
+func statsKey(id string, t time.Time, shard string) string {
+	return statsPrefix + id + "-" + t.UTC().Format(statsDate) + "-" + shard
+}
+
+// newShard returns a random shard, distinguishing the buckets of this process from others
+// sharing the stats backend
+func newShard() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// referrerHost returns the host of the referrer, or "direct" if there is none. Clicks continuing
+// from the preview are attributed to the referrer of the preview
+func referrerHost(r *http.Request) string {
+	ref := r.Referer()
+	if q := r.URL.Query(); q.Has(continueParam) {
+		ref = ""
+		if host := q.Get(referrerParam); host != "" {
+			ref = "//" + host
+		}
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Hostname() == "" {
+		return directReferrer
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// agentFamily returns the family of the user agent, such as chrome or bot
+func agentFamily(ua string) string {
+	if ua == "" {
+		return "unknown"
+	}
+	ua = strings.ToLower(ua)
+	for _, f := range agentFamilies {
+		if strings.Contains(ua, f.needle) {
+			return f.family
+		}
+	}
+	return "other"
+}
+
+// visitorHash returns a short hash of the network of the client, /24 for IPv4 and /48 for IPv6,
+// salted with the link such that visitors cannot be correlated across links
+func visitorHash(id, addr string) string {
+	network := addr
+	if ip := net.ParseIP(addr); ip != nil {
+		if v4 := ip.To4(); v4 != nil {
+			network = v4.Mask(net.CIDRMask(24, 32)).String()
+		} else {
+			network = ip.Mask(net.CIDRMask(48, 128)).String()
+		}
+	}
+	sum := sha256.Sum256([]byte(id + "-" + network))
+	return hex.EncodeToString(sum[:4])
+}
+
+// record queues the redirect to be counted in the background. Clicks are dropped rather than
+// slowing down redirects when the queue is full
+func (s *Service) record(r *http.Request, id string) {
+	if s.StatsBackend == nil {
+		return
+	}
+	c := click{
+		ID:       id,
+		Time:     time.Now().UTC(),
+		Referrer: referrerHost(r),
+		Agent:    agentFamily(r.UserAgent()),
+		Visitor:  visitorHash(id, service.ClientIP(r)),
+	}
+	select {
+	case <-s.stop:
+		return
+	default:
+	}
+	select {
+	case s.clicks <- c:
+	default:
+		s.logger(r).Warn("click queue full, dropping click", zap.String("id", id))
+	}
+}
+
+func (s *Service) work() {
+	defer s.wg.Done()
+	for {
+		select {
+		case c := <-s.clicks:
+			s.count(c)
+		case <-s.stop:
+			// count the clicks queued before closing
+			for {
+				select {
+				case c := <-s.clicks:
+					s.count(c)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// addReferrer counts the click of the referrer. Once maxReferrers are counted, the least clicked
+// referrer is folded into "other" to make room, such that the most clicked referrers are kept
+func (b *bucket) addReferrer(ref string) {
+	if _, ok := b.Referrers[ref]; ok || ref == otherReferrer {
+		b.Referrers[ref]++
+		return
+	}
+	tracked := len(b.Referrers)
+	if _, ok := b.Referrers[otherReferrer]; ok {
+		tracked--
+	}
+	if tracked >= maxReferrers {
+		least := ""
+		for r, n := range b.Referrers {
+			if r == otherReferrer {
+				continue
+			}
+			if least == "" || n < b.Referrers[least] || (n == b.Referrers[least] && r > least) {
+				least = r
+			}
+		}
+		b.Referrers[otherReferrer] += b.Referrers[least]
+		delete(b.Referrers, least)
+	}
+	b.Referrers[ref]++
+}
+
+// merge adds the clicks of another shard of the day
+func (b *bucket) merge(o bucket) {
+	b.Clicks += o.Clicks
+	for ref, n := range o.Referrers {
+		b.Referrers[ref] += n
+	}
+	for agent, n := range o.Agents {
+		b.Agents[agent] += n
+	}
+	for visitor, n := range o.Visitors {
+		b.Visitors[visitor] += n
+	}
+}
+
+// topReferrers keeps the most clicked maxReferrers referrers of the merged shards, folding the
+// rest into "other"
+func (b *bucket) topReferrers() {
+	refs := make([]string, 0, len(b.Referrers))
+	for ref := range b.Referrers {
+		if ref != otherReferrer {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) <= maxReferrers {
+		return
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if b.Referrers[refs[i]] != b.Referrers[refs[j]] {
+			return b.Referrers[refs[i]] > b.Referrers[refs[j]]
+		}
+		return refs[i] < refs[j]
+	})
+	for _, ref := range refs[maxReferrers:] {
+		b.Referrers[otherReferrer] += b.Referrers[ref]
+		delete(b.Referrers, ref)
+	}
+}
+
+// count adds the click to the bucket of its day. Buckets are sharded by process and only
+// updated by its worker, so reading and replacing them does not race, even with replicas
+// sharing the stats backend
+func (s *Service) count(c click) {
+	key := statsKey(c.ID, c.Time, s.shard)
+	logger := s.Logger.With(zap.String("id", c.ID), zap.String("key", key))
+	ctx := context.Background()
+
+	var b bucket
+	buf, err := s.StatsBackend.Retrieve(ctx, key)
+	if err == nil {
+		if err := json.Unmarshal(buf, &b); err != nil {
+			logger.Error("unable to decode click bucket", zap.Error(err))
+			return
+		}
+	} else if !errors.Is(err, app.ErrNotFound) {
+		logger.Error("unable to retrieve click bucket", zap.Error(err))
+		return
+	}
+	if b.Referrers == nil {
+		b.Referrers = map[string]int{}
+	}
+	if b.Agents == nil {
+		b.Agents = map[string]int{}
+	}
+	if b.Visitors == nil {
+		b.Visitors = map[string]int{}
+	}
+	b.Clicks++
+	b.addReferrer(c.Referrer)
+	b.Agents[c.Agent]++
+	if _, ok := b.Visitors[c.Visitor]; ok || len(b.Visitors) < maxVisitors {
+		b.Visitors[c.Visitor]++
+	}
+
+	buf, err = json.Marshal(b)
+	if err != nil {
+		logger.Error("unable to encode click bucket", zap.Error(err))
+		return
+	}
+	if err := s.StatsBackend.Overwrite(ctx, key, buf, statsRetention); err != nil {
+		logger.Error("unable to save click bucket", zap.Error(err))
+	}
+}
+
+// Close stops recording clicks, after counting the clicks already queued
+func (s *Service) Close() {
+	if s.StatsBackend == nil {
+		return
+	}
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+}
+
+// buckets returns the keys of the daily buckets of the link
+func (s *Service) buckets(c context.Context, id string) ([]string, error) {
+	var keys []string
+	cursor := ""
+	for {
+		items, next, err := s.StatsBackend.List(c, statsPrefix+id+"-", cursor, statsPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			keys = append(keys, item.ID)
+		}
+		if next == "" {
+			return keys, nil
+		}
+		cursor = next
+	}
+}
+
+// Stats returns the clicks of the link by day, merging the shards of each day
+func (s *Service) Stats(c context.Context, id string) (Stats, error) {
+	stats := Stats{
+		ID:   id,
+		Days: []DailyStats{},
+	}
+	keys, err := s.buckets(c, id)
+	if err != nil {
+		return Stats{}, errors.Wrap(err, "listing click buckets")
+	}
+	days := map[string]*bucket{}
+	for _, key := range keys {
+		buf, err := s.StatsBackend.Retrieve(c, key)
+		if errors.Is(err, app.ErrNotFound) {
+			// expired since listed
+			continue
+		} else if err != nil {
+			return Stats{}, errors.Wrap(err, "retrieving click bucket")
+		}
+		var b bucket
+		if err := json.Unmarshal(buf, &b); err != nil {
+			return Stats{}, errors.Wrap(err, "decoding click bucket")
+		}
+		date := strings.TrimPrefix(key, statsPrefix+id+"-")
+		if len(date) > len(statsDate) {
+			date = date[:len(statsDate)]
+		}
+		day, ok := days[date]
+		if !ok {
+			day = &bucket{
+				Referrers: map[string]int{},
+				Agents:    map[string]int{},
+				Visitors:  map[string]int{},
+			}
+			days[date] = day
+		}
+		day.merge(b)
+	}
+	for date, day := range days {
+		day.topReferrers()
+		stats.Clicks += day.Clicks
+		stats.Days = append(stats.Days, DailyStats{
+			Date:      date,
+			Clicks:    day.Clicks,
+			Visitors:  len(day.Visitors),
+			Referrers: day.Referrers,
+			Agents:    day.Agents,
+		})
+	}
+	sort.Slice(stats.Days, func(i, j int) bool {
+		return stats.Days[i].Date < stats.Days[j].Date
+	})
+	return stats, nil
+}
+
+// removeStats deletes the daily buckets of the link
+func (s *Service) removeStats(c context.Context, id string) error {
+	if s.StatsBackend == nil {
+		return nil
+	}
+	keys, err := s.buckets(c, id)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := s.StatsBackend.Delete(c, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeStats responds with the clicks of the link, or 404 if the link does not exist
+func (s *Service) writeStats(w http.ResponseWriter, r *http.Request, id string) {
+	if s.StatsBackend == nil {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link statistics are not recorded"))
+		return
+	}
+
+	_, err := s.Backend.Stat(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link statistics"))
+		return
+	}
+
+	stats, err := s.Stats(r.Context(), id)
+	if err != nil {
+		s.logger(r).Error("unable to retrieve link statistics", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link statistics"))
+		return
+	}
+
+	response.WriteResponse(w, r, stats)
+}
+
+// statsLink responds with the clicks of the link to its owner. Links of others are reported
+// as not found to avoid disclosing their existence
+func (s *Service) statsLink(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	user := service.User(r)
+	if user == "" {
+		w.Header().Set("WWW-Authenticate", `Basic realm="b"`)
+		response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Authenticate as the owner of the link"))
+		return
+	}
+	if s.Owners == nil {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	}
+
+	owner, err := s.Owners.Owner(r.Context(), kind, id)
+	if errors.Is(err, app.ErrNotFound) || (err == nil && owner != user) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Link either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve owner", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve link statistics"))
+		return
+	}
+
+	s.writeStats(w, r, id)
+}
+
+func (s *Service) adminStatsLink(w http.ResponseWriter, r *http.Request) {
+	s.writeStats(w, r, chi.URLParam(r, "id"))
+}
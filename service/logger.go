@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+type loggerCtxKey struct{}
+
+// WithLogger returns a copy of the context carrying the request-scoped logger
+func WithLogger(c context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(c, loggerCtxKey{}, logger)
+}
+
+// Logger returns the request-scoped logger installed by AccessLog, or fallback if there is none
+func Logger(r *http.Request, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := r.Context().Value(loggerCtxKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// ClientIP returns the IP address of the client of the request
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// AccessLog will log every request after it is served, and install a request-scoped
+// logger tagged with the request ID for handlers to use via Logger.
+// middleware.RequestID should be installed before this middleware
+func AccessLog(logger *zap.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			reqLogger := logger.With(zap.String("request-id", middleware.GetReqID(r.Context())))
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(WithLogger(r.Context(), reqLogger)))
+
+			route := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			reqLogger.Info("access",
+				zap.String("method", r.Method),
+				zap.String("route", route),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", status),
+				zap.Int("bytes", ww.BytesWritten()),
+				zap.Duration("duration", time.Since(start)),
+				zap.String("client-ip", ClientIP(r)),
+			)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLog(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(AccessLog(logger))
+	r.Get("/t-{id}", func(w http.ResponseWriter, r *http.Request) {
+		Logger(r, nil).Error("handler error")
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("hi"))
+	})
+
+	req, err := http.NewRequest("GET", "/t-hello", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "127.0.0.1:1234"
+
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+
+	handler := entries[0].ContextMap()
+	access := entries[1].ContextMap()
+
+	require.NotEmpty(t, handler["request-id"])
+	require.Equal(t, handler["request-id"], access["request-id"])
+	require.Equal(t, "/t-{id}", access["route"])
+	require.Equal(t, int64(http.StatusTeapot), access["status"])
+	require.Equal(t, int64(2), access["bytes"])
+	require.Equal(t, "127.0.0.1", access["client-ip"])
+}
+
+func TestLoggerFallback(t *testing.T) {
+	fallback := zap.NewNop()
+	req, err := http.NewRequest("GET", "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, fallback, Logger(req, fallback))
+}
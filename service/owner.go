@@ -0,0 +1,61 @@
+package service
+
+//go:generate mockgen -destination=owner_mocks.go -package=service github.com/zllovesuki/b/service Ownership
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type userCtxKey struct{}
+
+// WithUser returns a copy of the context carrying the ID of the authenticated user
+func WithUser(c context.Context, id string) context.Context {
+	return context.WithValue(c, userCtxKey{}, id)
+}
+
+// User returns the ID of the authenticated user of the request, or empty string if anonymous
+func User(r *http.Request) string {
+	id, _ := r.Context().Value(userCtxKey{}).(string)
+	return id
+}
+
+// Ownership records which user owns saved items. Items saved anonymously have no owner.
+// kind identifies the service, such as "link"
+type Ownership interface {
+	// Own records owner as the owner of the item until ttl, returning app.ErrConflict if it is already owned
+	Own(c context.Context, kind, id, owner string, ttl time.Duration) error
+	// Owner returns the owner of the item, or app.ErrNotFound if it has none
+	Owner(c context.Context, kind, id string) (string, error)
+	// TouchOwner extends the ownership of the item to ttl, returning app.ErrNotFound if it has no owner
+	TouchOwner(c context.Context, kind, id string, ttl time.Duration) error
+	// Disown removes the ownership of the item
+	Disown(c context.Context, kind, id string) error
+}
+
+// Items is implemented by services such that their items can be managed by their owners
+type Items interface {
+	// Kind identifies the service in Ownership
+	Kind() string
+	// Policy returns the ttl policy enforced when extending items
+	Policy() *TTLPolicy
+	// Inspect returns the entry of the item, or app.ErrNotFound if it does not exist
+	Inspect(c context.Context, id string) (Entry, error)
+	// Touch extends the expiration of the item, or app.ErrNotFound if it does not exist
+	Touch(c context.Context, id string, ttl time.Duration) (Saved, error)
+	// Remove deletes the item
+	Remove(c context.Context, id string) error
+}
+
+// OwnerOf returns the owner of the item, or empty string if it has none or ownership is not recorded
+func OwnerOf(c context.Context, o Ownership, kind, id string) string {
+	if o == nil {
+		return ""
+	}
+	owner, err := o.Owner(c, kind, id)
+	if err != nil {
+		return ""
+	}
+	return owner
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TTLPolicy bounds the ttl clients may request when saving or extending items.
+// A nil *TTLPolicy accepts any ttl
+type TTLPolicy struct {
+	// Default is used when the client does not request a ttl. 0 means never expire
+	Default time.Duration
+	// Min is the shortest ttl allowed. 0 means unbounded
+	Min time.Duration
+	// Max is the longest ttl allowed. 0 means unbounded
+	Max time.Duration
+	// AllowPermanent permits items that never expire
+	AllowPermanent bool
+}
+
+// Validate checks that the policy is satisfiable
+func (p *TTLPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.Default < 0 || p.Min < 0 || p.Max < 0 {
+		return errors.New("ttl policy cannot be negative")
+	}
+	if p.Max > 0 && p.Min > p.Max {
+		return errors.New("minimum ttl cannot be longer than maximum ttl")
+	}
+	if p.Default == 0 {
+		return nil
+	}
+	if p.Default < p.Min || (p.Max > 0 && p.Default > p.Max) {
+		return errors.New("default ttl must be between minimum and maximum ttl")
+	}
+	return nil
+}
+
+// Parse returns the effective ttl requested by r when saving. See ParseTTL for how the ttl is requested
+func (p *TTLPolicy) Parse(r *http.Request) (time.Duration, error) {
+	ttl, requested, err := ParseTTL(r)
+	if err != nil {
+		return 0, err
+	}
+	return p.Apply(ttl, requested)
+}
+
+// ParseExtend returns the effective ttl requested by r when extending. Unlike Parse, the default
+// does not apply: omitting the ttl asks for the item to never expire
+func (p *TTLPolicy) ParseExtend(r *http.Request) (time.Duration, error) {
+	ttl, _, err := ParseTTL(r)
+	if err != nil {
+		return 0, err
+	}
+	return p.Apply(ttl, true)
+}
+
+// Apply returns the effective ttl given the requested ttl, where 0 means never expire. Default is
+// used if no ttl was requested. ErrInvalidTTL is returned if the request violates the policy
+func (p *TTLPolicy) Apply(ttl time.Duration, requested bool) (time.Duration, error) {
+	if p == nil {
+		return ttl, nil
+	}
+	if !requested {
+		ttl = p.Default
+	}
+	if ttl == 0 {
+		if !p.AllowPermanent {
+			return 0, errors.Wrap(ErrInvalidTTL, "items must expire, please specify a ttl")
+		}
+		return 0, nil
+	}
+	if ttl < p.Min {
+		return 0, errors.Wrapf(ErrInvalidTTL, "ttl cannot be shorter than %s", p.Min)
+	}
+	if p.Max > 0 && ttl > p.Max {
+		return 0, errors.Wrapf(ErrInvalidTTL, "ttl cannot be longer than %s", p.Max)
+	}
+	return ttl, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLPolicy(t *testing.T) {
+	t.Run("nil policy accepts anything", func(t *testing.T) {
+		var p *TTLPolicy
+		require.NoError(t, p.Validate())
+
+		ttl, err := p.Apply(0, false)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ttl)
+	})
+
+	t.Run("default is used when unspecified", func(t *testing.T) {
+		p := &TTLPolicy{
+			Default: time.Hour,
+			Max:     time.Hour * 24,
+		}
+		require.NoError(t, p.Validate())
+
+		ttl, err := p.Apply(0, false)
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, ttl)
+
+		ttl, err = p.Apply(time.Minute, true)
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, ttl)
+	})
+
+	t.Run("bounds are enforced", func(t *testing.T) {
+		p := &TTLPolicy{
+			Min:            time.Minute,
+			Max:            time.Hour,
+			AllowPermanent: true,
+		}
+		require.NoError(t, p.Validate())
+
+		_, err := p.Apply(time.Second, true)
+		require.ErrorIs(t, err, ErrInvalidTTL)
+
+		_, err = p.Apply(time.Hour*2, true)
+		require.ErrorIs(t, err, ErrInvalidTTL)
+
+		ttl, err := p.Apply(time.Hour, true)
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, ttl)
+	})
+
+	t.Run("permanent items can be disallowed", func(t *testing.T) {
+		p := &TTLPolicy{}
+		_, err := p.Apply(0, false)
+		require.ErrorIs(t, err, ErrInvalidTTL)
+		_, err = p.Apply(0, true)
+		require.ErrorIs(t, err, ErrInvalidTTL)
+
+		p.AllowPermanent = true
+		ttl, err := p.Apply(0, false)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ttl)
+	})
+
+	t.Run("explicit permanence overrides default when allowed", func(t *testing.T) {
+		p := &TTLPolicy{
+			Default:        time.Hour,
+			AllowPermanent: true,
+		}
+		require.NoError(t, p.Validate())
+
+		ttl, err := p.Apply(0, false)
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, ttl)
+
+		ttl, err = p.Apply(0, true)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ttl)
+
+		p.AllowPermanent = false
+		_, err = p.Apply(0, true)
+		require.ErrorIs(t, err, ErrInvalidTTL)
+	})
+
+	t.Run("unsatisfiable policy should fail validation", func(t *testing.T) {
+		require.Error(t, (&TTLPolicy{Min: time.Hour, Max: time.Minute}).Validate())
+		require.Error(t, (&TTLPolicy{Default: time.Hour * 2, Max: time.Hour}).Validate())
+		require.Error(t, (&TTLPolicy{Default: time.Second, Min: time.Minute}).Validate())
+	})
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/zllovesuki/b/response"
+
+	"github.com/pkg/errors"
+	"github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 2048
+
+	qrPNG = "png"
+	qrSVG = "svg"
+)
+
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"l": qrcode.Low,
+	"m": qrcode.Medium,
+	"q": qrcode.High,
+	"h": qrcode.Highest,
+}
+
+// QR describes the QR code of the public URL of an item
+type QR struct {
+	// Size is the width and height in pixels
+	Size  int
+	Level qrcode.RecoveryLevel
+	SVG   bool
+}
+
+// ParseQR returns the QR code requested by the "size" (pixels, 256 by default), "level"
+// (error correction of l, m, q or h, m by default) and "format" (png or svg, png by default)
+// query strings
+func ParseQR(r *http.Request) (QR, error) {
+	query := r.URL.Query()
+	q := QR{
+		Size:  defaultQRSize,
+		Level: qrcode.Medium,
+	}
+	if v := query.Get("size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < minQRSize || size > maxQRSize {
+			return QR{}, errors.Errorf("qr code size must be between %d and %d pixels", minQRSize, maxQRSize)
+		}
+		q.Size = size
+	}
+	if v := query.Get("level"); v != "" {
+		level, ok := qrLevels[strings.ToLower(v)]
+		if !ok {
+			return QR{}, errors.Errorf("unknown qr code level %s, must be l, m, q or h", v)
+		}
+		q.Level = level
+	}
+	switch format := query.Get("format"); format {
+	case "", qrPNG:
+	case qrSVG:
+		q.SVG = true
+	default:
+		return QR{}, errors.Errorf("unknown qr code format %s, must be png or svg", format)
+	}
+	return q, nil
+}
+
+// ParseSavedQR returns the QR code to include in the response of a save, if requested by
+// the "qr" query string (png or svg). See ParseQR for the other options
+func ParseSavedQR(r *http.Request) (*QR, error) {
+	format := r.URL.Query().Get("qr")
+	if format == "" {
+		return nil, nil
+	}
+	q, err := ParseQR(r)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case qrPNG, "true", "1":
+		q.SVG = false
+	case qrSVG:
+		q.SVG = true
+	default:
+		return nil, errors.Errorf("unknown qr code format %s, must be png or svg", format)
+	}
+	return &q, nil
+}
+
+// ContentType returns the media type of the QR code
+func (q QR) ContentType() string {
+	if q.SVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Encode returns the QR code of the url as PNG or SVG
+func (q QR) Encode(url string) ([]byte, error) {
+	code, err := qrcode.New(url, q.Level)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding qr code")
+	}
+	if !q.SVG {
+		return code.PNG(q.Size)
+	}
+
+	// modules are drawn as a single path, merging consecutive dark modules of each row
+	bitmap := code.Bitmap()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, q.Size, q.Size, len(bitmap), len(bitmap))
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, len(bitmap), len(bitmap))
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes(), nil
+}
+
+// DataURI returns the QR code of the url as a data URI, or an empty string if q is nil
+func (q *QR) DataURI(url string) (string, error) {
+	if q == nil {
+		return "", nil
+	}
+	buf, err := q.Encode(url)
+	if err != nil {
+		return "", err
+	}
+	return "data:" + q.ContentType() + ";base64," + base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// WriteQR responds with the QR code of the url
+func WriteQR(w http.ResponseWriter, r *http.Request, logger *zap.Logger, q QR, url string) {
+	buf, err := q.Encode(url)
+	if err != nil {
+		logger.Error("unable to encode qr code", zap.Error(err), zap.String("url", url))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to generate QR code"))
+		return
+	}
+	w.Header().Set("Content-Type", q.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(buf)))
+	w.Write(buf)
+}
+
+// WithQR adds the QR code requested at save to the saved item. The QR code is optional, so
+// failing to encode it is logged rather than failing the save
+func WithQR(saved Saved, logger *zap.Logger, q *QR) Saved {
+	uri, err := q.DataURI(saved.URL)
+	if err != nil {
+		logger.Warn("unable to encode qr code of saved item", zap.Error(err), zap.String("url", saved.URL))
+		return saved
+	}
+	saved.QR = uri
+	return saved
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQR(t *testing.T) {
+	q, err := ParseQR(httptest.NewRequest("GET", "/l-hello.png", nil))
+	require.NoError(t, err)
+	require.Equal(t, QR{Size: defaultQRSize, Level: qrcode.Medium}, q)
+
+	q, err = ParseQR(httptest.NewRequest("GET", "/l-hello/qr?size=512&level=H&format=svg", nil))
+	require.NoError(t, err)
+	require.Equal(t, QR{Size: 512, Level: qrcode.Highest, SVG: true}, q)
+
+	for _, query := range []string{"size=abc", "size=8", "size=4096", "level=x", "format=gif"} {
+		_, err := ParseQR(httptest.NewRequest("GET", "/l-hello/qr?"+query, nil))
+		require.Error(t, err, query)
+	}
+}
+
+func TestParseSavedQR(t *testing.T) {
+	q, err := ParseSavedQR(httptest.NewRequest("PUT", "/l-hello", nil))
+	require.NoError(t, err)
+	require.Nil(t, q)
+
+	q, err = ParseSavedQR(httptest.NewRequest("PUT", "/l-hello?qr=svg&size=128", nil))
+	require.NoError(t, err)
+	require.Equal(t, &QR{Size: 128, Level: qrcode.Medium, SVG: true}, q)
+
+	_, err = ParseSavedQR(httptest.NewRequest("PUT", "/l-hello?qr=gif", nil))
+	require.Error(t, err)
+}
+
+func TestEncodeQR(t *testing.T) {
+	const url = "http://hello/l-hello"
+
+	t.Run("png", func(t *testing.T) {
+		q := QR{Size: 128, Level: qrcode.Medium}
+		buf, err := q.Encode(url)
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(buf))
+		require.NoError(t, err)
+		require.Equal(t, 128, img.Bounds().Dx())
+		require.Equal(t, 128, img.Bounds().Dy())
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		q := QR{Size: 128, Level: qrcode.Medium, SVG: true}
+		buf, err := q.Encode(url)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(buf), `<svg xmlns="http://www.w3.org/2000/svg" width="128" height="128"`))
+		require.True(t, strings.HasSuffix(string(buf), `"/></svg>`))
+	})
+
+	t.Run("data uri", func(t *testing.T) {
+		var none *QR
+		uri, err := none.DataURI(url)
+		require.NoError(t, err)
+		require.Empty(t, uri)
+
+		q := &QR{Size: 128, Level: qrcode.Medium}
+		uri, err = q.DataURI(url)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(uri, "data:image/png;base64,"))
+
+		buf, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, "data:image/png;base64,"))
+		require.NoError(t, err)
+		_, err = png.Decode(bytes.NewReader(buf))
+		require.NoError(t, err)
+	})
+}
@@ -15,7 +15,7 @@ func Recovery(logger *zap.Logger) func(next http.Handler) http.Handler {
 			defer func() {
 				err := recover()
 				if err != nil && err != http.ErrAbortHandler {
-					logger.Error("Handler panic",
+					Logger(r, logger).Error("Handler panic",
 						zap.Any("Exception", err),
 					)
 					response.WriteError(w, r, response.ErrUnexpected().AddMessages("Server has encountered an unrecoverable error"))
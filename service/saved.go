@@ -0,0 +1,38 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/zllovesuki/b/response"
+)
+
+// Saved is the result of saving or extending an item
+type Saved struct {
+	URL string `json:"url"`
+	// Expires is omitted if the item never expires
+	Expires *time.Time `json:"expires,omitempty"`
+	// QR is the QR code of the URL as a data URI, if requested at save
+	QR string `json:"qr,omitempty"`
+}
+
+// NewSaved returns the result for the item identified by route, expiring ttl from now
+func NewSaved(baseURL, prefix, route string, ttl time.Duration) Saved {
+	saved := Saved{
+		URL: Ret(baseURL, prefix, route),
+	}
+	if ttl > 0 {
+		expires := time.Now().UTC().Add(ttl).Truncate(time.Second)
+		saved.Expires = &expires
+	}
+	return saved
+}
+
+// WriteSaved responds with the saved item as the result, also setting its expiration in
+// X-B-Expires if it expires
+func WriteSaved(w http.ResponseWriter, r *http.Request, saved Saved) {
+	if saved.Expires != nil {
+		w.Header().Set(HeaderExpires, saved.Expires.Format(time.RFC3339))
+	}
+	response.WriteResponse(w, r, saved)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSaved(t *testing.T) {
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+		var ret struct {
+			Result map[string]interface{}
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&ret))
+		return ret.Result
+	}
+
+	t.Run("result has the url and expiration", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		saved := NewSaved("http://hello", "l-", "world", time.Hour)
+		WriteSaved(w, httptest.NewRequest("PUT", "/l-world", nil), saved)
+
+		require.Equal(t, saved.Expires.Format(time.RFC3339), w.Header().Get(HeaderExpires))
+		ret := decode(t, w)
+		require.Equal(t, "http://hello/l-world", ret["url"])
+		require.Equal(t, saved.Expires.Format(time.RFC3339), ret["expires"])
+		require.NotContains(t, ret, "qr")
+	})
+
+	t.Run("permanent items have no expiration", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		WriteSaved(w, httptest.NewRequest("PUT", "/l-world", nil), NewSaved("http://hello", "l-", "world", 0))
+
+		require.Empty(t, w.Header().Get(HeaderExpires))
+		ret := decode(t, w)
+		require.Equal(t, "http://hello/l-world", ret["url"])
+		require.NotContains(t, ret, "expires")
+	})
+
+	t.Run("qr code is added to the result", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		saved := NewSaved("http://hello", "l-", "world", 0)
+		saved.QR = "data:image/png;base64,"
+		WriteSaved(w, httptest.NewRequest("PUT", "/l-world?qr=png", nil), saved)
+
+		var ret struct {
+			Result Saved
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&ret))
+		require.Equal(t, saved, ret.Result)
+	})
+}
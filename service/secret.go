@@ -0,0 +1,35 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// HeaderSecret carries the secret returned at save time. The same secret is
+// required to modify the saved item afterward
+const HeaderSecret = "X-B-Secret"
+
+// NewSecret returns a random secret to be handed to the client, and its digest to be persisted
+func NewSecret() (string, []byte, error) {
+	buf := make([]byte, 24)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", nil, errors.Wrap(err, "generating secret")
+	}
+	secret := base64.RawURLEncoding.EncodeToString(buf)
+	digest := sha256.Sum256([]byte(secret))
+	return secret, digest[:], nil
+}
+
+// VerifySecret reports whether the secret matches the persisted digest
+func VerifySecret(secret string, digest []byte) bool {
+	if secret == "" {
+		return false
+	}
+	computed := sha256.Sum256([]byte(secret))
+	return subtle.ConstantTimeCompare(computed[:], digest) == 1
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	// longer suffixes first so KiB is not matched as B
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"TiB", 1 << 40},
+	{"KB", 1e3},
+	{"MB", 1e6},
+	{"GB", 1e9},
+	{"TB", 1e12},
+	{"B", 1},
+}
+
+// ParseSize parses a size in bytes with an optional unit, e.g. 1024, 512KiB, 10MB or 1GiB
+func ParseSize(v string) (int64, error) {
+	v = strings.TrimSpace(v)
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(v, unit.suffix) {
+			v = strings.TrimSpace(strings.TrimSuffix(v, unit.suffix))
+			multiplier = unit.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, errors.Errorf("invalid size: %s", v)
+	}
+	if n > (1<<63-1)/multiplier {
+		return 0, errors.Errorf("size too large: %s", v)
+	}
+	return n * multiplier, nil
+}
+
+// LimitBody caps the request body at max bytes. No limit is applied if max is 0
+func LimitBody(w http.ResponseWriter, r *http.Request, max int64) {
+	if max > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, max)
+	}
+}
+
+// TooLarge reports whether err was caused by reading beyond the limit set by LimitBody
+func TooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSize(t *testing.T) {
+	valid := map[string]int64{
+		"0":       0,
+		"1024":    1024,
+		"512B":    512,
+		"3KiB":    3 << 10,
+		"10 MiB":  10 << 20,
+		"10MB":    10e6,
+		"1GiB":    1 << 30,
+		"2TB":     2e12,
+		" 4KB   ": 4e3,
+	}
+	for v, expected := range valid {
+		size, err := ParseSize(v)
+		require.NoError(t, err, v)
+		require.Equal(t, expected, size, v)
+	}
+
+	for _, v := range []string{"", "abc", "-1", "1.5GiB", "10XB", "9999999TiB"} {
+		_, err := ParseSize(v)
+		require.Error(t, err, v)
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	r := httptest.NewRequest("PUT", "/t-hello", bytes.NewReader(make([]byte, 1024)))
+	LimitBody(httptest.NewRecorder(), r, 512)
+
+	_, err := io.Copy(io.Discard, r.Body)
+	require.True(t, TooLarge(errors.Wrap(err, "saving")))
+
+	r = httptest.NewRequest("PUT", "/t-hello", bytes.NewReader(make([]byte, 1024)))
+	LimitBody(httptest.NewRecorder(), r, 0)
+
+	n, err := io.Copy(io.Discard, r.Body)
+	require.NoError(t, err)
+	require.Equal(t, int64(1024), n)
+	require.False(t, TooLarge(http.ErrBodyNotAllowed))
+}
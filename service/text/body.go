@@ -0,0 +1,91 @@
+package text
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/zllovesuki/b/service"
+
+	"github.com/pkg/errors"
+)
+
+// textField is the form field of multipart/form-data saves holding the paste
+const textField = "text"
+
+// SaveTextReq is the body of application/json saves
+type SaveTextReq struct {
+	Content  *string `json:"content"`
+	TTL      string  `json:"ttl"`
+	Language string  `json:"language"`
+}
+
+// textBody is the paste in the save request, along with the ttl and language given in the body
+type textBody struct {
+	io.ReadCloser
+	TTL      string
+	Language string
+}
+
+var (
+	errUnsupportedMediaType = errors.New("unsupported media type")
+	errInvalidJSON          = errors.New("invalid json")
+	errMissingContent       = errors.New("missing content")
+)
+
+// readText returns the paste in the request body. Raw bodies (text/*, or curl's default
+// application/x-www-form-urlencoded) are streamed as is, JSON bodies are decoded as SaveTextReq,
+// and multipart/form-data bodies are streamed from the text field
+func readText(r *http.Request) (textBody, error) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return textBody{ReadCloser: r.Body}, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return textBody{}, errUnsupportedMediaType
+	}
+
+	switch {
+	case mediaType == "application/x-www-form-urlencoded", strings.HasPrefix(mediaType, "text/"):
+		return textBody{ReadCloser: r.Body}, nil
+
+	case mediaType == "application/json":
+		var req SaveTextReq
+		if err := json.NewDecoder(r.Body).Decode(&req); service.TooLarge(err) {
+			return textBody{}, err
+		} else if err != nil {
+			return textBody{}, errInvalidJSON
+		}
+		if req.Content == nil {
+			return textBody{}, errMissingContent
+		}
+		return textBody{
+			ReadCloser: io.NopCloser(strings.NewReader(*req.Content)),
+			TTL:        req.TTL,
+			Language:   req.Language,
+		}, nil
+
+	case mediaType == "multipart/form-data":
+		reader, err := r.MultipartReader()
+		if err != nil {
+			return textBody{}, err
+		}
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				return textBody{}, errMissingContent
+			} else if err != nil {
+				return textBody{}, err
+			}
+			if part.FormName() == textField {
+				return textBody{ReadCloser: part}, nil
+			}
+		}
+
+	default:
+		return textBody{}, errUnsupportedMediaType
+	}
+}
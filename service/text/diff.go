@@ -0,0 +1,330 @@
+package text
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultDiffSize caps the size of each side of a diff, as diffing buffers both pastes
+	defaultDiffSize = 256 << 10
+	// maxDiffLines caps the lines of each side of a diff
+	maxDiffLines = 5000
+	// diffBudget bounds the steps spent finding the changes, which grows with the number
+	// of lines times the number of changes
+	diffBudget = 1 << 24
+	// diffContext is the number of unchanged lines around changes
+	diffContext = 3
+
+	splitView = "split"
+)
+
+var errTooLargeToDiff = errors.New("too large to diff")
+
+// diffSide matches a side of a diff, either a paste or one of its revisions, e.g. hello@2
+var diffSide = regexp.MustCompile(`^([a-zA-Z0-9]+)(?:@([0-9]+))?$`)
+
+var diffPage = template.Must(template.New("diff").Parse(`
+{{- define "unified" -}}
+<table class="chroma diff">
+    {{- range . }}
+    {{- if .Hunk }}
+    <tr class="hunk"><td colspan="4">{{ .Hunk }}</td></tr>
+    {{- else }}
+    <tr{{ with .Class }} class="{{ . }}"{{ end }}><td class="num">{{ if .Old }}{{ .Old }}{{ end }}</td><td class="num">{{ if .New }}{{ .New }}{{ end }}</td><td class="sign">{{ .Sign }}</td><td>{{ .Code }}</td></tr>
+    {{- end }}
+    {{- else }}
+    <tr class="hunk"><td colspan="4">No differences</td></tr>
+    {{- end }}
+</table>
+{{- end -}}
+{{- define "split" -}}
+<table class="chroma diff">
+    {{- range . }}
+    {{- if .Hunk }}
+    <tr class="hunk"><td colspan="4">{{ .Hunk }}</td></tr>
+    {{- else }}
+    <tr>
+        {{- with .Left }}<td class="num{{ with .Class }} {{ . }}{{ end }}">{{ if .Number }}{{ .Number }}{{ end }}</td><td{{ with .Class }} class="{{ . }}"{{ end }}>{{ .Code }}</td>{{ end }}
+        {{- with .Right }}<td class="num{{ with .Class }} {{ . }}{{ end }}">{{ if .Number }}{{ .Number }}{{ end }}</td><td{{ with .Class }} class="{{ . }}"{{ end }}>{{ .Code }}</td>{{ end -}}
+    </tr>
+    {{- end }}
+    {{- else }}
+    <tr class="hunk"><td colspan="4">No differences</td></tr>
+    {{- end }}
+</table>
+{{- end -}}
+`))
+
+// unifiedRow is a line of the unified view, or the header of a hunk
+type unifiedRow struct {
+	Hunk  string
+	Class string
+	Old   int
+	New   int
+	Sign  string
+	Code  template.HTML
+}
+
+// diffCell is a side of a line of the split view. Number is 0 when the side has no line
+type diffCell struct {
+	Class  string
+	Number int
+	Code   template.HTML
+}
+
+// splitRow is a line of the split view, or the header of a hunk
+type splitRow struct {
+	Hunk  string
+	Left  diffCell
+	Right diffCell
+}
+
+// inline formats a single line of tokens, as lines are laid out by the diff views
+var inline = html.New(
+	html.WithClasses(true),
+	html.PreventSurroundingPre(true),
+)
+
+// noNewline marks the last line of a paste which does not end in a newline, as in unified diffs
+const noNewline = "\\ No newline at end of file"
+
+// splitLines splits the paste into lines terminated by newlines, without the empty line
+// following the last newline. The last line is kept as is, so a paste differing only by
+// its trailing newline also differs by its last line
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	}
+	return lines
+}
+
+// markNoNewline appends the marker to the highlighted last line if it does not end in a newline
+func markNoNewline(lines []string, out []template.HTML) {
+	if last := len(lines) - 1; last >= 0 && !strings.HasSuffix(lines[last], "\n") {
+		out[last] += template.HTML(` <span class="noeol">` + template.HTMLEscapeString(noNewline) + `</span>`)
+	}
+}
+
+// highlightLines highlights the paste and returns each of its lines as HTML
+func highlightLines(lexer chroma.Lexer, style *chroma.Style, text string, n int) ([]template.HTML, error) {
+	tokens, err := chroma.Tokenise(chroma.Coalesce(lexer), nil, text)
+	if err != nil {
+		return nil, errors.Wrap(err, "tokenising text paste")
+	}
+	out := make([]template.HTML, n)
+	var buf bytes.Buffer
+	for i, line := range chroma.SplitTokensIntoLines(tokens) {
+		if i >= n {
+			break
+		}
+		// rows lay out the lines, so the newline ending each line is omitted
+		if last := len(line) - 1; last >= 0 {
+			line[last].Value = strings.TrimSuffix(line[last].Value, "\n")
+		}
+		buf.Reset()
+		if err := inline.Format(&buf, style, chroma.Literator(line...)); err != nil {
+			return nil, errors.Wrap(err, "formatting text paste")
+		}
+		out[i] = template.HTML(buf.String())
+	}
+	return out, nil
+}
+
+// formatRange formats the range of lines of a hunk as in unified diffs
+func formatRange(start, stop int) string {
+	switch n := stop - start; n {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, n)
+	}
+}
+
+func hunkHeader(group []opCode) string {
+	first, last := group[0], group[len(group)-1]
+	return fmt.Sprintf("@@ -%s +%s @@", formatRange(first.I1, last.I2), formatRange(first.J1, last.J2))
+}
+
+func unifiedRows(groups [][]opCode, a, b []template.HTML) []unifiedRow {
+	var rows []unifiedRow
+	for _, group := range groups {
+		rows = append(rows, unifiedRow{Hunk: hunkHeader(group)})
+		for _, c := range group {
+			if c.Tag == 'e' {
+				for i := 0; i < c.I2-c.I1; i++ {
+					rows = append(rows, unifiedRow{Old: c.I1 + i + 1, New: c.J1 + i + 1, Sign: " ", Code: b[c.J1+i]})
+				}
+				continue
+			}
+			for i := c.I1; i < c.I2; i++ {
+				rows = append(rows, unifiedRow{Class: "del", Old: i + 1, Sign: "-", Code: a[i]})
+			}
+			for j := c.J1; j < c.J2; j++ {
+				rows = append(rows, unifiedRow{Class: "ins", New: j + 1, Sign: "+", Code: b[j]})
+			}
+		}
+	}
+	return rows
+}
+
+func splitRows(groups [][]opCode, a, b []template.HTML) []splitRow {
+	var rows []splitRow
+	for _, group := range groups {
+		rows = append(rows, splitRow{Hunk: hunkHeader(group)})
+		for _, c := range group {
+			n := c.I2 - c.I1
+			if c.J2-c.J1 > n {
+				n = c.J2 - c.J1
+			}
+			for k := 0; k < n; k++ {
+				var row splitRow
+				if i := c.I1 + k; i < c.I2 {
+					row.Left = diffCell{Number: i + 1, Code: a[i]}
+					if c.Tag != 'e' {
+						row.Left.Class = "del"
+					}
+				}
+				if j := c.J1 + k; j < c.J2 {
+					row.Right = diffCell{Number: j + 1, Code: b[j]}
+					if c.Tag != 'e' {
+						row.Right.Class = "ins"
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
+// readSide returns the paste or revision named by the side of the diff
+func (s *Service) readSide(r *http.Request, side string) (string, string, error) {
+	m := diffSide.FindStringSubmatch(side)
+	if m == nil {
+		return "", "", app.ErrNotFound
+	}
+	key, err := s.resolveRevision(r.Context(), m[1], m[2])
+	if err != nil {
+		return "", "", err
+	}
+	text, err := s.Backend.Retrieve(r.Context(), key)
+	if err != nil {
+		return "", "", err
+	}
+	defer text.Close()
+
+	buf, err := ioutil.ReadAll(io.LimitReader(text, s.DiffSize+1))
+	if err != nil {
+		return "", "", err
+	}
+	if int64(len(buf)) > s.DiffSize {
+		return "", "", errTooLargeToDiff
+	}
+	return m[1], string(buf), nil
+}
+
+func (s *Service) diffText(w http.ResponseWriter, r *http.Request) {
+	s.diff(w, r, chi.URLParam(r, "a"), chi.URLParam(r, "b"), chi.URLParam(r, "ext") == autoExt)
+}
+
+// diff renders the changes from paste a to paste b, either of which may be a revision. The raw
+// diff is unified, while the HTML view is unified or side by side with ?view=split
+func (s *Service) diff(w http.ResponseWriter, r *http.Request, a, b string, rendered bool) {
+	logger := s.logger(r).With(zap.String("from", a), zap.String("to", b))
+
+	var ids, texts [2]string
+	for i, side := range []string{a, b} {
+		id, text, err := s.readSide(r, side)
+		if errors.Is(err, app.ErrNotFound) {
+			response.WriteError(w, r, response.ErrNotFound().AddMessages(fmt.Sprintf("Text paste %s either expired or not found", side)))
+			return
+		} else if errors.Is(err, errTooLargeToDiff) {
+			response.WriteError(w, r, response.ErrPayloadTooLarge().AddMessages(fmt.Sprintf("Text pastes larger than %d bytes cannot be diffed", s.DiffSize)))
+			return
+		} else if err != nil {
+			logger.Error("unable to retrieve from backend", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste"))
+			return
+		}
+		ids[i], texts[i] = id, text
+	}
+	from, to := splitLines(texts[0]), splitLines(texts[1])
+	if len(from) > maxDiffLines || len(to) > maxDiffLines {
+		response.WriteError(w, r, response.ErrPayloadTooLarge().AddMessages(fmt.Sprintf("Text pastes longer than %d lines cannot be diffed", maxDiffLines)))
+		return
+	}
+	codes, err := diffLines(from, to, diffBudget)
+	if errors.Is(err, errTooLargeToDiff) {
+		response.WriteError(w, r, response.ErrPayloadTooLarge().AddMessages("Text pastes are too different to be diffed"))
+		return
+	} else if err != nil {
+		logger.Error("unable to diff text pastes", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to diff text pastes"))
+		return
+	}
+	groups := groupOpCodes(codes, diffContext)
+
+	if !rendered {
+		w.Header().Set("Content-Type", "text/x-diff; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if err := writeUnified(w, prefix+a, prefix+b, from, to, groups); err != nil {
+			logger.Warn("writing diff", zap.Error(err))
+		}
+		return
+	}
+
+	meta := s.lookupMetadata(r, ids[1])
+	style := s.style(r)
+	lex := lexer(r, "", meta.Language, texts[1])
+	fromHTML, err := highlightLines(lex, style, texts[0], len(from))
+	if err != nil {
+		logger.Error("unable to highlight text paste", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to render diff"))
+		return
+	}
+	toHTML, err := highlightLines(lex, style, texts[1], len(to))
+	if err != nil {
+		logger.Error("unable to highlight text paste", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to render diff"))
+		return
+	}
+	markNoNewline(from, fromHTML)
+	markNoNewline(to, toHTML)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.writeHead(w, a+".."+b, Metadata{}, style); err != nil {
+		logger.Error("unable to render page", zap.Error(err))
+		return
+	}
+	defer page.ExecuteTemplate(w, "foot", nil)
+
+	if r.URL.Query().Get("view") == splitView {
+		err = diffPage.ExecuteTemplate(w, "split", splitRows(groups, fromHTML, toHTML))
+	} else {
+		err = diffPage.ExecuteTemplate(w, "unified", unifiedRows(groups, fromHTML, toHTML))
+	}
+	if err != nil {
+		logger.Warn("rendering diff", zap.Error(err))
+	}
+}
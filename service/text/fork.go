@@ -0,0 +1,88 @@
+package text
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var validID = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+// forkText copies the paste, or one of its revisions, to a new paste given by ?id= or generated
+// otherwise. The fork keeps the metadata of the paste unless given on the request as any other
+// paste, along with its ttl and author, and records the paste as its parent
+func (s *Service) forkText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parent := id
+	if rev := chi.URLParam(r, "rev"); rev != "" {
+		parent = id + "@" + rev
+	}
+
+	forkID := r.URL.Query().Get("id")
+	if forkID == "" {
+		var err error
+		forkID, err = service.NewID()
+		if err != nil {
+			s.logger(r).Error("unable to generate identifier", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to fork text paste"))
+			return
+		}
+	} else if !validID.MatchString(forkID) {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages("Identifier must be alphanumeric"))
+		return
+	}
+
+	ttl, err := s.TTL.Parse(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	// the fork is authored as any other paste, rather than by the author of the parent
+	given, err := parseMetadata(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	// pastes saved without metadata are forked without it
+	meta, err := s.metadata(r.Context(), id)
+	if err != nil && !errors.Is(err, app.ErrNotFound) {
+		s.logger(r).Error("unable to retrieve text metadata", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to fork text paste"))
+		return
+	}
+	meta.Version = 1
+	meta.Parent = parent
+	meta.Author = given.Author
+	if given.Title != "" {
+		meta.Title = given.Title
+	}
+	if given.Language != "" {
+		meta.Language = given.Language
+	}
+	if given.ContentType != "" {
+		meta.ContentType = given.ContentType
+	}
+
+	text, err := s.openRevision(r, id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to fork text paste"))
+		return
+	}
+	defer text.Close()
+
+	// the paste is streamed from the backend to the fork
+	s.create(w, r, forkID, meta, text, ttl)
+}
@@ -0,0 +1,309 @@
+package text
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// langPrefix keys the hint stored alongside the paste when no metadata backend is configured,
+	// holding its language and, for forks, its parent on a second line
+	langPrefix = "tl-"
+	// maxHintLength caps the hint read back, as it is stored along with the paste
+	maxHintLength = 256
+	// autoExt renders the paste with the language given by ?lang=, the stored hint or detection
+	autoExt = "html"
+
+	defaultTheme = "nord"
+	// defaultHighlightSize caps the size of pastes highlighted, as highlighting buffers the paste
+	defaultHighlightSize = 1 << 20
+)
+
+var page = template.Must(template.New("page").Parse(`
+{{- define "head" -}}
+<!DOCTYPE html>
+<html lang="en">
+    <head>
+        <meta charset="UTF-8" />
+        <meta name="viewport" content="width=device-width, initial-scale=1" />
+        <title>{{ .Title }}</title>
+        <style>
+            html,
+            body {
+                margin: 0;
+                padding: 0;
+                background-color: {{ .Background }};
+                color: {{ .Color }};
+            }
+            .meta {
+                padding: 0.5em 1em;
+                font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif;
+                border-bottom: 1px solid;
+            }
+            .meta a {
+                color: inherit;
+            }
+            .markdown {
+                max-width: 60em;
+                margin: 0 auto;
+                padding: 1em 2em;
+                font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif;
+                line-height: 1.5;
+            }
+            .markdown a {
+                color: inherit;
+            }
+            .markdown .anchor {
+                margin-left: -1em;
+                padding-right: 0.25em;
+                text-decoration: none;
+                visibility: hidden;
+            }
+            .markdown h1:hover .anchor,
+            .markdown h2:hover .anchor,
+            .markdown h3:hover .anchor,
+            .markdown h4:hover .anchor,
+            .markdown h5:hover .anchor,
+            .markdown h6:hover .anchor {
+                visibility: visible;
+            }
+            .markdown table {
+                border-collapse: collapse;
+            }
+            .markdown th,
+            .markdown td {
+                padding: 0.25em 0.75em;
+                border: 1px solid;
+            }
+            .markdown img {
+                max-width: 100%;
+            }
+            .chroma {
+                margin: 0;
+                padding: 0.5em;
+                font-family: 'Cascadia Code', SFMono-Regular, Consolas, Liberation Mono, Menlo, monospace;
+            }
+            .chroma .lnlinks {
+                color: inherit;
+                text-decoration: none;
+            }
+            .diff {
+                width: 100%;
+                border-collapse: collapse;
+                table-layout: fixed;
+            }
+            .diff td {
+                height: 1.25em;
+                padding: 0 0.5em;
+                vertical-align: top;
+                white-space: pre-wrap;
+                overflow-wrap: anywhere;
+            }
+            .diff .num {
+                width: 3em;
+                text-align: right;
+                opacity: 0.5;
+                user-select: none;
+            }
+            .diff .sign {
+                width: 1em;
+                user-select: none;
+            }
+            .diff .hunk td {
+                padding: 0.25em 0.5em;
+                opacity: 0.6;
+            }
+            .diff .del {
+                background-color: rgba(248, 81, 73, 0.2);
+            }
+            .diff .ins {
+                background-color: rgba(46, 160, 67, 0.2);
+            }
+            .diff .noeol {
+                opacity: 0.5;
+                user-select: none;
+            }
+            {{ .CSS }}
+        </style>
+    </head>
+    <body>
+        {{- if or .Heading .Author .Parent }}
+        <header class="meta">
+            {{- if .Heading }}<strong>{{ .Heading }}</strong>{{ end }}
+            {{- if .Author }} by {{ .Author }}{{ end }}
+            {{- if .Parent }} forked from <a href="{{ .ParentURL }}">{{ .Parent }}</a>{{ end -}}
+        </header>
+        {{- end }}
+{{ end -}}
+{{- define "foot" }}
+    </body>
+</html>
+{{ end -}}
+`))
+
+type pageData struct {
+	Title      string
+	Heading    string
+	Author     string
+	Parent     string
+	ParentURL  string
+	Background template.CSS
+	Color      template.CSS
+	CSS        template.CSS
+}
+
+var formatter = html.New(
+	html.WithClasses(true),
+	html.WithLineNumbers(true),
+	html.WithLinkableLineNumbers(true, "L"),
+	html.TabWidth(4),
+)
+
+// lookupLexer returns the lexer matching the name, alias or file extension, or nil if unknown
+func lookupLexer(name string) chroma.Lexer {
+	if name == "" {
+		return nil
+	}
+	return lexers.Get(name)
+}
+
+// lexer returns the lexer named by ?lang=, the extension or the language stored at save,
+// otherwise the lexer detected from the content
+func lexer(r *http.Request, ext, lang, content string) chroma.Lexer {
+	if ext == autoExt {
+		ext = ""
+	}
+	for _, name := range []string{r.URL.Query().Get("lang"), ext, lang} {
+		if l := lookupLexer(name); l != nil {
+			return l
+		}
+	}
+	if l := lexers.Analyse(content); l != nil {
+		return l
+	}
+	return lexers.Fallback
+}
+
+// hintOf returns the hint of the paste, which pastes saved before forks recorded parents lack
+func hintOf(meta Metadata) string {
+	if meta.Parent == "" {
+		return meta.Language
+	}
+	return meta.Language + "\n" + meta.Parent
+}
+
+// hint returns the language and parent stored alongside the paste when no metadata backend
+// is configured
+func (s *Service) hint(c context.Context, id string) (Metadata, error) {
+	hint, err := s.Backend.Retrieve(c, langPrefix+id)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer hint.Close()
+
+	buf, err := ioutil.ReadAll(io.LimitReader(hint, maxHintLength))
+	if err != nil {
+		return Metadata{}, err
+	}
+	lang, parent, _ := strings.Cut(string(buf), "\n")
+	return Metadata{Language: lang, Parent: parent}, nil
+}
+
+// style returns the theme named by ?theme=, or the default theme
+func (s *Service) style(r *http.Request) *chroma.Style {
+	if theme, ok := styles.Registry[r.URL.Query().Get("theme")]; ok {
+		return theme
+	}
+	return styles.Get(s.Theme)
+}
+
+func (s *Service) writeHead(w io.Writer, id string, meta Metadata, style *chroma.Style) error {
+	var css bytes.Buffer
+	if err := formatter.WriteCSS(&css, style); err != nil {
+		return errors.Wrap(err, "writing css of theme")
+	}
+	title := service.Prefix(prefix, id)
+	if meta.Title != "" {
+		title = meta.Title
+	}
+	color := "inherit"
+	if c := style.Get(chroma.Text).Colour; c.IsSet() {
+		color = c.String()
+	}
+	data := pageData{
+		Title:      title,
+		Heading:    meta.Title,
+		Author:     meta.Author,
+		Background: template.CSS(style.Get(chroma.Background).Background.String()),
+		Color:      template.CSS(color),
+		CSS:        template.CSS(css.String()),
+	}
+	if meta.Parent != "" {
+		data.Parent = prefix + meta.Parent
+		data.ParentURL = service.Ret(s.BaseURL, prefix, meta.Parent+"."+autoExt)
+	}
+	return page.ExecuteTemplate(w, "head", data)
+}
+
+// highlight renders the paste as HTML with line numbers. Pastes larger than HighlightSize
+// are streamed escaped without highlighting
+func (s *Service) highlight(w http.ResponseWriter, r *http.Request, id, ext string, text io.Reader) {
+	logger := s.logger(r).With(zap.String("id", id))
+
+	buf, err := ioutil.ReadAll(io.LimitReader(text, s.HighlightSize+1))
+	if err != nil {
+		logger.Error("unable to read text paste", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste"))
+		return
+	}
+
+	meta := s.lookupMetadata(r, id)
+	style := s.style(r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.writeHead(w, id, meta, style); err != nil {
+		logger.Error("unable to render page", zap.Error(err))
+		return
+	}
+	defer page.ExecuteTemplate(w, "foot", nil)
+
+	if int64(len(buf)) > s.HighlightSize {
+		writeEscaped(w, logger, buf, text)
+		return
+	}
+
+	iterator, err := chroma.Coalesce(lexer(r, ext, meta.Language, string(buf))).Tokenise(nil, string(buf))
+	if err != nil {
+		logger.Error("unable to tokenise text paste", zap.Error(err))
+		return
+	}
+	if err := formatter.Format(w, style, iterator); err != nil {
+		logger.Warn("formatting text paste", zap.Error(err))
+	}
+}
+
+// writeEscaped streams the buffered head of the paste and the rest of it escaped
+// without highlighting
+func writeEscaped(w io.Writer, logger *zap.Logger, buf []byte, text io.Reader) {
+	io.WriteString(w, `<pre class="chroma">`)
+	escaped := service.NewHTMLEscapeWriter(w)
+	escaped.Write(buf)
+	if n, err := io.Copy(escaped, text); err != nil {
+		logger.Warn("piping text buffer", zap.Error(err), zap.Int64("bytes-written", n))
+	}
+	io.WriteString(w, `</pre>`)
+}
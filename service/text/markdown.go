@@ -0,0 +1,118 @@
+package text
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+
+	"github.com/zllovesuki/b/response"
+
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"go.uber.org/zap"
+)
+
+const (
+	markdownExt = "md"
+	// defaultMarkdownSize caps the size of pastes rendered as markdown, as rendering buffers the paste
+	defaultMarkdownSize = 1 << 20
+)
+
+// gfm renders GitHub-flavored markdown. Raw HTML in the paste is omitted, and fenced code
+// blocks are highlighted with classes, so the theme CSS of the page applies
+var gfm = goldmark.New(
+	goldmark.WithExtensions(
+		extension.Linkify,
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.TaskList,
+		highlighting.NewHighlighting(
+			highlighting.WithFormatOptions(html.WithClasses(true), html.TabWidth(4)),
+		),
+	),
+	goldmark.WithParserOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(util.Prioritized(headingAnchors{}, 100)),
+	),
+)
+
+// sanitizer is the last line of defense against the rendered markdown, should the
+// renderer let anything unsafe through
+var sanitizer = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-z ]+$`)).OnElements("pre", "code", "span", "a")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").Matching(regexp.MustCompile(`^$`)).OnElements("input")
+	return p
+}()
+
+// headingAnchors prepends a link to the heading itself to every heading with an id
+type headingAnchors struct{}
+
+func (headingAnchors) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		id, ok := heading.AttributeString("id")
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+		anchor := ast.NewLink()
+		anchor.Destination = append([]byte("#"), id.([]byte)...)
+		anchor.SetAttributeString("class", []byte("anchor"))
+		anchor.AppendChild(anchor, ast.NewString([]byte("#")))
+		heading.InsertBefore(heading, heading.FirstChild(), anchor)
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// markdown renders the paste as sanitized GitHub-flavored markdown. Pastes larger than
+// MarkdownSize are streamed escaped as plain text
+func (s *Service) markdown(w http.ResponseWriter, r *http.Request, id string, text io.Reader) {
+	logger := s.logger(r).With(zap.String("id", id))
+
+	buf, err := ioutil.ReadAll(io.LimitReader(text, s.MarkdownSize+1))
+	if err != nil {
+		logger.Error("unable to read text paste", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste"))
+		return
+	}
+
+	var rendered bytes.Buffer
+	if int64(len(buf)) <= s.MarkdownSize {
+		if err := gfm.Convert(buf, &rendered); err != nil {
+			logger.Error("unable to render text paste", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to render text paste"))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.writeHead(w, id, s.lookupMetadata(r, id), s.style(r)); err != nil {
+		logger.Error("unable to render page", zap.Error(err))
+		return
+	}
+	defer page.ExecuteTemplate(w, "foot", nil)
+
+	if int64(len(buf)) > s.MarkdownSize {
+		writeEscaped(w, logger, buf, text)
+		return
+	}
+
+	io.WriteString(w, `<article class="markdown">`)
+	if err := sanitizer.SanitizeReaderToWriter(&rendered, w); err != nil {
+		logger.Warn("writing rendered text paste", zap.Error(err))
+	}
+	io.WriteString(w, `</article>`)
+}
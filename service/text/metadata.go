@@ -0,0 +1,194 @@
+package text
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const metaPrefix = "tm-"
+
+// headers setting the metadata of pastes on save, alternatively to the query parameters
+// title, lang, author and content_type
+const (
+	HeaderTitle       = "X-B-Title"
+	HeaderLanguage    = "X-B-Language"
+	HeaderAuthor      = "X-B-Author"
+	HeaderContentType = "X-B-Content-Type"
+)
+
+const (
+	maxTitleLength  = 200
+	maxAuthorLength = 64
+)
+
+const defaultContentType = "text/plain; charset=utf-8"
+
+// unsafeContentTypes are rendered or executed by browsers, and cannot be set on raw pastes
+var unsafeContentTypes = map[string]bool{
+	"text/html":       true,
+	"text/xml":        true,
+	"text/xsl":        true,
+	"text/javascript": true,
+	"text/ecmascript": true,
+}
+
+type Metadata struct {
+	Version     int64  `json:"version"`
+	Title       string `json:"title,omitempty"`
+	Language    string `json:"language,omitempty"`
+	Author      string `json:"author,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	// Parent is the paste, or revision, the paste was forked from
+	Parent string `json:"parent,omitempty"`
+}
+
+// metadataParam returns the query parameter, or the header if the query parameter is omitted
+func metadataParam(r *http.Request, query, header string) string {
+	if v := r.URL.Query().Get(query); v != "" {
+		return v
+	}
+	return r.Header.Get(header)
+}
+
+func validText(field, v string, max int) error {
+	if !utf8.ValidString(v) {
+		return errors.Errorf("%s must be valid UTF-8", field)
+	}
+	if utf8.RuneCountInString(v) > max {
+		return errors.Errorf("%s cannot be longer than %d characters", field, max)
+	}
+	if strings.IndexFunc(v, unicode.IsControl) >= 0 {
+		return errors.Errorf("%s cannot contain control characters", field)
+	}
+	return nil
+}
+
+// parseContentType normalizes the content type of raw pastes. Only text types which browsers
+// display as is are accepted, and charset defaults to utf-8
+func parseContentType(v string) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		return "", errors.Errorf("invalid content type %s", v)
+	}
+	if !(strings.HasPrefix(mediaType, "text/") || mediaType == "application/json") || unsafeContentTypes[mediaType] {
+		return "", errors.Errorf("content type %s is not allowed", mediaType)
+	}
+	if _, ok := params["charset"]; !ok {
+		params["charset"] = "utf-8"
+	}
+	return mime.FormatMediaType(mediaType, params), nil
+}
+
+// parseMetadata returns the metadata given on save. Author defaults to the authenticated user
+func parseMetadata(r *http.Request) (Metadata, error) {
+	meta := Metadata{
+		Version: 1,
+		Title:   strings.TrimSpace(metadataParam(r, "title", HeaderTitle)),
+		Author:  strings.TrimSpace(metadataParam(r, "author", HeaderAuthor)),
+	}
+	if err := validText("title", meta.Title, maxTitleLength); err != nil {
+		return Metadata{}, err
+	}
+	if err := validText("author", meta.Author, maxAuthorLength); err != nil {
+		return Metadata{}, err
+	}
+	if meta.Author == "" {
+		meta.Author = service.User(r)
+	}
+	if name := metadataParam(r, "lang", HeaderLanguage); name != "" {
+		lexer := lookupLexer(name)
+		if lexer == nil {
+			return Metadata{}, errors.Errorf("unknown language %s", name)
+		}
+		meta.Language = lexer.Config().Name
+	}
+	if v := metadataParam(r, "content_type", HeaderContentType); v != "" {
+		contentType, err := parseContentType(v)
+		if err != nil {
+			return Metadata{}, err
+		}
+		meta.ContentType = contentType
+	}
+	return meta, nil
+}
+
+// metadata returns the metadata of the paste. Without a metadata backend, only the language
+// and parent hinted alongside the paste are known
+func (s *Service) metadata(c context.Context, id string) (Metadata, error) {
+	if s.MetadataBackend == nil {
+		return s.hint(c, id)
+	}
+
+	buf, err := s.MetadataBackend.Retrieve(c, metaPrefix+id)
+	if err != nil {
+		return Metadata{}, err
+	}
+	var meta Metadata
+	if err := json.Unmarshal(buf, &meta); err != nil {
+		return Metadata{}, errors.Wrap(err, "decoding text metadata")
+	}
+	return meta, nil
+}
+
+// lookupMetadata returns the metadata of the paste for rendering. Pastes saved without
+// metadata, or whose metadata cannot be retrieved, are rendered with the defaults
+func (s *Service) lookupMetadata(r *http.Request, id string) Metadata {
+	meta, err := s.metadata(r.Context(), id)
+	if err != nil && !errors.Is(err, app.ErrNotFound) {
+		s.logger(r).Error("unable to retrieve text metadata", zap.Error(err), zap.String("id", id))
+	}
+	return meta
+}
+
+// contentType returns the content type of the raw paste
+func (m Metadata) contentType() string {
+	if m.ContentType == "" {
+		return defaultContentType
+	}
+	return m.ContentType
+}
+
+// rawContentType returns the content type of the raw paste. Only the metadata backend
+// stores content types
+func (s *Service) rawContentType(r *http.Request, id string) string {
+	if s.MetadataBackend == nil {
+		return defaultContentType
+	}
+	return s.lookupMetadata(r, id).contentType()
+}
+
+func (s *Service) infoText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	info, err := s.statRevision(r, id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste"))
+		return
+	}
+
+	meta := s.lookupMetadata(r, id)
+	meta.ContentType = meta.contentType()
+
+	// the entry describes the latest revision, under the identifier of the paste
+	info.ID = prefix + id
+	entry := service.NewEntry(s.BaseURL, prefix, info)
+	entry.Metadata = meta
+	response.WriteResponse(w, r, entry)
+}
@@ -0,0 +1,266 @@
+package text
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// opCode describes how to turn a[I1:I2] into b[J1:J2]: 'e' for equal, 'd' for delete,
+// 'i' for insert and 'r' for replace, as in unified diffs
+type opCode struct {
+	Tag    byte
+	I1, I2 int
+	J1, J2 int
+}
+
+// myers finds the shortest edit script between two sequences of lines with the linear
+// space variant of Myers' algorithm. The work is bounded by a budget, so that pathological
+// inputs fail with errTooLargeToDiff rather than keeping a core busy
+type myers struct {
+	a, b []int
+	// deleted and inserted mark lines of a and b absent from the other side
+	deleted, inserted []bool
+	// forward and backward are the furthest reaching x of each diagonal
+	forward, backward []int
+	budget            int
+}
+
+// diffLines returns the changes from a to b, spending at most budget steps
+func diffLines(a, b []string, budget int) ([]opCode, error) {
+	// lines are compared as integers, as they are compared many times
+	ids := make(map[string]int, len(a))
+	intern := func(lines []string) []int {
+		out := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+			out[i] = id
+		}
+		return out
+	}
+	n := len(a) + len(b) + 1
+	m := &myers{
+		a:        intern(a),
+		b:        intern(b),
+		deleted:  make([]bool, len(a)),
+		inserted: make([]bool, len(b)),
+		forward:  make([]int, 2*n+1),
+		backward: make([]int, 2*n+1),
+		budget:   budget,
+	}
+	if err := m.compare(0, len(a), 0, len(b)); err != nil {
+		return nil, err
+	}
+	return m.opCodes(), nil
+}
+
+// compare marks the changes from a[aLo:aHi] to b[bLo:bHi], splitting both at the middle snake
+func (m *myers) compare(aLo, aHi, bLo, bHi int) error {
+	for aLo < aHi && bLo < bHi && m.a[aLo] == m.b[bLo] {
+		aLo++
+		bLo++
+	}
+	for aLo < aHi && bLo < bHi && m.a[aHi-1] == m.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+	switch {
+	case aLo == aHi:
+		for j := bLo; j < bHi; j++ {
+			m.inserted[j] = true
+		}
+		return nil
+	case bLo == bHi:
+		for i := aLo; i < aHi; i++ {
+			m.deleted[i] = true
+		}
+		return nil
+	}
+	x, y, err := m.middle(aLo, aHi, bLo, bHi)
+	if err != nil {
+		return err
+	}
+	if err := m.compare(aLo, x, bLo, y); err != nil {
+		return err
+	}
+	return m.compare(x, aHi, y, bHi)
+}
+
+// middle returns a point on a shortest edit script from a[aLo:aHi] to b[bLo:bHi], searching
+// forward from the start and backward from the end until both searches overlap
+func (m *myers) middle(aLo, aHi, bLo, bHi int) (int, int, error) {
+	n, k := aHi-aLo, bHi-bLo
+	delta := n - k
+	odd := delta&1 != 0
+	// diagonal d is stored at offset+d. The backward search runs on the reversed sequences,
+	// where diagonal d is diagonal delta-d of the forward search
+	offset := len(m.forward) / 2
+	m.forward[offset+1] = 0
+	m.backward[offset+1] = 0
+
+	for d := 0; d <= (n+k+1)/2; d++ {
+		// every diagonal is searched in both directions
+		m.budget -= 2 * (d + 1)
+		if m.budget < 0 {
+			return 0, 0, errTooLargeToDiff
+		}
+		for diag := -d; diag <= d; diag += 2 {
+			var x int
+			if diag == -d || (diag != d && m.forward[offset+diag-1] < m.forward[offset+diag+1]) {
+				x = m.forward[offset+diag+1]
+			} else {
+				x = m.forward[offset+diag-1] + 1
+			}
+			y, snake := x-diag, x
+			for x < n && y < k && m.a[aLo+x] == m.b[bLo+y] {
+				x++
+				y++
+			}
+			m.budget -= x - snake
+			m.forward[offset+diag] = x
+			if odd && diag >= delta-(d-1) && diag <= delta+(d-1) && x+m.backward[offset+delta-diag] >= n {
+				return aLo + x, bLo + y, nil
+			}
+		}
+		for diag := -d; diag <= d; diag += 2 {
+			var x int
+			if diag == -d || (diag != d && m.backward[offset+diag-1] < m.backward[offset+diag+1]) {
+				x = m.backward[offset+diag+1]
+			} else {
+				x = m.backward[offset+diag-1] + 1
+			}
+			y, snake := x-diag, x
+			for x < n && y < k && m.a[aHi-1-x] == m.b[bHi-1-y] {
+				x++
+				y++
+			}
+			m.budget -= x - snake
+			m.backward[offset+diag] = x
+			if !odd && delta-diag >= -d && delta-diag <= d && x+m.forward[offset+delta-diag] >= n {
+				return aHi - x, bHi - y, nil
+			}
+		}
+	}
+	return 0, 0, errors.New("edit script not found")
+}
+
+// opCodes returns the changes marked by compare as operations on ranges of lines
+func (m *myers) opCodes() []opCode {
+	var codes []opCode
+	i, j := 0, 0
+	for i < len(m.a) || j < len(m.b) {
+		i1, j1 := i, j
+		for i < len(m.a) && j < len(m.b) && !m.deleted[i] && !m.inserted[j] {
+			i++
+			j++
+		}
+		if i > i1 {
+			codes = append(codes, opCode{'e', i1, i, j1, j})
+		}
+		i1, j1 = i, j
+		for i < len(m.a) && m.deleted[i] {
+			i++
+		}
+		for j < len(m.b) && m.inserted[j] {
+			j++
+		}
+		switch {
+		case i > i1 && j > j1:
+			codes = append(codes, opCode{'r', i1, i, j1, j})
+		case i > i1:
+			codes = append(codes, opCode{'d', i1, i, j1, j})
+		case j > j1:
+			codes = append(codes, opCode{'i', i1, i, j1, j})
+		}
+	}
+	return codes
+}
+
+// groupOpCodes splits the changes into hunks with up to n lines of context, as in unified diffs
+func groupOpCodes(codes []opCode, n int) [][]opCode {
+	if len(codes) == 0 {
+		codes = []opCode{{'e', 0, 1, 0, 1}}
+	}
+	// leading and trailing context is trimmed to n lines
+	if c := codes[0]; c.Tag == 'e' {
+		codes[0] = opCode{'e', max(c.I1, c.I2-n), c.I2, max(c.J1, c.J2-n), c.J2}
+	}
+	if c := codes[len(codes)-1]; c.Tag == 'e' {
+		codes[len(codes)-1] = opCode{'e', c.I1, min(c.I2, c.I1+n), c.J1, min(c.J2, c.J1+n)}
+	}
+
+	var groups [][]opCode
+	var group []opCode
+	for _, c := range codes {
+		// unchanged ranges longer than the context on both sides end the hunk
+		if c.Tag == 'e' && c.I2-c.I1 > 2*n {
+			group = append(group, opCode{'e', c.I1, min(c.I2, c.I1+n), c.J1, min(c.J2, c.J1+n)})
+			groups = append(groups, group)
+			group = nil
+			c.I1, c.J1 = max(c.I1, c.I2-n), max(c.J1, c.J2-n)
+		}
+		group = append(group, c)
+	}
+	if len(group) > 0 && !(len(group) == 1 && group[0].Tag == 'e') {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// writeUnified writes the hunks from a to b as a unified diff
+func writeUnified(w io.Writer, fromFile, toFile string, a, b []string, groups [][]opCode) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "--- %s\n+++ %s\n", fromFile, toFile)
+	for _, group := range groups {
+		buf.WriteString(hunkHeader(group))
+		buf.WriteByte('\n')
+		for _, c := range group {
+			if c.Tag == 'e' {
+				for _, line := range a[c.I1:c.I2] {
+					writeLine(buf, ' ', line)
+				}
+				continue
+			}
+			for _, line := range a[c.I1:c.I2] {
+				writeLine(buf, '-', line)
+			}
+			for _, line := range b[c.J1:c.J2] {
+				writeLine(buf, '+', line)
+			}
+		}
+	}
+	return buf.Flush()
+}
+
+// writeLine writes the line of a hunk, marking the last line of a paste without a trailing newline
+func writeLine(buf *bufio.Writer, sign byte, line string) {
+	buf.WriteByte(sign)
+	buf.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		buf.WriteString("\n" + noNewline + "\n")
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,269 @@
+package text
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	revPrefix = "tr-"
+	// defaultMaxRevisions caps the revisions of a paste, including the paste itself
+	defaultMaxRevisions = 100
+)
+
+// Revision describes a revision of a paste in its history
+type Revision struct {
+	Revision int        `json:"revision"`
+	URL      string     `json:"url"`
+	Size     int64      `json:"size"`
+	Created  *time.Time `json:"created"`
+}
+
+// History is the response of /t-{id}/history, listing revisions from oldest to latest
+type History struct {
+	ID        string     `json:"id"`
+	Latest    int        `json:"latest"`
+	Revisions []Revision `json:"revisions"`
+}
+
+// revisionKey returns the key of the revision in the backend. The paste itself is the
+// first revision, and later revisions are stored under keys derived from it
+func revisionKey(id string, rev int) string {
+	if rev == 1 {
+		return prefix + id
+	}
+	return revPrefix + id + "-" + strconv.Itoa(rev)
+}
+
+// revisionRoute returns the route of the revision, e.g. hello@2
+func revisionRoute(id string, rev int) string {
+	return fmt.Sprintf("%s@%d", id, rev)
+}
+
+func (s *Service) hasRevision(c context.Context, id string, rev int) (bool, error) {
+	_, err := s.Backend.Stat(c, revisionKey(id, rev))
+	if errors.Is(err, app.ErrNotFound) || errors.Is(err, app.ErrExpired) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// latest returns the latest revision of the paste, as counted by editText under revPrefix+id.
+// Pastes never edited have no counter, so viewing them costs a single lookup
+func (s *Service) latest(c context.Context, id string) (int, error) {
+	if s.MaxRevisions < 2 {
+		return 1, nil
+	}
+	counter, err := s.Backend.Retrieve(c, revPrefix+id)
+	if errors.Is(err, app.ErrNotFound) || errors.Is(err, app.ErrExpired) {
+		return 1, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer counter.Close()
+
+	buf, err := ioutil.ReadAll(io.LimitReader(counter, 16))
+	if err != nil {
+		return 0, err
+	}
+	rev, err := strconv.Atoi(string(buf))
+	if err != nil || rev < 1 {
+		return 0, errors.Errorf("invalid revision counter %q", buf)
+	}
+	if rev > s.MaxRevisions {
+		rev = s.MaxRevisions
+	}
+	return rev, nil
+}
+
+// count records rev as the latest revision of the paste, replacing the previous counter at once
+func (s *Service) count(c context.Context, id string, rev int, ttl time.Duration) error {
+	_, err := s.Backend.Overwrite(c, revPrefix+id, io.NopCloser(strings.NewReader(strconv.Itoa(rev))), ttl)
+	return err
+}
+
+// resolveRevision returns the key of the revision, or of the latest revision if rev is empty
+func (s *Service) resolveRevision(c context.Context, id, rev string) (string, error) {
+	if rev == "" {
+		latest, err := s.latest(c, id)
+		if err != nil {
+			return "", err
+		}
+		return revisionKey(id, latest), nil
+	}
+	n, err := strconv.Atoi(rev)
+	if err != nil || n < 1 || n > s.MaxRevisions {
+		return "", app.ErrNotFound
+	}
+	return revisionKey(id, n), nil
+}
+
+// revision returns the key of the revision requested by the rev URL parameter,
+// or of the latest revision if omitted
+func (s *Service) revision(r *http.Request, id string) (string, error) {
+	return s.resolveRevision(r.Context(), id, chi.URLParam(r, "rev"))
+}
+
+// openRevision retrieves the revision of the paste requested by the rev URL parameter
+func (s *Service) openRevision(r *http.Request, id string) (io.ReadCloser, error) {
+	key, err := s.revision(r, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.Backend.Retrieve(r.Context(), key)
+}
+
+// statRevision returns the info of the revision of the paste requested by the rev URL parameter
+func (s *Service) statRevision(r *http.Request, id string) (app.Info, error) {
+	key, err := s.revision(r, id)
+	if err != nil {
+		return app.Info{}, err
+	}
+	return s.Backend.Stat(r.Context(), key)
+}
+
+func (s *Service) editText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	digest, err := s.retrieveSecret(r, id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve secret from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to edit text paste"))
+		return
+	}
+
+	if !service.VerifySecret(r.Header.Get(service.HeaderSecret), digest) {
+		response.WriteError(w, r, response.ErrForbidden().AddMessages("Invalid secret"))
+		return
+	}
+
+	// revisions expire along with the paste
+	info, err := s.Backend.Stat(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) || errors.Is(err, app.ErrExpired) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to edit text paste"))
+		return
+	}
+	var ttl time.Duration
+	if !info.Expires.IsZero() {
+		ttl = time.Until(info.Expires)
+		if ttl <= 0 {
+			response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+			return
+		}
+	}
+
+	latest, err := s.latest(r.Context(), id)
+	// the counter lags behind revisions saved by edits which failed to update it
+	for err == nil && latest < s.MaxRevisions {
+		var ok bool
+		if ok, err = s.hasRevision(r.Context(), id, latest+1); ok {
+			latest++
+		} else {
+			break
+		}
+	}
+	if err != nil {
+		s.logger(r).Error("unable to find latest revision", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to edit text paste"))
+		return
+	}
+	if latest >= s.MaxRevisions {
+		response.WriteError(w, r, response.ErrConflict().AddMessages(fmt.Sprintf("Text paste cannot have more than %d revisions", s.MaxRevisions)))
+		return
+	}
+
+	if s.MaxSize > 0 && r.ContentLength > s.MaxSize {
+		response.WriteError(w, r, s.errTooLarge())
+		return
+	}
+	service.LimitBody(w, r, s.MaxSize)
+
+	body, err := readText(r)
+	if s.writeBodyError(w, r, err) {
+		return
+	}
+
+	rev := latest + 1
+	_, err = s.Backend.SaveTTL(r.Context(), revisionKey(id, rev), body.ReadCloser, ttl)
+	if errors.Is(err, app.ErrConflict) {
+		response.WriteError(w, r, response.ErrConflict().AddMessages("Text paste was edited concurrently, please try again"))
+		return
+	} else if service.TooLarge(err) {
+		response.WriteError(w, r, s.errTooLarge())
+		return
+	} else if errors.Is(err, app.ErrQuotaExceeded) {
+		response.WriteError(w, r, response.ErrInsufficientStorage().AddMessages("Storage quota exceeded"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to save revision to backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to edit text paste"))
+		return
+	}
+
+	// the revision is saved regardless, and the next edit finds it even if the counter lags
+	if err := s.count(r.Context(), id, rev, ttl); err != nil {
+		s.logger(r).Error("unable to count revision in backend", zap.Error(err), zap.String("id", id), zap.Int("revision", rev))
+	}
+
+	service.WriteSaved(w, r, service.NewSaved(s.BaseURL, prefix, revisionRoute(id, rev), ttl))
+}
+
+func (s *Service) historyText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	latest, err := s.latest(r.Context(), id)
+	if err != nil {
+		s.logger(r).Error("unable to find latest revision", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste history"))
+		return
+	}
+
+	history := History{
+		ID:        id,
+		Latest:    latest,
+		Revisions: make([]Revision, 0, latest),
+	}
+	for rev := 1; rev <= latest; rev++ {
+		info, err := s.Backend.Stat(r.Context(), revisionKey(id, rev))
+		if errors.Is(err, app.ErrNotFound) || errors.Is(err, app.ErrExpired) {
+			response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+			return
+		} else if err != nil {
+			s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id), zap.Int("revision", rev))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste history"))
+			return
+		}
+		revision := Revision{
+			Revision: rev,
+			URL:      service.Ret(s.BaseURL, prefix, revisionRoute(id, rev)),
+			Size:     info.Size,
+		}
+		if !info.Created.IsZero() {
+			revision.Created = &info.Created
+		}
+		history.Revisions = append(history.Revisions, revision)
+	}
+
+	response.WriteResponse(w, r, history)
+}
@@ -1,53 +1,96 @@
 package text
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/zllovesuki/b/app"
-	"github.com/zllovesuki/b/box"
 	"github.com/zllovesuki/b/response"
 	"github.com/zllovesuki/b/service"
 
+	"github.com/alecthomas/chroma/v2/styles"
 	"github.com/go-chi/chi/v5"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
 const (
-	prefix = "t-"
+	kind         = "text"
+	prefix       = "t-"
+	secretPrefix = "ts-"
 )
 
 type Options struct {
 	BaseURL string
-	Asset   box.AssetExtractor
-	Backend app.FastBackend
-	Logger  *zap.Logger
+	Backend app.RemovableFastBackend
+	// MetadataBackend optionally stores the title, language, author and content type of pastes.
+	// Without it, only the language is stored alongside the paste
+	MetadataBackend app.RemovableBackend
+	Logger          *zap.Logger
+	// TTL is the optional policy enforced on save and extension
+	TTL *service.TTLPolicy
+	// MaxSize caps the size of a paste in bytes. 0 means unlimited
+	MaxSize int64
+	// Owners optionally records the owner of pastes saved by authenticated users
+	Owners service.Ownership
+	// Theme is the default chroma style of highlighted pastes. Defaults to nord
+	Theme string
+	// HighlightSize caps the size of pastes highlighted in bytes. Larger pastes are rendered
+	// without highlighting. Defaults to 1MiB
+	HighlightSize int64
+	// MarkdownSize caps the size of pastes rendered as markdown in bytes. Larger pastes are
+	// rendered as plain text. Defaults to 1MiB
+	MarkdownSize int64
+	// DiffSize caps the size of each paste compared by diffs in bytes. Defaults to 256KiB
+	DiffSize int64
+	// Events optionally receives created, deleted and expired events of pastes
+	Events service.Events
+	// MaxRevisions caps the revisions of a paste, including the paste itself. 1 disables
+	// editing. Defaults to 100
+	MaxRevisions int
 }
 
 type Service struct {
 	Options
-	head []byte
-	foot []byte
 }
 
+var _ service.Items = &Service{}
+
 func (o *Options) validate() error {
 	if o.BaseURL == "" {
 		return errors.New("baseurl cannot be empty")
 	}
-	if o.Asset == nil {
-		return errors.New("missing asset extractor")
-	}
 	if o.Backend == nil {
 		return errors.New("missing backend")
 	}
 	if o.Logger == nil {
 		return errors.New("missing logger")
 	}
+	if o.MaxSize < 0 {
+		return errors.New("max size cannot be negative")
+	}
+	if o.HighlightSize < 0 {
+		return errors.New("highlight size cannot be negative")
+	}
+	if o.MarkdownSize < 0 {
+		return errors.New("markdown size cannot be negative")
+	}
+	if o.DiffSize < 0 {
+		return errors.New("diff size cannot be negative")
+	}
+	if o.MaxRevisions < 0 {
+		return errors.New("max revisions cannot be negative")
+	}
+	if _, ok := styles.Registry[o.Theme]; o.Theme != "" && !ok {
+		return errors.Errorf("unknown theme %s", o.Theme)
+	}
 	return nil
 }
 
@@ -55,103 +98,513 @@ func NewService(option Options) (*Service, error) {
 	if err := option.validate(); err != nil {
 		return nil, err
 	}
-	h := option.Asset.Get("/highlightHead.html")
-	f := option.Asset.Get("/highlightFoot.html")
-	if h == "" || f == "" {
-		return nil, errors.New("unable to extract highlight.html")
+	if option.Theme == "" {
+		option.Theme = defaultTheme
 	}
-	head, err := os.Open(h)
-	if err != nil {
-		return nil, errors.Wrap(err, "opening highlightHead.html")
+	if option.HighlightSize == 0 {
+		option.HighlightSize = defaultHighlightSize
+	}
+	if option.MarkdownSize == 0 {
+		option.MarkdownSize = defaultMarkdownSize
+	}
+	if option.DiffSize == 0 {
+		option.DiffSize = defaultDiffSize
+	}
+	if option.MaxRevisions == 0 {
+		option.MaxRevisions = defaultMaxRevisions
 	}
-	defer head.Close()
 
-	foot, err := os.Open(f)
-	if err != nil {
-		return nil, errors.Wrap(err, "opening highlightFoot.html")
+	s := &Service{
+		Options: option,
+	}
+	if n, ok := option.Backend.(app.ExpiryNotifier); ok && option.Events != nil {
+		n.OnExpire(s.expired)
 	}
-	defer foot.Close()
+	return s, nil
+}
+
+// expired emits the expired event of pastes removed by the backend
+func (s *Service) expired(c context.Context, identifier string) {
+	if !strings.HasPrefix(identifier, prefix) {
+		return
+	}
+	id := strings.TrimPrefix(identifier, prefix)
+	service.Emit(c, s.Events, service.NewEvent(kind, service.ActionExpired, id, service.Ret(s.BaseURL, prefix, id)))
+}
 
-	b, err := ioutil.ReadAll(head)
+// logger returns the request-scoped logger if available
+func (s *Service) logger(r *http.Request) *zap.Logger {
+	return service.Logger(r, s.Logger)
+}
+
+func (s *Service) saveText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	meta, err := parseMetadata(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "reading head into buffer")
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
 	}
-	c, err := ioutil.ReadAll(foot)
+
+	if s.MaxSize > 0 && r.ContentLength > s.MaxSize {
+		response.WriteError(w, r, s.errTooLarge())
+		return
+	}
+	service.LimitBody(w, r, s.MaxSize)
+
+	body, err := readText(r)
+	if s.writeBodyError(w, r, err) {
+		return
+	}
+
+	ttl, err := s.parseTTL(r, body.TTL)
 	if err != nil {
-		return nil, errors.Wrap(err, "reading foot into buffer")
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
 	}
 
-	return &Service{
-		Options: option,
-		head:    b,
-		foot:    c,
-	}, nil
+	if body.Language != "" {
+		lexer := lookupLexer(body.Language)
+		if lexer == nil {
+			response.WriteError(w, r, response.ErrBadRequest().AddMessages("unknown language "+body.Language))
+			return
+		}
+		meta.Language = lexer.Config().Name
+	}
+
+	s.create(w, r, id, meta, body.ReadCloser, ttl)
 }
 
-func (s *Service) saveText(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	ttl := service.ParseTTL(r)
+// create saves the paste along with its secret, owner and metadata, and responds with the secret.
+// Everything saved is removed if any of it fails
+func (s *Service) create(w http.ResponseWriter, r *http.Request, id string, meta Metadata, text io.ReadCloser, ttl time.Duration) {
+	qr, err := service.ParseSavedQR(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
 
-	if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
-		response.WriteError(w, r, response.ErrBadRequest().
-			AddMessages("Request content-type is not application/x-www-form-urlencoded").
-			AddMessages("If you are using curl, please use the following command:").
-			AddMessages("cat foo.txt | curl --data-binary @- http://example.com/t-foo"))
+	secret, digest, err := service.NewSecret()
+	if err != nil {
+		s.logger(r).Error("unable to generate secret", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save text paste"))
 		return
 	}
 
-	_, err := s.Backend.SaveTTL(r.Context(), prefix+id, r.Body, time.Second*time.Duration(ttl))
+	// secret is saved first so a conflict leaves the existing paste untouched
+	_, err = s.Backend.SaveTTL(r.Context(), secretPrefix+id, io.NopCloser(bytes.NewReader(digest)), ttl)
 	if errors.Is(err, app.ErrConflict) {
 		response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
 		return
+	} else if errors.Is(err, app.ErrQuotaExceeded) {
+		response.WriteError(w, r, response.ErrInsufficientStorage().AddMessages("Storage quota exceeded"))
+		return
 	} else if err != nil {
-		s.Logger.Error("unable to save to backend", zap.Error(err))
+		s.logger(r).Error("unable to save secret to backend", zap.Error(err))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save text paste"))
 		return
 	}
 
-	response.WriteResponse(w, r, service.Ret(s.BaseURL, prefix, id))
+	// remove the secret if we could not save the paste
+	defer func() {
+		if err == nil {
+			return
+		}
+		if err := s.Backend.Delete(r.Context(), secretPrefix+id); err != nil {
+			s.logger(r).Error("removing secret of failed save from backend", zap.Error(err), zap.String("id", id))
+		}
+	}()
+
+	if owner := service.User(r); owner != "" && s.Owners != nil {
+		err = s.Owners.Own(r.Context(), kind, id, owner, ttl)
+		if errors.Is(err, app.ErrConflict) {
+			response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+			return
+		} else if err != nil {
+			s.logger(r).Error("unable to record owner", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save text paste"))
+			return
+		}
+
+		defer func() {
+			if err == nil {
+				return
+			}
+			if err := s.Owners.Disown(r.Context(), kind, id); err != nil {
+				s.logger(r).Error("removing owner of failed save", zap.Error(err), zap.String("id", id))
+			}
+		}()
+	}
+
+	if s.MetadataBackend != nil {
+		var buf []byte
+		buf, err = json.Marshal(meta)
+		if err != nil {
+			response.WriteError(w, r, response.ErrUnexpected())
+			return
+		}
+
+		err = s.MetadataBackend.SaveTTL(r.Context(), metaPrefix+id, buf, ttl)
+		if errors.Is(err, app.ErrConflict) {
+			response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+			return
+		} else if err != nil {
+			s.logger(r).Error("unable to save to metadata backend", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save text metadata"))
+			return
+		}
+
+		defer func() {
+			if err == nil {
+				return
+			}
+			if err := s.MetadataBackend.Delete(r.Context(), metaPrefix+id); err != nil {
+				s.logger(r).Error("removing metadata of failed save from metadata backend", zap.Error(err), zap.String("id", id))
+			}
+		}()
+	} else if hint := hintOf(meta); hint != "" {
+		_, err = s.Backend.SaveTTL(r.Context(), langPrefix+id, io.NopCloser(strings.NewReader(hint)), ttl)
+		if errors.Is(err, app.ErrConflict) {
+			response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+			return
+		} else if err != nil {
+			s.logger(r).Error("unable to save hint to backend", zap.Error(err))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save text paste"))
+			return
+		}
+
+		defer func() {
+			if err == nil {
+				return
+			}
+			if err := s.Backend.Delete(r.Context(), langPrefix+id); err != nil {
+				s.logger(r).Error("removing hint of failed save from backend", zap.Error(err), zap.String("id", id))
+			}
+		}()
+	}
+
+	_, err = s.Backend.SaveTTL(r.Context(), prefix+id, text, ttl)
+	if errors.Is(err, app.ErrConflict) {
+		response.WriteError(w, r, response.ErrConflict().AddMessages("Conflicting identifier"))
+		return
+	} else if service.TooLarge(err) {
+		response.WriteError(w, r, s.errTooLarge())
+		return
+	} else if errors.Is(err, app.ErrQuotaExceeded) {
+		response.WriteError(w, r, response.ErrInsufficientStorage().AddMessages("Storage quota exceeded"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to save to backend", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to save text paste"))
+		return
+	}
+
+	saved := service.NewSaved(s.BaseURL, prefix, id, ttl)
+	service.Emit(r.Context(), s.Events, service.NewCreatedEvent(kind, id, service.User(r), saved))
+
+	w.Header().Set(service.HeaderSecret, secret)
+	service.WriteSaved(w, r, service.WithQR(saved, s.logger(r), qr))
+}
+
+// writeBodyError writes the response to errors returned by readText, and reports whether there was one
+func (s *Service) writeBodyError(w http.ResponseWriter, r *http.Request, err error) bool {
+	if service.TooLarge(err) {
+		response.WriteError(w, r, s.errTooLarge())
+	} else if errors.Is(err, errUnsupportedMediaType) {
+		response.WriteError(w, r, response.ErrUnsupportedMediaType().
+			AddMessages("Text paste must be sent as text/*, application/json or multipart/form-data").
+			AddMessages("If you are using curl, please use the following command:").
+			AddMessages("cat foo.txt | curl --data-binary @- http://example.com/t-foo"))
+	} else if errors.Is(err, errInvalidJSON) {
+		response.WriteError(w, r, response.ErrInvalidJson())
+	} else if errors.Is(err, errMissingContent) {
+		response.WriteError(w, r, response.ErrBadRequest().
+			AddMessages(fmt.Sprintf(`Text paste is missing, send it as "content" in JSON or the %q form field`, textField)))
+	} else if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+	}
+	return err != nil
+}
+
+// parseTTL returns the effective ttl given in the JSON body, or otherwise in the request
+func (s *Service) parseTTL(r *http.Request, v string) (time.Duration, error) {
+	if v == "" {
+		return s.TTL.Parse(r)
+	}
+	ttl, err := service.ParseTTLValue(v)
+	if err != nil {
+		return 0, err
+	}
+	return s.TTL.Apply(ttl, true)
+}
+
+func (s *Service) errTooLarge() *response.Error {
+	return response.ErrPayloadTooLarge().AddMessages(fmt.Sprintf("Text paste cannot be larger than %d bytes", s.MaxSize))
+}
+
+func (s *Service) touchText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ttl, err := s.TTL.ParseExtend(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	digest, err := s.retrieveSecret(r, id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve secret from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to extend text paste"))
+		return
+	}
+
+	if !service.VerifySecret(r.Header.Get(service.HeaderSecret), digest) {
+		response.WriteError(w, r, response.ErrForbidden().AddMessages("Invalid secret"))
+		return
+	}
+
+	saved, err := s.Touch(r.Context(), id, ttl)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to touch in backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to extend text paste"))
+		return
+	}
+
+	service.WriteSaved(w, r, saved)
+}
+
+// Kind identifies text pastes in service.Ownership
+func (s *Service) Kind() string {
+	return kind
+}
+
+// Policy returns the ttl policy of text pastes
+func (s *Service) Policy() *service.TTLPolicy {
+	return s.TTL
+}
+
+// Inspect returns the entry of the text paste
+func (s *Service) Inspect(c context.Context, id string) (service.Entry, error) {
+	info, err := s.Backend.Stat(c, prefix+id)
+	if err != nil {
+		return service.Entry{}, err
+	}
+	return service.NewEntry(s.BaseURL, prefix, info), nil
+}
+
+// Touch extends the expiration of the text paste, along with its revisions, secret, metadata and owner
+func (s *Service) Touch(c context.Context, id string, ttl time.Duration) (service.Saved, error) {
+	latest, err := s.latest(c, id)
+	if err != nil {
+		return service.Saved{}, errors.Wrap(err, "finding latest revision")
+	}
+	keys := []string{prefix + id, secretPrefix + id}
+	for rev := 2; rev <= latest; rev++ {
+		keys = append(keys, revisionKey(id, rev))
+	}
+	for _, key := range keys {
+		if err := s.Backend.Touch(c, key, ttl); err != nil {
+			return service.Saved{}, err
+		}
+	}
+	// pastes saved without a language or parent have no hint, and pastes never edited have no revision counter
+	for _, key := range []string{langPrefix + id, revPrefix + id} {
+		if err := s.Backend.Touch(c, key, ttl); err != nil && !errors.Is(err, app.ErrNotFound) {
+			return service.Saved{}, err
+		}
+	}
+	// pastes saved before the metadata backend was configured have no metadata
+	if s.MetadataBackend != nil {
+		if err := s.MetadataBackend.Touch(c, metaPrefix+id, ttl); err != nil && !errors.Is(err, app.ErrNotFound) {
+			return service.Saved{}, errors.Wrap(err, "touching metadata")
+		}
+	}
+	if s.Owners != nil {
+		if err := s.Owners.TouchOwner(c, kind, id, ttl); err != nil && !errors.Is(err, app.ErrNotFound) {
+			return service.Saved{}, err
+		}
+	}
+	return service.NewSaved(s.BaseURL, prefix, id, ttl), nil
+}
+
+// Remove deletes the text paste, along with its revisions, secret, metadata and owner
+func (s *Service) Remove(c context.Context, id string) error {
+	latest, err := s.latest(c, id)
+	if err != nil {
+		return errors.Wrap(err, "finding latest revision")
+	}
+	keys := []string{prefix + id, secretPrefix + id, langPrefix + id}
+	for rev := 2; rev <= latest; rev++ {
+		keys = append(keys, revisionKey(id, rev))
+	}
+	// the counter goes last, so revisions left behind by a failed removal are still found
+	for _, key := range append(keys, revPrefix+id) {
+		if err := s.Backend.Delete(c, key); err != nil {
+			return err
+		}
+	}
+	if s.MetadataBackend != nil {
+		if err := s.MetadataBackend.Delete(c, metaPrefix+id); err != nil {
+			return errors.Wrap(err, "deleting from metadata backend")
+		}
+	}
+	if s.Owners != nil {
+		if err := s.Owners.Disown(c, kind, id); err != nil {
+			return err
+		}
+	}
+	service.Emit(c, s.Events, service.NewEvent(kind, service.ActionDeleted, id, service.Ret(s.BaseURL, prefix, id)))
+	return nil
+}
+
+func (s *Service) retrieveSecret(r *http.Request, id string) ([]byte, error) {
+	secret, err := s.Backend.Retrieve(r.Context(), secretPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	defer secret.Close()
+
+	return ioutil.ReadAll(io.LimitReader(secret, 64))
 }
 
 func (s *Service) retrieveText(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	html := strings.HasSuffix(r.RequestURI, ".html")
+	ext := chi.URLParam(r, "ext")
 
-	text, err := s.Backend.Retrieve(r.Context(), prefix+id)
+	// ?diff= compares another paste or revision to this one
+	if other := r.URL.Query().Get("diff"); other != "" && (ext == "" || ext == autoExt) {
+		side := id
+		if rev := chi.URLParam(r, "rev"); rev != "" {
+			side = id + "@" + rev
+		}
+		s.diff(w, r, other, side, ext == autoExt)
+		return
+	}
+
+	text, err := s.openRevision(r, id)
 	if errors.Is(err, app.ErrNotFound) {
 		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
 		return
 	} else if err != nil {
-		s.Logger.Error("unable to retrieve from backend", zap.Error(err), zap.String("id", id))
+		s.logger(r).Error("unable to retrieve from backend", zap.Error(err), zap.String("id", id))
 		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste"))
 		return
 	}
 	defer text.Close()
 
-	var wDst io.Writer
-	if html {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		w.Write(s.head)
-		wDst = service.NewHTMLEscapeWriter(w)
-		defer w.Write(s.foot)
-	} else {
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		wDst = w
+	switch ext {
+	case "":
+	case markdownExt:
+		s.markdown(w, r, id, text)
+		return
+	default:
+		s.highlight(w, r, id, ext, text)
+		return
 	}
-	if w, err := io.Copy(wDst, text); err != nil {
-		s.Logger.Warn("piping text buffer", zap.Error(err), zap.Int64("bytes-written", w))
+
+	w.Header().Set("Content-Type", s.rawContentType(r, id))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if w, err := io.Copy(w, text); err != nil {
+		s.logger(r).Warn("piping text buffer", zap.Error(err), zap.Int64("bytes-written", w))
 	}
 }
 
-// SaveRoute returns a mountable router for saving text paste.
+func (s *Service) qrText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	q, err := service.ParseQR(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	_, err = s.Backend.Stat(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste"))
+		return
+	}
+
+	service.WriteQR(w, r, s.logger(r), q, service.Ret(s.BaseURL, prefix, id))
+}
+
+func (s *Service) statText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	info, err := s.statRevision(r, id)
+	if errors.Is(err, app.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", s.rawContentType(r, id))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	service.WriteInfoHeaders(w, info)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Service) inspectText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	info, err := s.Backend.Stat(r.Context(), prefix+id)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Text paste either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to stat from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve text paste"))
+		return
+	}
+
+	entry := service.NewEntry(s.BaseURL, prefix, info)
+	entry.Owner = service.OwnerOf(r.Context(), s.Owners, kind, id)
+	if s.MetadataBackend != nil {
+		entry.Metadata = s.lookupMetadata(r, id)
+	}
+
+	response.WriteResponse(w, r, entry)
+}
+
+func (s *Service) deleteText(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := s.Remove(r.Context(), id); err != nil {
+		s.logger(r).Error("unable to delete from backend", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to delete text paste"))
+		return
+	}
+
+	response.WriteResponse(w, r, service.Ret(s.BaseURL, prefix, id))
+}
+
+// SaveRoute returns a mountable router for saving text paste and extending its expiration.
 // Alternatively, it can mount directly to the provided router.
 func (s *Service) SaveRoute(r chi.Router) http.Handler {
 	if r == nil {
 		r = chi.NewRouter()
 	}
 
-	r.Put(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/{ttl:[0-9]+}"), s.saveText)
+	r.Put(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/{ttl}"), s.saveText)
 	r.Put(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.saveText)
+	r.Patch(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/{ttl}"), s.touchText)
+	r.Patch(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.touchText)
+	r.Post(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.editText)
+	r.Post(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/fork/{ttl}"), s.forkText)
+	r.Post(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/fork"), s.forkText)
+	r.Post(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}@{rev:[0-9]+}/fork/{ttl}"), s.forkText)
+	r.Post(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}@{rev:[0-9]+}/fork"), s.forkText)
 
 	return r
 }
@@ -163,8 +616,31 @@ func (s *Service) RetrieveRoute(r chi.Router) http.Handler {
 		r = chi.NewRouter()
 	}
 
-	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}.html"), s.retrieveText)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}.{ext:[a-zA-Z0-9+_-]+}"), s.retrieveText)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}@{rev:[0-9]+}.{ext:[a-zA-Z0-9+_-]+}"), s.retrieveText)
 	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.retrieveText)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}@{rev:[0-9]+}"), s.retrieveText)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/info"), s.infoText)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/history"), s.historyText)
+	r.Get(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}/qr"), s.qrText)
+	r.Get(service.Prefix(prefix, "{a:[a-zA-Z0-9@]+}..{b:[a-zA-Z0-9@]+}.{ext:html}"), s.diffText)
+	r.Get(service.Prefix(prefix, "{a:[a-zA-Z0-9@]+}..{b:[a-zA-Z0-9@]+}"), s.diffText)
+	r.Head(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}"), s.statText)
+	r.Head(service.Prefix(prefix, "{id:[a-zA-Z0-9]+}@{rev:[0-9]+}"), s.statText)
+
+	return r
+}
+
+// AdminRoute returns a mountable router for listing, inspecting and deleting text pastes.
+// Alternatively, it can mount directly to the provided router.
+func (s *Service) AdminRoute(r chi.Router) http.Handler {
+	if r == nil {
+		r = chi.NewRouter()
+	}
+
+	r.Get("/text", service.ListHandler(s.BaseURL, prefix, s.Logger, s.Backend.List))
+	r.Get("/text/{id:[a-zA-Z0-9]+}", s.inspectText)
+	r.Delete("/text/{id:[a-zA-Z0-9]+}", s.deleteText)
 
 	return r
 }
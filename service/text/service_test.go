@@ -2,20 +2,25 @@ package text
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/zllovesuki/b/app"
-	"github.com/zllovesuki/b/box"
-	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/fast"
 	"github.com/zllovesuki/b/service"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
@@ -23,16 +28,14 @@ import (
 
 type testDependencies struct {
 	baseURL     string
-	mockBackend *app.MockFastBackend
+	mockBackend *app.MockRemovableFastBackend
 	recorder    *httptest.ResponseRecorder
 	service     *Service
 }
 
-var asset = box.GetAssetExtractor()
-
 func getFixtures(t *testing.T) (*testDependencies, func()) {
 	ctrl := gomock.NewController(t)
-	mockBackend := app.NewMockFastBackend(ctrl)
+	mockBackend := app.NewMockRemovableFastBackend(ctrl)
 
 	recorder := httptest.NewRecorder()
 
@@ -42,7 +45,6 @@ func getFixtures(t *testing.T) (*testDependencies, func()) {
 
 	service, err := NewService(Options{
 		BaseURL: base,
-		Asset:   asset,
 		Backend: mockBackend,
 		Logger:  logger,
 	})
@@ -58,6 +60,13 @@ func getFixtures(t *testing.T) (*testDependencies, func()) {
 		}
 }
 
+// expectUnedited expects the lookup of the latest revision of a paste never edited
+func expectUnedited(dep *testDependencies, id string) *gomock.Call {
+	return dep.mockBackend.EXPECT().
+		Retrieve(gomock.Any(), revPrefix+id).
+		Return(nil, app.ErrNotFound)
+}
+
 func TestGetText(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		dep, finish := getFixtures(t)
@@ -74,6 +83,8 @@ func TestGetText(t *testing.T) {
 			Retrieve(gomock.Any(), prefix+id).
 			Return(io.NopCloser(ret), nil)
 
+		expectUnedited(dep, id)
+
 		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
 
 		resp := dep.recorder.Result()
@@ -97,6 +108,8 @@ func TestGetText(t *testing.T) {
 			Retrieve(gomock.Any(), prefix+id).
 			Return(nil, app.ErrNotFound)
 
+		expectUnedited(dep, id)
+
 		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
 
 		resp := dep.recorder.Result()
@@ -117,6 +130,8 @@ func TestGetText(t *testing.T) {
 			Retrieve(gomock.Any(), prefix+id).
 			Return(nil, fmt.Errorf("error"))
 
+		expectUnedited(dep, id)
+
 		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
 
 		resp := dep.recorder.Result()
@@ -140,22 +155,25 @@ func TestGetText(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 
-	t.Run(".html in url should return highlight.js page", func(t *testing.T) {
+	t.Run(".html in url should return highlighted page", func(t *testing.T) {
 		dep, finish := getFixtures(t)
 		defer finish()
 
 		id := "hello"
-		txt := []byte("hello world")
-		ret := bytes.NewBuffer(txt)
+		txt := []byte("hello <world>")
 		uri := service.Prefix(prefix, fmt.Sprintf("%s.html", id))
 
 		r, err := http.NewRequest("GET", uri, nil)
-		r.RequestURI = uri // monkey patch
 		require.NoError(t, err)
 
 		dep.mockBackend.EXPECT().
 			Retrieve(gomock.Any(), prefix+id).
-			Return(io.NopCloser(ret), nil)
+			Return(io.NopCloser(bytes.NewBuffer(txt)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound)
+
+		expectUnedited(dep, id)
 
 		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
 
@@ -166,7 +184,307 @@ func TestGetText(t *testing.T) {
 
 		buf, err := ioutil.ReadAll(resp.Body)
 		require.NoError(t, err)
-		require.Contains(t, string(buf), string(txt))
+		require.Contains(t, string(buf), "hello &lt;world&gt;")
+		require.Contains(t, string(buf), `href="#L1"`)
+		require.NotContains(t, string(buf), "<script")
+	})
+}
+
+func TestHighlightText(t *testing.T) {
+	const goSource = "package main\n\nfunc main() {}\n"
+	const goKeyword = `<span class="kn">package</span>`
+
+	get := func(t *testing.T, dep *testDependencies, uri string) string {
+		r, err := http.NewRequest("GET", uri, nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		buf, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(buf)
+	}
+
+	t.Run("stored language hint", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(goSource)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(io.NopCloser(bytes.NewBufferString("Go")), nil)
+
+		expectUnedited(dep, id)
+
+		require.Contains(t, get(t, dep, service.Prefix(prefix, id+".html")), goKeyword)
+	})
+
+	t.Run("stored parent hint", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "copy"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(goSource)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(io.NopCloser(bytes.NewBufferString("Go\nhello@2")), nil)
+
+		expectUnedited(dep, id)
+
+		body := get(t, dep, service.Prefix(prefix, id+".html"))
+		require.Contains(t, body, goKeyword)
+		require.Contains(t, body, `forked from <a href="`+service.Ret(dep.baseURL, prefix, "hello@2."+autoExt)+`">t-hello@2</a>`)
+	})
+
+	t.Run("extension selects language", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(goSource)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound)
+
+		expectUnedited(dep, id)
+
+		require.Contains(t, get(t, dep, service.Prefix(prefix, id+".go")), goKeyword)
+	})
+
+	t.Run("lang query overrides extension", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(goSource)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound)
+
+		expectUnedited(dep, id)
+
+		require.Contains(t, get(t, dep, service.Prefix(prefix, id+".txt")+"?lang=golang"), goKeyword)
+	})
+
+	t.Run("theme selection", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(goSource)), nil).
+			Times(2)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound).
+			Times(2)
+
+		expectUnedited(dep, id).Times(2)
+
+		require.Contains(t, get(t, dep, service.Prefix(prefix, id+".go")), "#2e3440")
+
+		dep.recorder = httptest.NewRecorder()
+		require.Contains(t, get(t, dep, service.Prefix(prefix, id+".go")+"?theme=monokai"), "#272822")
+	})
+
+	t.Run("large paste is not highlighted", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		dep.service.HighlightSize = 4
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(goSource+"// <b>")), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound)
+
+		expectUnedited(dep, id)
+
+		page := get(t, dep, service.Prefix(prefix, id+".go"))
+		require.NotContains(t, page, goKeyword)
+		require.Contains(t, page, "package main")
+		require.Contains(t, page, "// &lt;b&gt;")
+	})
+}
+
+func TestMarkdownText(t *testing.T) {
+	const readme = "# Hello World\n\n| a | b |\n|:--|--:|\n| 1 | 2 |\n\n- [x] done\n\n" +
+		"```go\npackage main\n```\n\n<script>alert(1)</script>\n\n[x](javascript:alert(1))\n"
+
+	get := func(t *testing.T, dep *testDependencies, uri string) string {
+		r, err := http.NewRequest("GET", uri, nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+		buf, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(buf)
+	}
+
+	t.Run("renders sanitized markdown", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(readme)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound)
+
+		expectUnedited(dep, id)
+
+		page := get(t, dep, service.Prefix(prefix, id+".md"))
+		require.Contains(t, page, `<h1 id="hello-world"><a href="#hello-world" class="anchor"`)
+		require.Contains(t, page, `<th align="left">a</th>`)
+		require.Contains(t, page, `<input checked="" disabled="" type="checkbox"`)
+		require.Contains(t, page, `<span class="kn">package</span>`)
+		require.NotContains(t, page, "<script>")
+		require.NotContains(t, page, "javascript:")
+	})
+
+	t.Run("large paste is not rendered", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		dep.service.MarkdownSize = 4
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(readme)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound)
+
+		expectUnedited(dep, id)
+
+		page := get(t, dep, service.Prefix(prefix, id+".md"))
+		require.NotContains(t, page, "<h1")
+		require.Contains(t, page, "# Hello World")
+		require.Contains(t, page, "&lt;script&gt;")
+	})
+}
+
+func TestSaveTextLanguage(t *testing.T) {
+	t.Run("language hint is stored", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id)+"?lang=golang", bytes.NewBufferString("package main"))
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(0), nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), langPrefix+id, gomock.Any(), time.Duration(0)).
+			DoAndReturn(func(_ context.Context, _ string, r io.ReadCloser, _ time.Duration) (int64, error) {
+				lang, err := io.ReadAll(r)
+				require.Equal(t, "Go", string(lang))
+				return int64(len(lang)), err
+			})
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(12), nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusOK, dep.recorder.Code)
+	})
+
+	t.Run("unknown language should return bad request", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, "hello")+"?lang=klingon", bytes.NewBufferString("qapla'"))
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusBadRequest, dep.recorder.Code)
+	})
+}
+
+func TestHeadText(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("HEAD", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{
+				ID:      prefix + id,
+				Size:    11,
+				Created: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+			}, nil)
+
+		expectUnedited(dep, id)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "11", resp.Header.Get("Content-Length"))
+		require.Equal(t, "2023-01-02T03:04:05Z", resp.Header.Get(service.HeaderCreated))
+		require.Empty(t, resp.Header.Get(service.HeaderExpires))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("HEAD", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{}, app.ErrNotFound)
+
+		expectUnedited(dep, id)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 }
 
@@ -183,6 +501,13 @@ func TestSaveText(t *testing.T) {
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		require.NoError(t, err)
 
+		var digest []byte
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			DoAndReturn(func(_ context.Context, _ string, r io.ReadCloser, _ time.Duration) (int64, error) {
+				digest, err = io.ReadAll(r)
+				return int64(len(digest)), err
+			})
 		dep.mockBackend.EXPECT().
 			SaveTTL(gomock.Any(), prefix+id, r.Body, time.Duration(0)).
 			Return(int64(len(txt)), nil)
@@ -191,11 +516,16 @@ func TestSaveText(t *testing.T) {
 
 		resp := dep.recorder.Result()
 		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.True(t, service.VerifySecret(resp.Header.Get(service.HeaderSecret), digest))
 
-		var ret response.V1Response
+		var ret struct {
+			Result service.Saved
+		}
 		err = json.NewDecoder(resp.Body).Decode(&ret)
 		require.NoError(t, err)
-		require.Equal(t, service.Ret(dep.baseURL, prefix, id), ret.Result)
+		require.Equal(t, service.Ret(dep.baseURL, prefix, id), ret.Result.URL)
+		require.Nil(t, ret.Result.Expires)
+		require.Empty(t, resp.Header.Get(service.HeaderExpires))
 	})
 
 	t.Run("conflicting id should return conflict", func(t *testing.T) {
@@ -210,7 +540,7 @@ func TestSaveText(t *testing.T) {
 		require.NoError(t, err)
 
 		dep.mockBackend.EXPECT().
-			SaveTTL(gomock.Any(), prefix+id, r.Body, time.Duration(0)).
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
 			Return(int64(0), app.ErrConflict)
 
 		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
@@ -230,10 +560,18 @@ func TestSaveText(t *testing.T) {
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		require.NoError(t, err)
 
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(0), nil)
 		dep.mockBackend.EXPECT().
 			SaveTTL(gomock.Any(), prefix+id, r.Body, time.Duration(0)).
 			Return(int64(0), fmt.Errorf("error"))
 
+		// secret should be removed as the paste was not saved
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+
 		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
 
 		resp := dep.recorder.Result()
@@ -258,7 +596,7 @@ func TestSaveText(t *testing.T) {
 
 	})
 
-	t.Run("should reject non x-www-form-urlencoded request", func(t *testing.T) {
+	t.Run("json without content should return bad request", func(t *testing.T) {
 		dep, finish := getFixtures(t)
 		defer finish()
 
@@ -282,7 +620,7 @@ func TestSaveText(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
-	t.Run("ttl request should be validated at router level", func(t *testing.T) {
+	t.Run("invalid ttl should return bad request", func(t *testing.T) {
 		dep, finish := getFixtures(t)
 		defer finish()
 
@@ -294,7 +632,7 @@ func TestSaveText(t *testing.T) {
 
 		resp := dep.recorder.Result()
 
-		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
 	t.Run("ttl request should work", func(t *testing.T) {
@@ -309,6 +647,9 @@ func TestSaveText(t *testing.T) {
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		require.NoError(t, err)
 
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Second*time.Duration(ttl)).
+			Return(int64(0), nil)
 		dep.mockBackend.EXPECT().
 			SaveTTL(gomock.Any(), prefix+id, r.Body, time.Second*time.Duration(ttl)).
 			Return(int64(len(txt)), nil)
@@ -320,3 +661,1182 @@ func TestSaveText(t *testing.T) {
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 }
+
+func TestSaveTextBodies(t *testing.T) {
+	// expectSave expects the paste to be saved with the content and ttl
+	expectSave := func(t *testing.T, dep *testDependencies, id, content string, ttl time.Duration) {
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), ttl).
+			Return(int64(0), nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), ttl).
+			DoAndReturn(func(_ context.Context, _ string, r io.ReadCloser, _ time.Duration) (int64, error) {
+				buf, err := io.ReadAll(r)
+				require.Equal(t, content, string(buf))
+				return int64(len(buf)), err
+			})
+	}
+
+	save := func(t *testing.T, dep *testDependencies, id, contentType string, body io.Reader) *http.Response {
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), body)
+		require.NoError(t, err)
+		if contentType != "" {
+			r.Header.Add("Content-Type", contentType)
+		}
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		return dep.recorder.Result()
+	}
+
+	t.Run("text types are saved raw", func(t *testing.T) {
+		for _, contentType := range []string{"text/plain", "text/plain; charset=utf-8", "text/markdown", ""} {
+			dep, finish := getFixtures(t)
+
+			expectSave(t, dep, "hello", "# hello", 0)
+			require.Equal(t, http.StatusOK, save(t, dep, "hello", contentType, bytes.NewBufferString("# hello")).StatusCode, contentType)
+
+			finish()
+		}
+	})
+
+	t.Run("json with content, ttl and language", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), langPrefix+id, gomock.Any(), time.Hour).
+			Return(int64(2), nil)
+		expectSave(t, dep, id, "package main", time.Hour)
+
+		body := bytes.NewBufferString(`{"content":"package main","ttl":"1h","language":"golang"}`)
+		require.Equal(t, http.StatusOK, save(t, dep, id, "application/json", body).StatusCode)
+	})
+
+	t.Run("json with invalid ttl or language should return bad request", func(t *testing.T) {
+		for _, body := range []string{
+			`{"content":"hi","ttl":"soon"}`,
+			`{"content":"hi","language":"klingon"}`,
+			`{"content":`,
+		} {
+			dep, finish := getFixtures(t)
+
+			require.Equal(t, http.StatusBadRequest, save(t, dep, "hello", "application/json", bytes.NewBufferString(body)).StatusCode, body)
+
+			finish()
+		}
+	})
+
+	t.Run("multipart text field", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		require.NoError(t, form.WriteField("other", "ignored"))
+		require.NoError(t, form.WriteField(textField, "hello world"))
+		require.NoError(t, form.Close())
+
+		expectSave(t, dep, "hello", "hello world", 0)
+		require.Equal(t, http.StatusOK, save(t, dep, "hello", form.FormDataContentType(), &body).StatusCode)
+	})
+
+	t.Run("multipart without text field should return bad request", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		require.NoError(t, form.WriteField("other", "hello world"))
+		require.NoError(t, form.Close())
+
+		require.Equal(t, http.StatusBadRequest, save(t, dep, "hello", form.FormDataContentType(), &body).StatusCode)
+	})
+
+	t.Run("unsupported type should return unsupported media type", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		resp := save(t, dep, "hello", "image/png", bytes.NewBufferString("\x89PNG"))
+		require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+}
+
+func TestSaveTextTTL(t *testing.T) {
+	tests := []struct {
+		name   string
+		uri    string
+		header string
+		ttl    time.Duration
+	}{
+		{"duration in path", service.Prefix(prefix, "hello/7d"), "", time.Hour * 24 * 7},
+		{"duration in query", service.Prefix(prefix, "hello?ttl=90m"), "", time.Minute * 90},
+		{"duration in header", service.Prefix(prefix, "hello"), "1h", time.Hour},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dep, finish := getFixtures(t)
+			defer finish()
+
+			id := "hello"
+
+			r, err := http.NewRequest("PUT", tc.uri, bytes.NewBufferString("hello world"))
+			require.NoError(t, err)
+			r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+			if tc.header != "" {
+				r.Header.Add(service.HeaderExpires, tc.header)
+			}
+
+			dep.mockBackend.EXPECT().
+				SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), tc.ttl).
+				Return(int64(0), nil)
+			dep.mockBackend.EXPECT().
+				SaveTTL(gomock.Any(), prefix+id, gomock.Any(), tc.ttl).
+				Return(int64(11), nil)
+
+			dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+			resp := dep.recorder.Result()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+
+	t.Run("expiration in the past should return bad request", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, "hello?expires=2006-01-02T15:04:05Z"), bytes.NewBufferString("hello world"))
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestSaveTextMaxSize(t *testing.T) {
+	t.Run("declared length beyond limit should be rejected early", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.MaxSize = 8
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, "hello"), bytes.NewBufferString("hello world"))
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+
+	t.Run("streamed body beyond limit should be rejected and cleaned up", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.MaxSize = 8
+		id := "hello"
+
+		// unknown length, as with chunked encoding
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), io.NopCloser(bytes.NewBufferString("hello world")))
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		require.Equal(t, int64(0), r.ContentLength)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(0), nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			DoAndReturn(func(_ context.Context, _ string, r io.ReadCloser, _ time.Duration) (int64, error) {
+				return io.Copy(io.Discard, r)
+			})
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+
+	t.Run("exceeding quota should return insufficient storage", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), bytes.NewBufferString("hello world"))
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(0), nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(0), app.ErrQuotaExceeded)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusInsufficientStorage, resp.StatusCode)
+	})
+}
+
+func TestTouchText(t *testing.T) {
+	secret, digest, err := service.NewSecret()
+	require.NoError(t, err)
+
+	t.Run("happy path", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		ttl := 60
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, fmt.Sprintf("%s/%d", id, ttl)), nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, secret)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(io.NopCloser(bytes.NewReader(digest)), nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), prefix+id, time.Second*time.Duration(ttl)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), secretPrefix+id, time.Second*time.Duration(ttl)).
+			Return(nil)
+		// saved without a language hint
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), langPrefix+id, time.Second*time.Duration(ttl)).
+			Return(app.ErrNotFound)
+		// never edited
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), revPrefix+id, time.Second*time.Duration(ttl)).
+			Return(app.ErrNotFound)
+
+		expectUnedited(dep, id)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("invalid secret should be forbidden", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(io.NopCloser(bytes.NewReader(digest)), nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("paste gone while secret remains", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		r, err := http.NewRequest("PATCH", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+		r.Header.Set(service.HeaderSecret, secret)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), secretPrefix+id).
+			Return(io.NopCloser(bytes.NewReader(digest)), nil)
+		dep.mockBackend.EXPECT().
+			Touch(gomock.Any(), prefix+id, time.Duration(0)).
+			Return(app.ErrNotFound)
+
+		expectUnedited(dep, id)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestExpiredEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backend, err := fast.NewFileFastBackend(t.TempDir())
+	require.NoError(t, err)
+	events := service.NewMockEvents(ctrl)
+
+	s, err := NewService(Options{
+		BaseURL: "http://hello",
+		Backend: backend,
+		Logger:  zaptest.NewLogger(t),
+		Events:  events,
+	})
+	require.NoError(t, err)
+
+	id := "expiring"
+	for _, key := range []string{prefix + id, secretPrefix + id} {
+		_, err = backend.SaveTTL(context.Background(), key, io.NopCloser(bytes.NewBufferString("hello")), time.Millisecond)
+		require.NoError(t, err)
+	}
+	<-time.After(time.Second)
+
+	// only the paste emits an event, not its secret
+	events.EXPECT().
+		Emit(gomock.Any(), gomock.Any()).
+		Do(func(c context.Context, e service.Event) {
+			require.Equal(t, "text.expired", e.Type)
+			require.Equal(t, id, e.ID)
+			require.Equal(t, service.Ret("http://hello", prefix, id), e.URL)
+		})
+
+	for _, key := range []string{prefix + id, secretPrefix + id} {
+		_, err = backend.Retrieve(context.Background(), key)
+		require.ErrorIs(t, err, app.ErrExpired)
+	}
+
+	r, err := http.NewRequest("GET", service.Prefix(prefix, id), nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	s.RetrieveRoute(nil).ServeHTTP(recorder, r)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestTextMetadata(t *testing.T) {
+	withMetadata := func(t *testing.T, dep *testDependencies) *app.MockRemovableBackend {
+		mockMetadata := app.NewMockRemovableBackend(gomock.NewController(t))
+		dep.service.MetadataBackend = mockMetadata
+		return mockMetadata
+	}
+
+	t.Run("metadata is stored on save", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		mockMetadata := withMetadata(t, dep)
+
+		id := "hello"
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id)+"?lang=golang&title=Hello", bytes.NewBufferString("package main"))
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Add(HeaderAuthor, "alice")
+		r.Header.Add(HeaderContentType, "text/x-go")
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(0), nil)
+		mockMetadata.EXPECT().
+			SaveTTL(gomock.Any(), metaPrefix+id, gomock.Any(), time.Duration(0)).
+			DoAndReturn(func(_ context.Context, _ string, buf []byte, _ time.Duration) error {
+				var meta Metadata
+				require.NoError(t, json.Unmarshal(buf, &meta))
+				require.Equal(t, Metadata{
+					Version:     1,
+					Title:       "Hello",
+					Language:    "Go",
+					Author:      "alice",
+					ContentType: "text/x-go; charset=utf-8",
+				}, meta)
+				return nil
+			})
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(12), nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusOK, dep.recorder.Code)
+	})
+
+	t.Run("metadata is removed on failed save", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		mockMetadata := withMetadata(t, dep)
+
+		id := "hello"
+		r, err := http.NewRequest("PUT", service.Prefix(prefix, id), bytes.NewBufferString("package main"))
+		require.NoError(t, err)
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(0), nil)
+		mockMetadata.EXPECT().
+			SaveTTL(gomock.Any(), metaPrefix+id, gomock.Any(), time.Duration(0)).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+id, gomock.Any(), time.Duration(0)).
+			Return(int64(0), fmt.Errorf("boom"))
+		mockMetadata.EXPECT().
+			Delete(gomock.Any(), metaPrefix+id).
+			Return(nil)
+		dep.mockBackend.EXPECT().
+			Delete(gomock.Any(), secretPrefix+id).
+			Return(nil)
+
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusInternalServerError, dep.recorder.Code)
+	})
+
+	t.Run("unsafe content type should return bad request", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		withMetadata(t, dep)
+
+		for _, contentType := range []string{"text/html", "image/svg+xml", "application/javascript", "not a type"} {
+			dep.recorder = httptest.NewRecorder()
+			r, err := http.NewRequest("PUT", service.Prefix(prefix, "hello"), bytes.NewBufferString("<script>alert(1)</script>"))
+			require.NoError(t, err)
+			r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+			r.Header.Add(HeaderContentType, contentType)
+
+			dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+
+			require.Equal(t, http.StatusBadRequest, dep.recorder.Code, contentType)
+		}
+	})
+
+	t.Run("raw paste has stored content type", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		mockMetadata := withMetadata(t, dep)
+
+		id := "hello"
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString(`{"a":1}`)), nil)
+		mockMetadata.EXPECT().
+			Retrieve(gomock.Any(), metaPrefix+id).
+			Return([]byte(`{"version":1,"content_type":"application/json; charset=utf-8"}`), nil)
+
+		expectUnedited(dep, id)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+		require.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+	})
+
+	t.Run("html view shows title and author", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		mockMetadata := withMetadata(t, dep)
+
+		id := "hello"
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id+".html"), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString("package main")), nil)
+		mockMetadata.EXPECT().
+			Retrieve(gomock.Any(), metaPrefix+id).
+			Return([]byte(`{"version":1,"title":"<Hello>","language":"Go","author":"alice"}`), nil)
+
+		expectUnedited(dep, id)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		resp := dep.recorder.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		buf, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(buf), "<title>&lt;Hello&gt;</title>")
+		require.Contains(t, string(buf), "by alice")
+		require.Contains(t, string(buf), `<span class="kn">package</span>`)
+	})
+
+	t.Run("info returns metadata", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		mockMetadata := withMetadata(t, dep)
+
+		id := "hello"
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id+"/info"), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{ID: prefix + id, Size: 12}, nil)
+		mockMetadata.EXPECT().
+			Retrieve(gomock.Any(), metaPrefix+id).
+			Return([]byte(`{"version":1,"title":"Hello","language":"Go"}`), nil)
+
+		expectUnedited(dep, id)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusOK, dep.recorder.Code)
+		var resp struct {
+			Result struct {
+				URL      string   `json:"url"`
+				Size     int64    `json:"size"`
+				Metadata Metadata `json:"metadata"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(dep.recorder.Body.Bytes(), &resp))
+		require.Equal(t, service.Ret(dep.baseURL, prefix, id), resp.Result.URL)
+		require.Equal(t, int64(12), resp.Result.Size)
+		require.Equal(t, "Hello", resp.Result.Metadata.Title)
+		require.Equal(t, "Go", resp.Result.Metadata.Language)
+		require.Equal(t, defaultContentType, resp.Result.Metadata.ContentType)
+	})
+
+	t.Run("info without metadata backend returns language hint", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id+"/info"), nil)
+		require.NoError(t, err)
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{ID: prefix + id, Size: 12}, nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(io.NopCloser(bytes.NewBufferString("Go")), nil)
+
+		expectUnedited(dep, id)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusOK, dep.recorder.Code)
+		require.Contains(t, dep.recorder.Body.String(), `"language":"Go"`)
+	})
+}
+
+func TestTextRevisions(t *testing.T) {
+	backend, err := fast.NewFileFastBackend(t.TempDir())
+	require.NoError(t, err)
+
+	s, err := NewService(Options{
+		BaseURL:      "http://hello",
+		Backend:      backend,
+		Logger:       zaptest.NewLogger(t),
+		MaxRevisions: 3,
+	})
+	require.NoError(t, err)
+
+	router := s.RetrieveRoute(s.SaveRoute(nil).(chi.Router))
+
+	do := func(method, path, secret, body string) *httptest.ResponseRecorder {
+		r, err := http.NewRequest(method, path, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		if secret != "" {
+			r.Header.Set(service.HeaderSecret, secret)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	id := "hello"
+	saved := do("PUT", service.Prefix(prefix, id)+"/3600", "", "first")
+	require.Equal(t, http.StatusOK, saved.Code)
+	secret := saved.Header().Get(service.HeaderSecret)
+	require.NotEmpty(t, secret)
+
+	t.Run("edit without the secret should be forbidden", func(t *testing.T) {
+		resp := do("POST", service.Prefix(prefix, id), "nope", "second")
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("edits should create revisions", func(t *testing.T) {
+		for _, edit := range []struct {
+			rev     int
+			content string
+		}{{2, "second"}, {3, "third"}} {
+			resp := do("POST", service.Prefix(prefix, id), secret, edit.content)
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var edited struct {
+				Result service.Saved `json:"result"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &edited))
+			require.Equal(t, service.Ret("http://hello", prefix, revisionRoute(id, edit.rev)), edited.Result.URL)
+			require.NotNil(t, edited.Result.Expires)
+		}
+
+		info, err := backend.Stat(context.Background(), revisionKey(id, 3))
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Hour), info.Expires, time.Minute)
+	})
+
+	t.Run("edits beyond the max revisions should conflict", func(t *testing.T) {
+		resp := do("POST", service.Prefix(prefix, id), secret, "fourth")
+		require.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("paste should show the latest revision", func(t *testing.T) {
+		resp := do("GET", service.Prefix(prefix, id), "", "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, "third", resp.Body.String())
+	})
+
+	t.Run("revisions should be retrievable", func(t *testing.T) {
+		for rev, content := range []string{"first", "second", "third"} {
+			resp := do("GET", service.Prefix(prefix, revisionRoute(id, rev+1)), "", "")
+			require.Equal(t, http.StatusOK, resp.Code)
+			require.Equal(t, content, resp.Body.String())
+		}
+
+		resp := do("GET", service.Prefix(prefix, revisionRoute(id, 4)), "", "")
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("history should list revisions", func(t *testing.T) {
+		recorder := do("GET", service.Prefix(prefix, id)+"/history", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp struct {
+			Result History `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		history := resp.Result
+		require.Equal(t, 3, history.Latest)
+		require.Len(t, history.Revisions, 3)
+		for i, revision := range history.Revisions {
+			require.Equal(t, i+1, revision.Revision)
+			require.Equal(t, service.Ret("http://hello", prefix, revisionRoute(id, i+1)), revision.URL)
+		}
+		require.EqualValues(t, len("second"), history.Revisions[1].Size)
+	})
+
+	t.Run("remove should delete revisions", func(t *testing.T) {
+		require.NoError(t, s.Remove(context.Background(), id))
+		for rev := 1; rev <= 3; rev++ {
+			_, err := backend.Stat(context.Background(), revisionKey(id, rev))
+			require.ErrorIs(t, err, app.ErrNotFound)
+		}
+		_, err := backend.Stat(context.Background(), revPrefix+id)
+		require.ErrorIs(t, err, app.ErrNotFound)
+	})
+
+	t.Run("edits should catch up with a lagging counter", func(t *testing.T) {
+		id := "lagging"
+		saved := do("PUT", service.Prefix(prefix, id), "", "first")
+		require.Equal(t, http.StatusOK, saved.Code)
+		secret := saved.Header().Get(service.HeaderSecret)
+
+		require.Equal(t, http.StatusOK, do("POST", service.Prefix(prefix, id), secret, "second").Code)
+		// as if the counter failed to update after saving the revision
+		require.NoError(t, backend.Delete(context.Background(), revPrefix+id))
+		require.Equal(t, "first", do("GET", service.Prefix(prefix, id), "", "").Body.String())
+
+		resp := do("POST", service.Prefix(prefix, id), secret, "third")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var edited struct {
+			Result service.Saved `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &edited))
+		require.Equal(t, service.Ret("http://hello", prefix, revisionRoute(id, 3)), edited.Result.URL)
+		require.Equal(t, "third", do("GET", service.Prefix(prefix, id), "", "").Body.String())
+	})
+
+	t.Run("concurrent edits should not overwrite revisions", func(t *testing.T) {
+		id := "racing"
+		saved := do("PUT", service.Prefix(prefix, id), "", "first")
+		require.Equal(t, http.StatusOK, saved.Code)
+		secret := saved.Header().Get(service.HeaderSecret)
+
+		const edits = 8
+		var wg sync.WaitGroup
+		responses := make([]*httptest.ResponseRecorder, edits)
+		for i := range responses {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				responses[i] = do("POST", service.Prefix(prefix, id), secret, fmt.Sprintf("edit %d", i))
+			}(i)
+		}
+		wg.Wait()
+
+		revisions := map[string]string{}
+		for i, resp := range responses {
+			if resp.Code == http.StatusConflict {
+				continue
+			}
+			require.Equal(t, http.StatusOK, resp.Code)
+			var edited struct {
+				Result service.Saved `json:"result"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &edited))
+			require.NotContains(t, revisions, edited.Result.URL)
+			revisions[edited.Result.URL] = fmt.Sprintf("edit %d", i)
+		}
+		require.NotEmpty(t, revisions)
+		for url, content := range revisions {
+			route := strings.TrimPrefix(url, "http://hello")
+			require.Equal(t, content, do("GET", route, "", "").Body.String())
+		}
+	})
+}
+
+func TestDiffText(t *testing.T) {
+	const before = "port: 80\nhost: a\n"
+	const after = "port: 8080\nhost: a\n"
+	const unified = "--- t-before\n+++ t-after\n@@ -1,2 +1,2 @@\n-port: 80\n+port: 8080\n host: a\n"
+
+	expectSides := func(dep *testDependencies, a, b string) {
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+a).
+			Return(io.NopCloser(bytes.NewBufferString(before)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+b).
+			Return(io.NopCloser(bytes.NewBufferString(after)), nil)
+		expectUnedited(dep, a)
+		expectUnedited(dep, b)
+	}
+
+	get := func(dep *testDependencies, uri string) *http.Response {
+		r, err := http.NewRequest("GET", uri, nil)
+		require.NoError(t, err)
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+		return dep.recorder.Result()
+	}
+
+	t.Run("raw diff", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		expectSides(dep, "before", "after")
+
+		resp := get(dep, service.Prefix(prefix, "before..after"))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/x-diff; charset=utf-8", resp.Header.Get("Content-Type"))
+		require.Equal(t, unified, dep.recorder.Body.String())
+	})
+
+	t.Run("diff query parameter", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		expectSides(dep, "before", "after")
+
+		resp := get(dep, service.Prefix(prefix, "after?diff=before"))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, unified, dep.recorder.Body.String())
+	})
+
+	t.Run("diff between revisions", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), revisionKey(id, 1)).
+			Return(io.NopCloser(bytes.NewBufferString(before)), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), revisionKey(id, 2)).
+			Return(io.NopCloser(bytes.NewBufferString(after)), nil)
+
+		resp := get(dep, service.Prefix(prefix, "hello@1..hello@2"))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, dep.recorder.Body.String(), "--- t-hello@1\n+++ t-hello@2\n")
+	})
+
+	t.Run("rendered views", func(t *testing.T) {
+		for _, view := range []string{"unified", "split"} {
+			dep, finish := getFixtures(t)
+
+			expectSides(dep, "before", "after")
+			dep.mockBackend.EXPECT().
+				Retrieve(gomock.Any(), langPrefix+"after").
+				Return(nil, app.ErrNotFound)
+
+			resp := get(dep, service.Prefix(prefix, "before..after.html?lang=yaml&view="+view))
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+			body := dep.recorder.Body.String()
+			require.Contains(t, body, `class="del"`)
+			require.Contains(t, body, `class="ins"`)
+			require.Contains(t, body, `<span class="m">8080</span>`)
+
+			finish()
+		}
+	})
+
+	t.Run("missing trailing newline should be marked", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"before").
+			Return(io.NopCloser(bytes.NewBufferString("port: 80\nhost: a")), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"after").
+			Return(io.NopCloser(bytes.NewBufferString("port: 80\nhost: a\n")), nil)
+		expectUnedited(dep, "before")
+		expectUnedited(dep, "after")
+
+		resp := get(dep, service.Prefix(prefix, "before..after"))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "--- t-before\n+++ t-after\n@@ -1,2 +1,2 @@\n port: 80\n-host: a\n\\ No newline at end of file\n+host: a\n", dep.recorder.Body.String())
+	})
+
+	t.Run("missing trailing newline should be marked when rendered", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"before").
+			Return(io.NopCloser(bytes.NewBufferString("a\n")), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"after").
+			Return(io.NopCloser(bytes.NewBufferString("a")), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+"after").
+			Return(nil, app.ErrNotFound)
+		expectUnedited(dep, "before")
+		expectUnedited(dep, "after")
+
+		resp := get(dep, service.Prefix(prefix, "before..after.html"))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body := dep.recorder.Body.String()
+		require.NotContains(t, body, "No differences")
+		require.Contains(t, body, `<span class="noeol">\ No newline at end of file</span>`)
+	})
+
+	t.Run("missing paste should return not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"before").
+			Return(nil, app.ErrNotFound)
+		expectUnedited(dep, "before")
+
+		resp := get(dep, service.Prefix(prefix, "before..after"))
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("pastes larger than diff size should be rejected", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.service.DiffSize = 4
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"before").
+			Return(io.NopCloser(bytes.NewBufferString(before)), nil)
+		expectUnedited(dep, "before")
+
+		resp := get(dep, service.Prefix(prefix, "before..after"))
+		require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+
+	t.Run("pastes with too many lines should be rejected", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"before").
+			Return(io.NopCloser(strings.NewReader(strings.Repeat("a\n", maxDiffLines+1))), nil)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+"after").
+			Return(io.NopCloser(bytes.NewBufferString(after)), nil)
+		expectUnedited(dep, "before")
+		expectUnedited(dep, "after")
+
+		resp := get(dep, service.Prefix(prefix, "before..after"))
+		require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+}
+
+func TestDiffLines(t *testing.T) {
+	// lcs returns the length of the longest common subsequence, from which the shortest edit script follows
+	lcs := func(a, b []string) int {
+		dp := make([][]int, len(a)+1)
+		for i := range dp {
+			dp[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					dp[i][j] = dp[i+1][j+1] + 1
+				} else if dp[i+1][j] > dp[i][j+1] {
+					dp[i][j] = dp[i+1][j]
+				} else {
+					dp[i][j] = dp[i][j+1]
+				}
+			}
+		}
+		return dp[0][0]
+	}
+
+	t.Run("changes should be the shortest edit script", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		lines := func() []string {
+			out := make([]string, rng.Intn(30))
+			for i := range out {
+				out[i] = string(rune('a' + rng.Intn(4)))
+			}
+			return out
+		}
+		for i := 0; i < 1000; i++ {
+			a, b := lines(), lines()
+			codes, err := diffLines(a, b, diffBudget)
+			require.NoError(t, err)
+
+			var patched []string
+			edits := 0
+			for _, c := range codes {
+				if c.Tag == 'e' {
+					require.Equal(t, a[c.I1:c.I2], b[c.J1:c.J2])
+				} else {
+					edits += c.I2 - c.I1 + c.J2 - c.J1
+				}
+				patched = append(patched, b[c.J1:c.J2]...)
+			}
+			require.Equal(t, strings.Join(b, ""), strings.Join(patched, ""), "%v..%v", a, b)
+			require.Equal(t, len(a)+len(b)-2*lcs(a, b), edits, "%v..%v", a, b)
+		}
+	})
+
+	t.Run("repeated lines should diff quickly", func(t *testing.T) {
+		var a, b []string
+		for i := 0; i < maxDiffLines; i++ {
+			a = append(a, fmt.Sprintf("line %d\n", i%7))
+			b = append(b, fmt.Sprintf("line %d\n", i*3%5))
+		}
+		_, err := diffLines(a, b, diffBudget)
+		require.NoError(t, err)
+	})
+
+	t.Run("changes beyond the budget should be rejected", func(t *testing.T) {
+		var a, b []string
+		for i := 0; i < maxDiffLines; i++ {
+			a = append(a, fmt.Sprintf("a %d\n", i))
+			b = append(b, fmt.Sprintf("b %d\n", i))
+		}
+		_, err := diffLines(a, b, diffBudget)
+		require.ErrorIs(t, err, errTooLargeToDiff)
+	})
+}
+
+func TestForkText(t *testing.T) {
+	fork := func(dep *testDependencies, uri string) *http.Response {
+		r, err := http.NewRequest("POST", uri, nil)
+		require.NoError(t, err)
+		dep.service.SaveRoute(nil).ServeHTTP(dep.recorder, r)
+		return dep.recorder.Result()
+	}
+
+	t.Run("fork should stream the paste and hint its language and parent", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+		forkID := "copy"
+		text := io.NopCloser(bytes.NewBufferString("package main"))
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(io.NopCloser(bytes.NewBufferString("Go")), nil)
+		expectUnedited(dep, id)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(text, nil)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+forkID, gomock.Any(), time.Minute).
+			Return(int64(0), nil)
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), langPrefix+forkID, gomock.Any(), time.Minute).
+			DoAndReturn(func(_ context.Context, _ string, r io.ReadCloser, _ time.Duration) (int64, error) {
+				buf, err := io.ReadAll(r)
+				require.Equal(t, "Go\n"+id, string(buf))
+				return int64(len(buf)), err
+			})
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+forkID, text, time.Minute).
+			Return(int64(12), nil)
+
+		resp := fork(dep, service.Prefix(prefix, id)+"/fork/60?id="+forkID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotEmpty(t, resp.Header.Get(service.HeaderSecret))
+
+		var ret struct {
+			Result service.Saved
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&ret))
+		require.Equal(t, service.Ret(dep.baseURL, prefix, forkID), ret.Result.URL)
+	})
+
+	t.Run("fork should generate an identifier if omitted", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), revisionKey(id, 2)).
+			Return(io.NopCloser(bytes.NewBufferString("package main")), nil)
+
+		var keys []string
+		var hint string
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), gomock.Any(), gomock.Any(), time.Duration(0)).
+			DoAndReturn(func(_ context.Context, key string, r io.ReadCloser, _ time.Duration) (int64, error) {
+				keys = append(keys, key)
+				if strings.HasPrefix(key, langPrefix) {
+					buf, err := io.ReadAll(r)
+					hint = string(buf)
+					return int64(len(buf)), err
+				}
+				return 0, nil
+			}).
+			Times(3)
+
+		resp := fork(dep, service.Prefix(prefix, id)+"@2/fork")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		require.Len(t, keys, 3)
+		forkID := strings.TrimPrefix(keys[2], prefix)
+		require.Equal(t, secretPrefix+forkID, keys[0])
+		require.Equal(t, langPrefix+forkID, keys[1])
+		require.Regexp(t, "^[a-zA-Z0-9]{10}$", forkID)
+		// forks of pastes without a language still hint their parent
+		require.Equal(t, "\n"+id+"@2", hint)
+	})
+
+	forkWithMetadata := func(t *testing.T, query string, want Metadata) {
+		dep, finish := getFixtures(t)
+		defer finish()
+		mockMetadata := app.NewMockRemovableBackend(gomock.NewController(t))
+		dep.service.MetadataBackend = mockMetadata
+
+		id := "hello"
+		forkID := "copy"
+
+		mockMetadata.EXPECT().
+			Retrieve(gomock.Any(), metaPrefix+id).
+			Return([]byte(`{"version":1,"title":"Hello","language":"Go","author":"alice"}`), nil)
+		expectUnedited(dep, id)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(io.NopCloser(bytes.NewBufferString("package main")), nil)
+
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), secretPrefix+forkID, gomock.Any(), time.Duration(0)).
+			Return(int64(0), nil)
+		mockMetadata.EXPECT().
+			SaveTTL(gomock.Any(), metaPrefix+forkID, gomock.Any(), time.Duration(0)).
+			DoAndReturn(func(_ context.Context, _ string, buf []byte, _ time.Duration) error {
+				var meta Metadata
+				require.NoError(t, json.Unmarshal(buf, &meta))
+				require.Equal(t, want, meta)
+				return nil
+			})
+		dep.mockBackend.EXPECT().
+			SaveTTL(gomock.Any(), prefix+forkID, gomock.Any(), time.Duration(0)).
+			Return(int64(12), nil)
+
+		resp := fork(dep, service.Prefix(prefix, id)+"/fork?id="+forkID+query)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	t.Run("fork should record its parent in the metadata", func(t *testing.T) {
+		// anonymous forks are not attributed to the author of the parent
+		forkWithMetadata(t, "", Metadata{Version: 1, Title: "Hello", Language: "Go", Parent: "hello"})
+	})
+
+	t.Run("fork should be attributed to the author of the request", func(t *testing.T) {
+		forkWithMetadata(t, "&author=bob", Metadata{Version: 1, Title: "Hello", Language: "Go", Author: "bob", Parent: "hello"})
+	})
+
+	t.Run("fork should take the title and language of the request", func(t *testing.T) {
+		forkWithMetadata(t, "&title=Copy&lang=python", Metadata{Version: 1, Title: "Copy", Language: "Python", Parent: "hello"})
+	})
+
+	t.Run("invalid identifier should return bad request", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		resp := fork(dep, service.Prefix(prefix, "hello")+"/fork?id=a-b")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("missing paste should return not found", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), langPrefix+id).
+			Return(nil, app.ErrNotFound)
+		expectUnedited(dep, id)
+		dep.mockBackend.EXPECT().
+			Retrieve(gomock.Any(), prefix+id).
+			Return(nil, app.ErrNotFound)
+
+		resp := fork(dep, service.Prefix(prefix, id)+"/fork")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestQRText(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		id := "hello"
+
+		dep.mockBackend.EXPECT().
+			Stat(gomock.Any(), prefix+id).
+			Return(app.Info{}, nil)
+
+		r, err := http.NewRequest("GET", service.Prefix(prefix, id+"/qr")+"?format=svg&level=q", nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusOK, dep.recorder.Code)
+		require.Equal(t, "image/svg+xml", dep.recorder.Header().Get("Content-Type"))
+	})
+
+	t.Run("invalid options should return bad request", func(t *testing.T) {
+		dep, finish := getFixtures(t)
+		defer finish()
+
+		r, err := http.NewRequest("GET", service.Prefix(prefix, "hello/qr")+"?level=z", nil)
+		require.NoError(t, err)
+
+		dep.service.RetrieveRoute(nil).ServeHTTP(dep.recorder, r)
+
+		require.Equal(t, http.StatusBadRequest, dep.recorder.Code)
+	})
+}
@@ -3,19 +3,96 @@ package service
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
 )
 
-func ParseTTL(r *http.Request) int64 {
-	ttlStr := chi.URLParam(r, "ttl")
-	var ttl int64
-	var err error
-	if ttlStr != "" {
-		ttl, err = strconv.ParseInt(ttlStr, 10, 64)
+// ErrInvalidTTL is returned by ParseTTL when the requested expiration cannot be understood
+var ErrInvalidTTL = errors.New("invalid ttl")
+
+// never requests an item that never expires, same as a ttl of 0
+const never = "never"
+
+// ParseTTL returns the requested time to live, or 0 if the item should never expire, and
+// whether a ttl was requested at all.
+// The ttl is taken from, in order of precedence, the "ttl" URL parameter, the "ttl"
+// or "expires" query string, or the X-B-Expires header. Each accepts seconds (60),
+// a duration with optional days (90m, 7d, 1d12h), an RFC 3339 timestamp in the future,
+// or "never"
+func ParseTTL(r *http.Request) (time.Duration, bool, error) {
+	sources := []string{
+		chi.URLParam(r, "ttl"),
+		r.URL.Query().Get("ttl"),
+		r.URL.Query().Get("expires"),
+		r.Header.Get(HeaderExpires),
+	}
+	for _, v := range sources {
+		if v != "" {
+			ttl, err := parseTTL(v, time.Now())
+			return ttl, true, err
+		}
+	}
+	return 0, false, nil
+}
+
+// ParseTTLValue parses a ttl given outside of the request, e.g. in a JSON body, in any form
+// accepted by ParseTTL
+func ParseTTLValue(v string) (time.Duration, error) {
+	return parseTTL(v, time.Now())
+}
+
+func parseTTL(v string, now time.Time) (time.Duration, error) {
+	if v == never {
+		return 0, nil
+	}
+
+	if secs, err := strconv.ParseUint(v, 10, 32); err == nil {
+		return time.Second * time.Duration(secs), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		ttl := t.Sub(now)
+		if ttl <= 0 {
+			return 0, errors.Wrapf(ErrInvalidTTL, "%s is in the past", v)
+		}
+		return ttl, nil
+	}
+
+	ttl, err := ParseDuration(v)
+	if err != nil {
+		return 0, errors.Wrapf(ErrInvalidTTL, "%s is neither seconds, a duration nor an RFC 3339 timestamp", v)
+	}
+	if ttl < 0 {
+		return 0, errors.Wrapf(ErrInvalidTTL, "%s is negative", v)
+	}
+	return ttl, nil
+}
+
+// ParseDuration extends time.ParseDuration with a leading day unit, e.g. 7d or 1d12h
+func ParseDuration(v string) (time.Duration, error) {
+	i := strings.IndexByte(v, 'd')
+	if i < 0 {
+		return time.ParseDuration(v)
+	}
+
+	days, err := strconv.ParseUint(v[:i], 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	ttl := time.Hour * 24 * time.Duration(days)
+
+	if rest := v[i+1:]; rest != "" {
+		d, err := time.ParseDuration(rest)
 		if err != nil {
-			ttl = 0
+			return 0, err
+		}
+		if d < 0 {
+			return 0, errors.New("mixed signs")
 		}
+		ttl += d
 	}
-	return ttl
+	return ttl, nil
 }
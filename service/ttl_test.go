@@ -0,0 +1,104 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTTL(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	valid := map[string]time.Duration{
+		"0":                    0,
+		"60":                   time.Minute,
+		"90m":                  time.Minute * 90,
+		"7d":                   time.Hour * 24 * 7,
+		"1d12h":                time.Hour * 36,
+		"0s":                   0,
+		"never":                0,
+		"2023-01-02T04:04:05Z": time.Hour,
+	}
+	for v, expected := range valid {
+		ttl, err := parseTTL(v, now)
+		require.NoError(t, err, v)
+		require.Equal(t, expected, ttl, v)
+	}
+
+	invalid := []string{
+		"abc",
+		"-60",
+		"-5m",
+		"1d-5h",
+		"d",
+		"2023-01-02T02:04:05Z",
+		"2023-01-02",
+	}
+	for _, v := range invalid {
+		_, err := parseTTL(v, now)
+		require.ErrorIs(t, err, ErrInvalidTTL, v)
+	}
+}
+
+func TestParseTTLSources(t *testing.T) {
+	parse := func(t *testing.T, url string, header string) (time.Duration, bool, error) {
+		var ttl time.Duration
+		var requested bool
+		var err error
+		r := chi.NewRouter()
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			ttl, requested, err = ParseTTL(r)
+		}
+		r.Put("/t-{id}/{ttl}", handler)
+		r.Put("/t-{id}", handler)
+
+		req, reqErr := http.NewRequest("PUT", url, nil)
+		require.NoError(t, reqErr)
+		if header != "" {
+			req.Header.Set(HeaderExpires, header)
+		}
+		r.ServeHTTP(nil, req)
+		return ttl, requested, err
+	}
+
+	t.Run("path takes precedence", func(t *testing.T) {
+		ttl, requested, err := parse(t, "/t-hello/1h?ttl=2h", "3h")
+		require.NoError(t, err)
+		require.True(t, requested)
+		require.Equal(t, time.Hour, ttl)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		ttl, _, err := parse(t, "/t-hello?ttl=2h", "3h")
+		require.NoError(t, err)
+		require.Equal(t, time.Hour*2, ttl)
+	})
+
+	t.Run("header", func(t *testing.T) {
+		ttl, _, err := parse(t, "/t-hello", "3h")
+		require.NoError(t, err)
+		require.Equal(t, time.Hour*3, ttl)
+	})
+
+	t.Run("absent is not requested", func(t *testing.T) {
+		ttl, requested, err := parse(t, "/t-hello", "")
+		require.NoError(t, err)
+		require.False(t, requested)
+		require.Equal(t, time.Duration(0), ttl)
+	})
+
+	t.Run("explicit never is requested", func(t *testing.T) {
+		ttl, requested, err := parse(t, "/t-hello?ttl=never", "")
+		require.NoError(t, err)
+		require.True(t, requested)
+		require.Equal(t, time.Duration(0), ttl)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := parse(t, "/t-hello?expires=tomorrow", "")
+		require.ErrorIs(t, err, ErrInvalidTTL)
+	})
+}
@@ -0,0 +1,347 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/zllovesuki/b/account"
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/response"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type Options struct {
+	Accounts *account.Store
+	Logger   *zap.Logger
+	// Items are services whose items can be managed by their owners
+	Items []service.Items
+}
+
+type Service struct {
+	Options
+	items map[string]service.Items
+}
+
+func (o *Options) validate() error {
+	if o.Accounts == nil {
+		return errors.New("missing account store")
+	}
+	if o.Logger == nil {
+		return errors.New("missing logger")
+	}
+	return nil
+}
+
+func NewService(option Options) (*Service, error) {
+	if err := option.validate(); err != nil {
+		return nil, err
+	}
+	items := make(map[string]service.Items, len(option.Items))
+	for _, i := range option.Items {
+		items[i.Kind()] = i
+	}
+	return &Service{
+		Options: option,
+		items:   items,
+	}, nil
+}
+
+// logger returns the request-scoped logger if available
+func (s *Service) logger(r *http.Request) *zap.Logger {
+	return service.Logger(r, s.Logger)
+}
+
+// requireUser rejects anonymous requests
+func requireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if service.User(r) == "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="b"`)
+			response.WriteError(w, r, response.ErrUnauthorized().AddMessages("Authenticate with your username and password, or an API key"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Service) me(w http.ResponseWriter, r *http.Request) {
+	u, err := s.Accounts.User(r.Context(), service.User(r))
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("User not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve user", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve user"))
+		return
+	}
+	u.Password = nil
+
+	response.WriteResponse(w, r, u)
+}
+
+// itemsOf returns the service of the kind in the url, or writes 404 if none
+func (s *Service) itemsOf(w http.ResponseWriter, r *http.Request) service.Items {
+	items, ok := s.items[chi.URLParam(r, "kind")]
+	if !ok {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Unknown kind of items"))
+		return nil
+	}
+	return items
+}
+
+func (s *Service) listItems(w http.ResponseWriter, r *http.Request) {
+	items := s.itemsOf(w, r)
+	if items == nil {
+		return
+	}
+	page, perr := service.ParsePage(r)
+	if perr != nil {
+		response.WriteError(w, r, perr)
+		return
+	}
+
+	ids, next, err := s.Accounts.Owned(r.Context(), service.User(r), items.Kind(), page.Cursor, page.Limit)
+	if err != nil {
+		s.logger(r).Error("unable to list owned items", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to list items"))
+		return
+	}
+
+	listing := service.Listing{
+		Items:  make([]service.Entry, 0, len(ids)),
+		Cursor: next,
+	}
+	for _, id := range ids {
+		entry, err := items.Inspect(r.Context(), id)
+		if errors.Is(err, app.ErrNotFound) {
+			// removed since owned
+			continue
+		} else if err != nil {
+			s.logger(r).Error("unable to inspect owned item", zap.Error(err), zap.String("id", id))
+			response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to list items"))
+			return
+		}
+		listing.Items = append(listing.Items, entry)
+	}
+
+	response.WriteResponse(w, r, listing)
+}
+
+// owned checks that the item in the url is owned by the user, or writes 404 if not.
+// Items of others are reported as not found to avoid disclosing their existence
+func (s *Service) owned(w http.ResponseWriter, r *http.Request) (service.Items, string) {
+	items := s.itemsOf(w, r)
+	if items == nil {
+		return nil, ""
+	}
+	id := chi.URLParam(r, "id")
+
+	owner, err := s.Accounts.Owner(r.Context(), items.Kind(), id)
+	if errors.Is(err, app.ErrNotFound) || (err == nil && owner != service.User(r)) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Item either expired or not found"))
+		return nil, ""
+	} else if err != nil {
+		s.logger(r).Error("unable to retrieve owner", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to retrieve item"))
+		return nil, ""
+	}
+	return items, id
+}
+
+func (s *Service) touchItem(w http.ResponseWriter, r *http.Request) {
+	items, id := s.owned(w, r)
+	if items == nil {
+		return
+	}
+	ttl, err := items.Policy().ParseExtend(r)
+	if err != nil {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	}
+
+	saved, err := items.Touch(r.Context(), id, ttl)
+	if errors.Is(err, app.ErrNotFound) {
+		response.WriteError(w, r, response.ErrNotFound().AddMessages("Item either expired or not found"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to extend owned item", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to extend item"))
+		return
+	}
+
+	service.WriteSaved(w, r, saved)
+}
+
+func (s *Service) deleteItem(w http.ResponseWriter, r *http.Request) {
+	items, id := s.owned(w, r)
+	if items == nil {
+		return
+	}
+
+	if err := items.Remove(r.Context(), id); err != nil {
+		s.logger(r).Error("unable to delete owned item", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to delete item"))
+		return
+	}
+
+	response.WriteResponse(w, r, id)
+}
+
+type CreateKeyReq struct {
+	Name string `json:"name"`
+}
+
+type CreateKeyRes struct {
+	*account.Key
+	// Token is the API key, which cannot be retrieved again
+	Token string `json:"token"`
+}
+
+func (s *Service) createKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateKeyReq
+	service.LimitBody(w, r, 4096)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, r, response.ErrInvalidJson())
+		return
+	}
+
+	token, key, err := s.Accounts.CreateKey(r.Context(), service.User(r), req.Name)
+	if err != nil {
+		s.logger(r).Error("unable to create api key", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to create API key"))
+		return
+	}
+
+	response.WriteResponse(w, r, CreateKeyRes{
+		Key:   key,
+		Token: token,
+	})
+}
+
+func (s *Service) listKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := s.Accounts.ListKeys(r.Context(), service.User(r))
+	if err != nil {
+		s.logger(r).Error("unable to list api keys", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to list API keys"))
+		return
+	}
+
+	response.WriteResponse(w, r, keys)
+}
+
+func (s *Service) deleteKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "key")
+
+	if err := s.Accounts.DeleteKey(r.Context(), service.User(r), id); err != nil {
+		s.logger(r).Error("unable to delete api key", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to delete API key"))
+		return
+	}
+
+	response.WriteResponse(w, r, id)
+}
+
+type CreateUserReq struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (s *Service) createUser(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserReq
+	service.LimitBody(w, r, 4096)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, r, response.ErrInvalidJson())
+		return
+	}
+
+	u, err := s.Accounts.CreateUser(r.Context(), req.Username, req.Password)
+	if errors.Is(err, account.ErrInvalidUsername) || errors.Is(err, account.ErrInvalidPassword) {
+		response.WriteError(w, r, response.ErrBadRequest().AddMessages(err.Error()))
+		return
+	} else if errors.Is(err, app.ErrConflict) {
+		response.WriteError(w, r, response.ErrConflict().AddMessages("Username is taken"))
+		return
+	} else if err != nil {
+		s.logger(r).Error("unable to create user", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to create user"))
+		return
+	}
+
+	response.WriteResponse(w, r, u)
+}
+
+func (s *Service) listUsers(w http.ResponseWriter, r *http.Request) {
+	page, perr := service.ParsePage(r)
+	if perr != nil {
+		response.WriteError(w, r, perr)
+		return
+	}
+
+	users, next, err := s.Accounts.ListUsers(r.Context(), page.Prefix, page.Cursor, page.Limit)
+	if err != nil {
+		s.logger(r).Error("unable to list users", zap.Error(err))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to list users"))
+		return
+	}
+
+	response.WriteResponse(w, r, struct {
+		Users  []account.User `json:"users"`
+		Cursor string         `json:"cursor"`
+	}{
+		Users:  users,
+		Cursor: next,
+	})
+}
+
+func (s *Service) deleteUser(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := s.Accounts.DeleteUser(r.Context(), id); err != nil {
+		s.logger(r).Error("unable to delete user", zap.Error(err), zap.String("id", id))
+		response.WriteError(w, r, response.ErrUnexpected().AddMessages("Unable to delete user"))
+		return
+	}
+
+	response.WriteResponse(w, r, id)
+}
+
+// Route returns a mountable router for users to manage their API keys and own items.
+// Alternatively, it can mount directly to the provided router.
+// account.Middleware should be installed before this router
+func (s *Service) Route(r chi.Router) http.Handler {
+	if r == nil {
+		r = chi.NewRouter()
+	}
+
+	r.Route("/me", func(r chi.Router) {
+		r.Use(requireUser)
+
+		r.Get("/", s.me)
+		r.Get("/keys", s.listKeys)
+		r.Post("/keys", s.createKey)
+		r.Delete("/keys/{key:[a-f0-9]+}", s.deleteKey)
+		r.Get("/{kind:[a-z]+}", s.listItems)
+		r.Patch("/{kind:[a-z]+}/{id:[a-zA-Z0-9]+}/{ttl}", s.touchItem)
+		r.Patch("/{kind:[a-z]+}/{id:[a-zA-Z0-9]+}", s.touchItem)
+		r.Delete("/{kind:[a-z]+}/{id:[a-zA-Z0-9]+}", s.deleteItem)
+	})
+
+	return r
+}
+
+// AdminRoute returns a mountable router for listing, creating and deleting users.
+// Alternatively, it can mount directly to the provided router.
+func (s *Service) AdminRoute(r chi.Router) http.Handler {
+	if r == nil {
+		r = chi.NewRouter()
+	}
+
+	r.Get("/users", s.listUsers)
+	r.Post("/users", s.createUser)
+	r.Delete("/users/{id:[a-zA-Z0-9]+}", s.deleteUser)
+
+	return r
+}
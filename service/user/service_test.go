@@ -0,0 +1,191 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zllovesuki/b/account"
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/backend"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeItems keeps items in memory, with the expiration of each
+type fakeItems map[string]time.Duration
+
+var _ service.Items = fakeItems{}
+
+func (f fakeItems) Kind() string {
+	return "link"
+}
+
+func (f fakeItems) Policy() *service.TTLPolicy {
+	return nil
+}
+
+func (f fakeItems) Inspect(c context.Context, id string) (service.Entry, error) {
+	if _, ok := f[id]; !ok {
+		return service.Entry{}, app.ErrNotFound
+	}
+	return service.Entry{ID: id}, nil
+}
+
+func (f fakeItems) Touch(c context.Context, id string, ttl time.Duration) (service.Saved, error) {
+	if _, ok := f[id]; !ok {
+		return service.Saved{}, app.ErrNotFound
+	}
+	f[id] = ttl
+	return service.NewSaved("http://hello", "l-", id, ttl), nil
+}
+
+func (f fakeItems) Remove(c context.Context, id string) error {
+	delete(f, id)
+	return nil
+}
+
+type testDependencies struct {
+	accounts *account.Store
+	items    fakeItems
+	router   chi.Router
+}
+
+func getFixtures(t *testing.T) (*testDependencies, func()) {
+	b, err := backend.NewSQLiteBackend(filepath.Join(t.TempDir(), "user.db"))
+	require.NoError(t, err)
+
+	accounts, err := account.NewStore(b)
+	require.NoError(t, err)
+
+	logger := zaptest.NewLogger(t)
+	items := fakeItems{}
+
+	s, err := NewService(Options{
+		Accounts: accounts,
+		Logger:   logger,
+		Items:    []service.Items{items},
+	})
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(account.Middleware(accounts, logger))
+	s.Route(r)
+	r.Route("/api", func(r chi.Router) {
+		s.AdminRoute(r)
+	})
+
+	return &testDependencies{
+		accounts: accounts,
+		items:    items,
+		router:   r,
+	}, func() {
+		require.NoError(t, b.Close())
+	}
+}
+
+func (dep *testDependencies) do(t *testing.T, method, path, body string, auth func(*http.Request)) *http.Response {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if auth != nil {
+		auth(r)
+	}
+	recorder := httptest.NewRecorder()
+	dep.router.ServeHTTP(recorder, r)
+	return recorder.Result()
+}
+
+func basic(username, password string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.SetBasicAuth(username, password)
+	}
+}
+
+func TestMe(t *testing.T) {
+	dep, finish := getFixtures(t)
+	defer finish()
+
+	resp := dep.do(t, "POST", "/api/users", `{"username":"alice","password":"correct horse"}`, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = dep.do(t, "POST", "/api/users", `{"username":"alice","password":"correct horse"}`, nil)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp = dep.do(t, "POST", "/api/users", `{"username":"bob","password":"short"}`, nil)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	t.Run("anonymous should return unauthorized", func(t *testing.T) {
+		resp := dep.do(t, "GET", "/me/", "", nil)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+	})
+
+	t.Run("api key can be used instead of password", func(t *testing.T) {
+		resp := dep.do(t, "POST", "/me/keys", `{"name":"laptop"}`, basic("alice", "correct horse"))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var ret struct {
+			Result CreateKeyRes
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&ret))
+		require.NotEmpty(t, ret.Result.Token)
+
+		bearer := func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer "+ret.Result.Token)
+		}
+		resp = dep.do(t, "GET", "/me/", "", bearer)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = dep.do(t, "DELETE", "/me/keys/"+ret.Result.ID, "", bearer)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = dep.do(t, "GET", "/me/", "", bearer)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("manage own items only", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := dep.accounts.CreateUser(ctx, "bob", "battery staple")
+		require.NoError(t, err)
+
+		dep.items["mine"] = 0
+		dep.items["theirs"] = 0
+		require.NoError(t, dep.accounts.Own(ctx, "link", "mine", "alice", 0))
+		require.NoError(t, dep.accounts.Own(ctx, "link", "theirs", "bob", 0))
+
+		alice := basic("alice", "correct horse")
+
+		resp := dep.do(t, "GET", "/me/link", "", alice)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var ret struct {
+			Result service.Listing
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&ret))
+		require.Len(t, ret.Result.Items, 1)
+		require.Equal(t, "mine", ret.Result.Items[0].ID)
+
+		resp = dep.do(t, "GET", "/me/file", "", alice)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp = dep.do(t, "PATCH", "/me/link/mine/1h", "", alice)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, time.Hour, dep.items["mine"])
+
+		resp = dep.do(t, "PATCH", "/me/link/theirs/1h", "", alice)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		require.Equal(t, time.Duration(0), dep.items["theirs"])
+
+		resp = dep.do(t, "DELETE", "/me/link/theirs", "", alice)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		require.Contains(t, dep.items, "theirs")
+
+		resp = dep.do(t, "DELETE", "/me/link/mine", "", alice)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotContains(t, dep.items, "mine")
+	})
+}
@@ -0,0 +1,245 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// span attributes attached to backend operations
+const (
+	backendKey    = attribute.Key("b.backend")
+	identifierKey = attribute.Key("b.identifier")
+	ttlKey        = attribute.Key("b.ttl")
+	resultKey     = attribute.Key("b.result")
+	bytesKey      = attribute.Key("b.bytes")
+)
+
+func start(c context.Context, kind, op, identifier string) (context.Context, trace.Span) {
+	return tracer().Start(c, fmt.Sprintf("%s.%s", kind, op),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			backendKey.String(kind),
+			identifierKey.String(identifier),
+			RequestIDKey.String(middleware.GetReqID(c)),
+		),
+	)
+}
+
+// finish records the outcome of the operation. Conflict and not found are
+// expected outcomes, so they are recorded as result instead of span error
+func finish(span trace.Span, err error) {
+	switch {
+	case err == nil:
+		span.SetAttributes(resultKey.String("ok"))
+	case errors.Is(err, app.ErrConflict):
+		span.SetAttributes(resultKey.String("conflict"))
+	case errors.Is(err, app.ErrExpired):
+		span.SetAttributes(resultKey.String("expired"))
+	case errors.Is(err, app.ErrNotFound):
+		span.SetAttributes(resultKey.String("not_found"))
+	default:
+		span.SetAttributes(resultKey.String("error"))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Backend wraps an app.RemovableBackend to create a span for every operation
+type Backend struct {
+	kind    string
+	backend app.RemovableBackend
+}
+
+var _ app.RemovableBackend = &Backend{}
+
+// NewBackend returns a traced app.RemovableBackend. kind is used as span name prefix (e.g. sqlite.retrieve)
+func NewBackend(kind string, b app.RemovableBackend) *Backend {
+	return &Backend{
+		kind:    kind,
+		backend: b,
+	}
+}
+
+func (b *Backend) SaveTTL(c context.Context, identifier string, data []byte, ttl time.Duration) error {
+	c, span := start(c, b.kind, "save", identifier)
+	span.SetAttributes(ttlKey.String(ttl.String()), bytesKey.Int(len(data)))
+	err := b.backend.SaveTTL(c, identifier, data, ttl)
+	finish(span, err)
+	return err
+}
+
+func (b *Backend) Overwrite(c context.Context, identifier string, data []byte, ttl time.Duration) error {
+	c, span := start(c, b.kind, "overwrite", identifier)
+	span.SetAttributes(ttlKey.String(ttl.String()), bytesKey.Int(len(data)))
+	err := b.backend.Overwrite(c, identifier, data, ttl)
+	finish(span, err)
+	return err
+}
+
+func (b *Backend) Retrieve(c context.Context, identifier string) ([]byte, error) {
+	c, span := start(c, b.kind, "retrieve", identifier)
+	data, err := b.backend.Retrieve(c, identifier)
+	span.SetAttributes(bytesKey.Int(len(data)))
+	finish(span, err)
+	return data, err
+}
+
+func (b *Backend) Stat(c context.Context, identifier string) (app.Info, error) {
+	c, span := start(c, b.kind, "stat", identifier)
+	info, err := b.backend.Stat(c, identifier)
+	span.SetAttributes(bytesKey.Int64(info.Size))
+	finish(span, err)
+	return info, err
+}
+
+func (b *Backend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	c, span := start(c, b.kind, "touch", identifier)
+	span.SetAttributes(ttlKey.String(ttl.String()))
+	err := b.backend.Touch(c, identifier, ttl)
+	finish(span, err)
+	return err
+}
+
+func (b *Backend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	c, span := start(c, b.kind, "list", prefix)
+	items, next, err := b.backend.List(c, prefix, cursor, limit)
+	finish(span, err)
+	return items, next, err
+}
+
+func (b *Backend) Delete(c context.Context, identifier string) error {
+	c, span := start(c, b.kind, "delete", identifier)
+	err := b.backend.Delete(c, identifier)
+	finish(span, err)
+	return err
+}
+
+func (b *Backend) Close() error {
+	return b.backend.Close()
+}
+
+// FastBackend wraps an app.RemovableFastBackend to create a span for every operation.
+// Readers returned from Retrieve are traced as well until they are closed
+type FastBackend struct {
+	kind    string
+	backend app.RemovableFastBackend
+}
+
+var _ app.RemovableFastBackend = &FastBackend{}
+var _ app.ExpiryNotifier = &FastBackend{}
+
+// NewFastBackend returns a traced app.RemovableFastBackend. kind is used as span name prefix (e.g. s3.retrieve)
+func NewFastBackend(kind string, f app.RemovableFastBackend) *FastBackend {
+	return &FastBackend{
+		kind:    kind,
+		backend: f,
+	}
+}
+
+func (f *FastBackend) SaveTTL(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
+	c, span := start(c, f.kind, "save", identifier)
+	span.SetAttributes(ttlKey.String(ttl.String()))
+	written, err := f.backend.SaveTTL(c, identifier, r, ttl)
+	span.SetAttributes(bytesKey.Int64(written))
+	finish(span, err)
+	return written, err
+}
+
+func (f *FastBackend) Overwrite(c context.Context, identifier string, r io.ReadCloser, ttl time.Duration) (int64, error) {
+	c, span := start(c, f.kind, "overwrite", identifier)
+	span.SetAttributes(ttlKey.String(ttl.String()))
+	written, err := f.backend.Overwrite(c, identifier, r, ttl)
+	span.SetAttributes(bytesKey.Int64(written))
+	finish(span, err)
+	return written, err
+}
+
+func (f *FastBackend) Retrieve(c context.Context, identifier string) (io.ReadCloser, error) {
+	ctx, span := start(c, f.kind, "retrieve", identifier)
+	r, err := f.backend.Retrieve(ctx, identifier)
+	finish(span, err)
+	if err != nil {
+		return r, err
+	}
+	_, stream := start(c, f.kind, "stream", identifier)
+	return &tracedReader{ReadCloser: r, span: stream}, nil
+}
+
+func (f *FastBackend) Stat(c context.Context, identifier string) (app.Info, error) {
+	c, span := start(c, f.kind, "stat", identifier)
+	info, err := f.backend.Stat(c, identifier)
+	span.SetAttributes(bytesKey.Int64(info.Size))
+	finish(span, err)
+	return info, err
+}
+
+func (f *FastBackend) Touch(c context.Context, identifier string, ttl time.Duration) error {
+	c, span := start(c, f.kind, "touch", identifier)
+	span.SetAttributes(ttlKey.String(ttl.String()))
+	err := f.backend.Touch(c, identifier, ttl)
+	finish(span, err)
+	return err
+}
+
+func (f *FastBackend) List(c context.Context, prefix, cursor string, limit int) ([]app.Info, string, error) {
+	c, span := start(c, f.kind, "list", prefix)
+	items, next, err := f.backend.List(c, prefix, cursor, limit)
+	finish(span, err)
+	return items, next, err
+}
+
+func (f *FastBackend) Delete(c context.Context, identifier string) error {
+	c, span := start(c, f.kind, "delete", identifier)
+	err := f.backend.Delete(c, identifier)
+	finish(span, err)
+	return err
+}
+
+// OnExpire registers fn with the wrapped backend, if it removes expired data lazily
+func (f *FastBackend) OnExpire(fn func(c context.Context, identifier string)) {
+	if n, ok := f.backend.(app.ExpiryNotifier); ok {
+		n.OnExpire(fn)
+	}
+}
+
+func (f *FastBackend) Close() error {
+	return f.backend.Close()
+}
+
+// tracedReader keeps the stream span open until the reader is closed
+type tracedReader struct {
+	io.ReadCloser
+	span trace.Span
+	n    int64
+	err  error
+}
+
+func (t *tracedReader) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	t.n += int64(n)
+	if err != nil && err != io.EOF {
+		t.err = err
+	}
+	return n, err
+}
+
+func (t *tracedReader) Close() error {
+	err := t.ReadCloser.Close()
+	t.span.SetAttributes(bytesKey.Int64(t.n))
+	if t.err == nil {
+		t.err = err
+	}
+	finish(t.span, t.err)
+	return err
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/zllovesuki/b/app"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func getRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func attributes(s sdktrace.ReadOnlySpan) map[string]string {
+	m := map[string]string{}
+	for _, kv := range s.Attributes() {
+		m[string(kv.Key)] = kv.Value.Emit()
+	}
+	return m
+}
+
+func TestBackendSpans(t *testing.T) {
+	recorder := getRecorder(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBackend := app.NewMockRemovableBackend(ctrl)
+	b := NewBackend("sqlite", mockBackend)
+
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+
+	mockBackend.EXPECT().Retrieve(gomock.Any(), "a").Return(nil, app.ErrNotFound)
+	mockBackend.EXPECT().Delete(gomock.Any(), "b").Return(fmt.Errorf("error"))
+
+	_, err := b.Retrieve(ctx, "a")
+	require.ErrorIs(t, err, app.ErrNotFound)
+	require.Error(t, b.Delete(ctx, "b"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "sqlite.retrieve", spans[0].Name())
+	attrs := attributes(spans[0])
+	require.Equal(t, "req-1", attrs[string(RequestIDKey)])
+	require.Equal(t, "a", attrs[string(identifierKey)])
+	require.Equal(t, "not_found", attrs[string(resultKey)])
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	require.Equal(t, "sqlite.delete", spans[1].Name())
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestFastBackendStreamSpan(t *testing.T) {
+	recorder := getRecorder(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBackend := app.NewMockRemovableFastBackend(ctrl)
+	f := NewFastBackend("s3", mockBackend)
+
+	mockBackend.EXPECT().Retrieve(gomock.Any(), "a").Return(io.NopCloser(bytes.NewBufferString("hello")), nil)
+
+	r, err := f.Retrieve(context.Background(), "a")
+	require.NoError(t, err)
+	require.Len(t, recorder.Ended(), 1)
+
+	buf, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(buf))
+	require.NoError(t, r.Close())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "s3.stream", spans[1].Name())
+	require.Equal(t, "5", attributes(spans[1])[string(bytesKey)])
+}
@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/pkg/errors"
+)
+
+const instrumentationName = "github.com/zllovesuki/b"
+
+// available exporters
+const (
+	ExporterNone   = ""
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Config describes where spans should be exported to
+type Config struct {
+	// Exporter is one of otlp, stdout or file. Tracing is disabled when empty
+	Exporter string
+	// Endpoint is the host:port of the OTLP/HTTP collector
+	Endpoint string
+	// Insecure disables TLS when talking to the OTLP/HTTP collector
+	Insecure bool
+	// Path is the file to which spans are appended when using the file exporter
+	Path string
+	// SampleRatio is the fraction of new traces to be sampled. Defaults to always sample
+	SampleRatio float64
+}
+
+func (c Config) validate() error {
+	switch c.Exporter {
+	case ExporterNone, ExporterStdout:
+	case ExporterOTLP:
+		if c.Endpoint == "" {
+			return errors.New("otlp exporter requires an endpoint")
+		}
+	case ExporterFile:
+		if c.Path == "" {
+			return errors.New("file exporter requires a path")
+		}
+	default:
+		return errors.Errorf("unknown tracing exporter: %s", c.Exporter)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.New("sample ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup configures the global tracer provider according to conf and returns
+// a function to flush and stop the exporter before exit
+func Setup(conf Config) (func(context.Context) error, error) {
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	if conf.Exporter == ExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   io.Closer
+		err      error
+	)
+	switch conf.Exporter {
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(conf.Endpoint),
+		}
+		if conf.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		if err := os.MkdirAll(filepath.Dir(conf.Path), 0750); err != nil {
+			return nil, errors.Wrap(err, "creating directory for trace file")
+		}
+		var f *os.File
+		f, err = os.OpenFile(conf.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, errors.Wrap(err, "opening trace file")
+		}
+		closer = f
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "creating span exporter")
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if conf.SampleRatio > 0 && conf.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(conf.SampleRatio)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String("b"),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(c context.Context) error {
+		err := provider.Shutdown(c)
+		if closer != nil {
+			closer.Close()
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of webhook requests
+const (
+	HeaderEvent     = "X-B-Event"
+	HeaderDelivery  = "X-B-Delivery"
+	HeaderTimestamp = "X-B-Timestamp"
+	HeaderSignature = "X-B-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the signature of the payload sent at timestamp, as set in X-B-Signature.
+// The signature is the hex encoded HMAC-SHA256 of "{timestamp}.{payload}" keyed by the secret
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches the payload sent at timestamp, as received in
+// X-B-Timestamp, and whether the timestamp is within tolerance of now to prevent replays
+func Verify(secret, timestamp, signature string, payload []byte, tolerance time.Duration) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	sent := time.Unix(unix, 0)
+	if d := time.Since(sent); d > tolerance || d < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, sent, payload)))
+}
@@ -0,0 +1,409 @@
+// Package webhook delivers events of saved items to HTTP endpoints
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/service"
+	"github.com/zllovesuki/b/validator"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	keyPrefix = "webhook-"
+
+	defaultAttempts      = 5
+	defaultBackoff       = time.Second
+	defaultRetryInterval = 5 * time.Minute
+	defaultRetention     = 7 * 24 * time.Hour
+	defaultTimeout       = 10 * time.Second
+
+	queueSize = 256
+	workers   = 4
+)
+
+// Endpoint receives events
+type Endpoint struct {
+	URL string
+	// Secret signs payloads in X-B-Signature. Payloads are not signed if empty
+	Secret string
+	// Events filters event types with patterns such as "file.*" or "*.expired". Every event is sent if empty
+	Events []string
+}
+
+// wants reports whether the endpoint subscribes to the event type
+func (e Endpoint) wants(eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, pattern := range e.Events {
+		if ok, _ := path.Match(pattern, eventType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type Options struct {
+	Endpoints []Endpoint
+	// Backend persists deliveries which failed every attempt, such that they are retried later and across restarts
+	Backend app.RemovableBackend
+	Logger  *zap.Logger
+	// Client sends the requests. Defaults to a client with 10 seconds timeout
+	Client *http.Client
+	// Attempts of each delivery before it is persisted. Defaults to 5
+	Attempts int
+	// Backoff is the delay before the first retry, doubled after every attempt. Defaults to 1 second
+	Backoff time.Duration
+	// RetryInterval is how often persisted deliveries are retried. Defaults to 5 minutes
+	RetryInterval time.Duration
+	// Retention is how long failed deliveries are kept and retried. Defaults to 7 days
+	Retention time.Duration
+}
+
+func (o *Options) validate() error {
+	if len(o.Endpoints) == 0 {
+		return errors.New("missing endpoints")
+	}
+	for _, e := range o.Endpoints {
+		if !validator.URL(e.URL) {
+			return errors.Errorf("endpoint %s is not a valid URL", e.URL)
+		}
+		for _, pattern := range e.Events {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return errors.Wrapf(err, "invalid event pattern %s of endpoint %s", pattern, e.URL)
+			}
+		}
+	}
+	if o.Backend == nil {
+		return errors.New("missing backend")
+	}
+	if o.Logger == nil {
+		return errors.New("missing logger")
+	}
+	if o.Attempts < 0 || o.Backoff < 0 || o.RetryInterval < 0 || o.Retention < 0 {
+		return errors.New("attempts, backoff, retry interval and retention cannot be negative")
+	}
+	return nil
+}
+
+// Payload is the JSON body of webhook requests
+type Payload struct {
+	// ID identifies the event, and is identical across retries so receivers can ignore duplicates
+	ID string `json:"id"`
+	// Event describes the item and what happened to it
+	Event service.Event `json:"event"`
+}
+
+// delivery is a payload pending delivery to an endpoint
+type delivery struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	URL      string          `json:"url"`
+	Payload  json.RawMessage `json:"payload"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error,omitempty"`
+	// Failed is when the delivery was first persisted
+	Failed time.Time `json:"failed"`
+}
+
+func (d *delivery) key() string {
+	sum := sha256.Sum256([]byte(d.URL))
+	return keyPrefix + d.ID + "-" + hex.EncodeToString(sum[:4])
+}
+
+// Dispatcher delivers events to endpoints in the background, retrying with exponential backoff.
+// Deliveries failing every attempt are persisted in the backend and periodically retried
+type Dispatcher struct {
+	Options
+	endpoints map[string]Endpoint
+	queue     chan *delivery
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	once      sync.Once
+}
+
+var _ service.Events = &Dispatcher{}
+
+// NewDispatcher starts delivering events emitted to the endpoints
+func NewDispatcher(option Options) (*Dispatcher, error) {
+	if err := option.validate(); err != nil {
+		return nil, err
+	}
+	if option.Client == nil {
+		option.Client = &http.Client{Timeout: defaultTimeout}
+	}
+	if option.Attempts == 0 {
+		option.Attempts = defaultAttempts
+	}
+	if option.Backoff == 0 {
+		option.Backoff = defaultBackoff
+	}
+	if option.RetryInterval == 0 {
+		option.RetryInterval = defaultRetryInterval
+	}
+	if option.Retention == 0 {
+		option.Retention = defaultRetention
+	}
+
+	endpoints := make(map[string]Endpoint, len(option.Endpoints))
+	for _, e := range option.Endpoints {
+		endpoints[e.URL] = e
+	}
+	d := &Dispatcher{
+		Options:   option,
+		endpoints: endpoints,
+		queue:     make(chan *delivery, queueSize),
+		stop:      make(chan struct{}),
+	}
+
+	d.wg.Add(workers + 1)
+	for i := 0; i < workers; i++ {
+		go d.work()
+	}
+	go d.retryLoop()
+
+	return d, nil
+}
+
+func newID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Emit queues the event for delivery to the endpoints subscribing to it. When the queue is full,
+// the event is persisted to be retried later instead of blocking the caller
+func (d *Dispatcher) Emit(c context.Context, e service.Event) {
+	id, err := newID()
+	if err != nil {
+		d.Logger.Error("unable to generate event id", zap.Error(err))
+		return
+	}
+	payload, err := json.Marshal(Payload{
+		ID:    id,
+		Event: e,
+	})
+	if err != nil {
+		d.Logger.Error("unable to encode event", zap.Error(err))
+		return
+	}
+
+	for _, endpoint := range d.Endpoints {
+		if !endpoint.wants(e.Type) {
+			continue
+		}
+		dl := &delivery{
+			ID:      id,
+			Type:    e.Type,
+			URL:     endpoint.URL,
+			Payload: payload,
+		}
+		select {
+		case <-d.stop:
+			dl.Error = "dispatcher closed"
+			d.persist(c, dl)
+			continue
+		default:
+		}
+		select {
+		case d.queue <- dl:
+		default:
+			dl.Error = "queue full"
+			d.persist(c, dl)
+		}
+	}
+}
+
+// deliver sends the payload once, returning an error unless the endpoint responds with 2xx
+func (d *Dispatcher) deliver(c context.Context, dl *delivery) error {
+	endpoint, ok := d.endpoints[dl.URL]
+	if !ok {
+		return errors.Errorf("endpoint %s is no longer configured", dl.URL)
+	}
+	req, err := http.NewRequestWithContext(c, http.MethodPost, dl.URL, bytes.NewReader(dl.Payload))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "b-webhook")
+	req.Header.Set(HeaderEvent, dl.Type)
+	req.Header.Set(HeaderDelivery, dl.ID)
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(now.Unix()))
+	if endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(endpoint.Secret, now, dl.Payload))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "sending request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case dl := <-d.queue:
+			d.attempt(dl)
+		}
+	}
+}
+
+// attempt delivers with exponential backoff, persisting the delivery once attempts are exhausted
+func (d *Dispatcher) attempt(dl *delivery) {
+	backoff := d.Backoff
+	for {
+		err := d.deliver(context.Background(), dl)
+		dl.Attempts++
+		if err == nil {
+			return
+		}
+		dl.Error = err.Error()
+		if dl.Attempts >= d.Attempts {
+			d.persist(context.Background(), dl)
+			return
+		}
+		select {
+		case <-d.stop:
+			d.persist(context.Background(), dl)
+			return
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+// persist saves the failed delivery to be retried, until retention since it first failed
+func (d *Dispatcher) persist(c context.Context, dl *delivery) {
+	logger := d.Logger.With(zap.String("event", dl.ID), zap.String("type", dl.Type), zap.String("url", dl.URL))
+	if dl.Failed.IsZero() {
+		dl.Failed = time.Now().UTC().Truncate(time.Second)
+	}
+	if err := d.Backend.Delete(c, dl.key()); err != nil && !errors.Is(err, app.ErrNotFound) {
+		logger.Error("unable to remove previous failed delivery", zap.Error(err))
+		return
+	}
+	ttl := d.Retention - time.Since(dl.Failed)
+	if ttl <= 0 {
+		logger.Warn("giving up on webhook delivery", zap.Int("attempts", dl.Attempts), zap.String("error", dl.Error))
+		return
+	}
+	buf, err := json.Marshal(dl)
+	if err != nil {
+		logger.Error("unable to encode failed delivery", zap.Error(err))
+		return
+	}
+	if err := d.Backend.SaveTTL(c, dl.key(), buf, ttl); err != nil {
+		logger.Error("unable to persist failed delivery", zap.Error(err))
+		return
+	}
+	logger.Warn("webhook delivery failed, retrying later", zap.Int("attempts", dl.Attempts), zap.String("error", dl.Error))
+}
+
+func (d *Dispatcher) retryLoop() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(d.RetryInterval)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), d.RetryInterval)
+		if err := d.Redeliver(ctx); err != nil {
+			d.Logger.Error("unable to retry failed webhook deliveries", zap.Error(err))
+		}
+		cancel()
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Redeliver attempts each persisted delivery once, removing those delivered or whose endpoint is no longer configured
+func (d *Dispatcher) Redeliver(c context.Context) error {
+	cursor := ""
+	for {
+		items, next, err := d.Backend.List(c, keyPrefix, cursor, 100)
+		if err != nil {
+			return errors.Wrap(err, "listing failed deliveries")
+		}
+		for _, item := range items {
+			select {
+			case <-d.stop:
+				return nil
+			default:
+			}
+			buf, err := d.Backend.Retrieve(c, item.ID)
+			if errors.Is(err, app.ErrNotFound) {
+				continue
+			} else if err != nil {
+				return errors.Wrap(err, "retrieving failed delivery")
+			}
+			var dl delivery
+			if err := json.Unmarshal(buf, &dl); err != nil {
+				d.Logger.Error("removing undecodable failed delivery", zap.String("key", item.ID), zap.Error(err))
+				d.Backend.Delete(c, item.ID)
+				continue
+			}
+			if _, ok := d.endpoints[dl.URL]; !ok {
+				d.Logger.Info("removing failed delivery to unconfigured endpoint", zap.String("event", dl.ID), zap.String("url", dl.URL))
+				d.Backend.Delete(c, item.ID)
+				continue
+			}
+			err = d.deliver(c, &dl)
+			dl.Attempts++
+			if err == nil {
+				if err := d.Backend.Delete(c, item.ID); err != nil && !errors.Is(err, app.ErrNotFound) {
+					return errors.Wrap(err, "removing redelivered delivery")
+				}
+				continue
+			}
+			dl.Error = err.Error()
+			d.persist(c, &dl)
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Close stops delivering, persisting queued deliveries such that they are retried after restart
+func (d *Dispatcher) Close() {
+	d.once.Do(func() {
+		close(d.stop)
+		d.wg.Wait()
+		for {
+			select {
+			case dl := <-d.queue:
+				dl.Error = "dispatcher closed"
+				d.persist(context.Background(), dl)
+			default:
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zllovesuki/b/app"
+	"github.com/zllovesuki/b/backend"
+	"github.com/zllovesuki/b/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const testSecret = "secret"
+
+// receiver records webhook requests, failing the first failures of them
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	failures int
+	payloads []Payload
+	headers  []http.Header
+}
+
+func newReceiver(t *testing.T, failures int) *receiver {
+	rc := &receiver{
+		failures: failures,
+	}
+	rc.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		if rc.failures > 0 {
+			rc.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.True(t, Verify(testSecret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute))
+		var p Payload
+		require.NoError(t, json.Unmarshal(body, &p))
+		rc.payloads = append(rc.payloads, p)
+		rc.headers = append(rc.headers, r.Header.Clone())
+	}))
+	return rc
+}
+
+func (rc *receiver) received() []Payload {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]Payload{}, rc.payloads...)
+}
+
+func (rc *receiver) setFailures(n int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.failures = n
+}
+
+func getBackend(t *testing.T) (app.RemovableBackend, func()) {
+	b, err := backend.NewSQLiteBackend(filepath.Join(t.TempDir(), "webhook.db"))
+	require.NoError(t, err)
+	return b, func() {
+		require.NoError(t, b.Close())
+	}
+}
+
+func persisted(t *testing.T, b app.Backend) []app.Info {
+	items, _, err := b.List(context.Background(), keyPrefix, "", 100)
+	require.NoError(t, err)
+	return items
+}
+
+func TestDeliver(t *testing.T) {
+	b, finish := getBackend(t)
+	defer finish()
+
+	all := newReceiver(t, 0)
+	defer all.Close()
+	expired := newReceiver(t, 0)
+	defer expired.Close()
+
+	d, err := NewDispatcher(Options{
+		Endpoints: []Endpoint{
+			{URL: all.URL, Secret: testSecret},
+			{URL: expired.URL, Secret: testSecret, Events: []string{"*.expired"}},
+		},
+		Backend: b,
+		Logger:  zaptest.NewLogger(t),
+	})
+	require.NoError(t, err)
+	defer d.Close()
+
+	d.Emit(context.Background(), service.NewEvent("file", service.ActionCreated, "hello", "http://b/f-hello"))
+	d.Emit(context.Background(), service.NewEvent("text", service.ActionExpired, "world", "http://b/t-world"))
+
+	require.Eventually(t, func() bool {
+		return len(all.received()) == 2 && len(expired.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	p := expired.received()[0]
+	require.Equal(t, "text.expired", p.Event.Type)
+	require.Equal(t, "world", p.Event.ID)
+	require.NotEmpty(t, p.ID)
+	require.Equal(t, "text.expired", expired.headers[0].Get(HeaderEvent))
+	require.Equal(t, p.ID, expired.headers[0].Get(HeaderDelivery))
+	require.Empty(t, persisted(t, b))
+}
+
+func TestRetry(t *testing.T) {
+	b, finish := getBackend(t)
+	defer finish()
+
+	rc := newReceiver(t, 2)
+	defer rc.Close()
+
+	d, err := NewDispatcher(Options{
+		Endpoints: []Endpoint{{URL: rc.URL, Secret: testSecret}},
+		Backend:   b,
+		Logger:    zaptest.NewLogger(t),
+		Attempts:  3,
+		Backoff:   10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer d.Close()
+
+	d.Emit(context.Background(), service.NewEvent("link", service.ActionCreated, "hello", "http://b/l-hello"))
+
+	require.Eventually(t, func() bool {
+		return len(rc.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Empty(t, persisted(t, b))
+}
+
+func TestPersistFailedDelivery(t *testing.T) {
+	b, finish := getBackend(t)
+	defer finish()
+
+	rc := newReceiver(t, 1000)
+	defer rc.Close()
+
+	options := Options{
+		Endpoints:     []Endpoint{{URL: rc.URL, Secret: testSecret}},
+		Backend:       b,
+		Logger:        zaptest.NewLogger(t),
+		Attempts:      2,
+		Backoff:       10 * time.Millisecond,
+		RetryInterval: time.Hour,
+	}
+	d, err := NewDispatcher(options)
+	require.NoError(t, err)
+
+	d.Emit(context.Background(), service.NewEvent("file", service.ActionDeleted, "hello", "http://b/f-hello"))
+
+	require.Eventually(t, func() bool {
+		return len(persisted(t, b)) == 1
+	}, time.Second, 10*time.Millisecond)
+	d.Close()
+
+	buf, err := b.Retrieve(context.Background(), persisted(t, b)[0].ID)
+	require.NoError(t, err)
+	var dl delivery
+	require.NoError(t, json.Unmarshal(buf, &dl))
+	require.Equal(t, 2, dl.Attempts)
+	require.Equal(t, "unexpected status 503", dl.Error)
+
+	// persisted deliveries are retried after restart
+	rc.setFailures(0)
+	d, err = NewDispatcher(options)
+	require.NoError(t, err)
+	defer d.Close()
+
+	require.Eventually(t, func() bool {
+		return len(rc.received()) == 1 && len(persisted(t, b)) == 0
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "file.deleted", rc.received()[0].Event.Type)
+	require.Equal(t, dl.ID, rc.received()[0].ID)
+}
+
+func TestRedeliverRemovedEndpoint(t *testing.T) {
+	b, finish := getBackend(t)
+	defer finish()
+
+	dl := &delivery{
+		ID:      "removed",
+		Type:    "file.created",
+		URL:     "http://removed.example.com/",
+		Payload: json.RawMessage(`{}`),
+	}
+	buf, err := json.Marshal(dl)
+	require.NoError(t, err)
+	require.NoError(t, b.SaveTTL(context.Background(), dl.key(), buf, time.Hour))
+
+	d, err := NewDispatcher(Options{
+		Endpoints:     []Endpoint{{URL: "http://other.example.com/"}},
+		Backend:       b,
+		Logger:        zaptest.NewLogger(t),
+		RetryInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	defer d.Close()
+
+	require.NoError(t, d.Redeliver(context.Background()))
+	require.Empty(t, persisted(t, b))
+}
+
+func TestVerify(t *testing.T) {
+	payload := []byte(`{"id":"hello"}`)
+	now := time.Now()
+	signature := Sign(testSecret, now, payload)
+
+	ts := func(t time.Time) string {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+
+	require.True(t, Verify(testSecret, ts(now), signature, payload, time.Minute))
+	require.False(t, Verify("other", ts(now), signature, payload, time.Minute))
+	require.False(t, Verify(testSecret, ts(now), signature, []byte(`{}`), time.Minute))
+	require.False(t, Verify(testSecret, ts(now.Add(time.Hour)), signature, payload, time.Minute))
+
+	old := now.Add(-time.Hour)
+	require.False(t, Verify(testSecret, ts(old), Sign(testSecret, old, payload), payload, time.Minute))
+}